@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	protov1 "github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// redactedValue replaces values of redacted fields and metadata
+	redactedValue = "[REDACTED]"
+	// accessLogMaxResponses limits the number of response messages recorded per call
+	accessLogMaxResponses = 100
+)
+
+// accessLogEntry is a single line of the access log
+type accessLogEntry struct {
+	Time     time.Time           `json:"time"`
+	Method   string              `json:"method"`
+	Peer     string              `json:"peer,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+	Request  json.RawMessage     `json:"request,omitempty"`
+	MockID   string              `json:"mock_id,omitempty"`
+	Outcome  string              `json:"outcome"`
+	// Responses are the messages sent to the client
+	Responses        []json.RawMessage `json:"responses,omitempty"`
+	ResponsesDropped int               `json:"responses_dropped,omitempty"`
+	Code             string            `json:"code"`
+	Message          string            `json:"message,omitempty"`
+	DurationMs       float64           `json:"duration_ms"`
+	// Truncated is set if any of the bodies exceeds the size limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// accessLog writes mock calls as json lines
+type accessLog struct {
+	mu  sync.Mutex
+	out io.Writer
+	// maxBody is the size limit of request and response bodies, bodies are not limited if it's 0
+	maxBody int
+	redact  redactionRules
+}
+
+// openAccessLog opens the destination of the access log: stdout, stderr or a file path,
+// the returned function flushes and closes the file, standard streams are kept open
+func openAccessLog(dest string) (io.Writer, func() error, error) {
+	switch dest {
+	case "stdout":
+		return os.Stdout, func() error { return nil }, nil
+	case "stderr":
+		return os.Stderr, func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, func() error {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}, nil
+}
+
+func newAccessLog(out io.Writer, maxBody int, redact []string) *accessLog {
+	return &accessLog{
+		out:     out,
+		maxBody: maxBody,
+		redact:  newRedactionRules(redact),
+	}
+}
+
+// streamInterceptor records calls handled by mocks, admin api and reflection calls are not logged
+func (l *accessLog) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, call := withCallInfo(ss.Context())
+	recorder := &recordingStream{ServerStream: &contextStream{ServerStream: ss, ctx: ctx}}
+
+	start := time.Now()
+	err := handler(srv, recorder)
+	if call.outcome == "" {
+		return err
+	}
+
+	entry := accessLogEntry{
+		Time:       start.UTC(),
+		Method:     info.FullMethod,
+		MockID:     call.mockID,
+		Outcome:    call.outcome,
+		Code:       status.Code(err).String(),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		entry.Message = status.Convert(err).Message()
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		entry.Peer = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		entry.Metadata = l.redact.metadata(md)
+	}
+	if len(call.request) > 0 {
+		entry.Request = l.body(call.request, &entry.Truncated)
+	}
+
+	responses, dropped := recorder.messages()
+	for _, r := range responses {
+		entry.Responses = append(entry.Responses, l.body(r, &entry.Truncated))
+	}
+	entry.ResponsesDropped = dropped
+
+	l.write(entry)
+	return err
+}
+
+// body redacts the fields and applies the size limit, bodies exceeding the limit
+// are logged as truncated json strings
+func (l *accessLog) body(js []byte, truncated *bool) json.RawMessage {
+	js = l.redact.body(js)
+	if l.maxBody <= 0 || len(js) <= l.maxBody {
+		return js
+	}
+
+	*truncated = true
+	s, _ := json.Marshal(string(js[:l.maxBody]) + "...")
+	return s
+}
+
+func (l *accessLog) write(entry accessLogEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"method": entry.Method, "error": err.Error()})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = l.out.Write(append(line, '\n'))
+}
+
+// recordingStream keeps json of the messages sent to the client
+type recordingStream struct {
+	grpc.ServerStream
+
+	mu      sync.Mutex
+	sent    [][]byte
+	dropped int
+}
+
+func (s *recordingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.sent) >= accessLogMaxResponses {
+		s.dropped++
+		return nil
+	}
+
+	js, jsErr := messageJSON(m)
+	if jsErr != nil {
+		js, _ = json.Marshal(fmt.Sprintf("message json marshaling: %s", jsErr))
+	}
+	s.sent = append(s.sent, js)
+	return nil
+}
+
+func (s *recordingStream) messages() ([][]byte, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sent, s.dropped
+}
+
+// messageJSON marshals dynamic and generated messages using original field names like the requests
+func messageJSON(m interface{}) ([]byte, error) {
+	switch msg := m.(type) {
+	case jsonpb.JSONPBMarshaler:
+		return msg.MarshalJSONPB(&jsonpb.Marshaler{OrigName: true})
+	case proto.Message:
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	case protov1.Message:
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(protov1.MessageV2(msg))
+	}
+	return nil, fmt.Errorf("unsupported message type %T", m)
+}
+
+// redactionRules replace values of sensitive fields. A rule without dots matches the field
+// and the metadata key at any depth, a dotted path like user.password matches from the root,
+// array elements are matched by the path of the array
+type redactionRules struct {
+	names map[string]struct{}
+	paths map[string]struct{}
+	// keys are lowercased names, metadata keys are case insensitive
+	keys map[string]struct{}
+}
+
+func newRedactionRules(rules []string) redactionRules {
+	r := redactionRules{
+		names: map[string]struct{}{},
+		paths: map[string]struct{}{},
+		keys:  map[string]struct{}{},
+	}
+
+	for _, rule := range rules {
+		rule = strings.TrimPrefix(strings.TrimSpace(rule), "$.")
+		switch {
+		case rule == "":
+		case strings.Contains(rule, "."):
+			r.paths[rule] = struct{}{}
+		default:
+			r.names[rule] = struct{}{}
+			r.keys[strings.ToLower(rule)] = struct{}{}
+		}
+	}
+
+	return r
+}
+
+func (r redactionRules) empty() bool {
+	return len(r.names) == 0 && len(r.paths) == 0
+}
+
+func (r redactionRules) metadata(md metadata.MD) map[string][]string {
+	result := make(map[string][]string, len(md))
+	for k, v := range md {
+		if _, ok := r.keys[strings.ToLower(k)]; ok {
+			v = []string{redactedValue}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func (r redactionRules) body(js []byte) []byte {
+	if r.empty() {
+		return js
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return js
+	}
+
+	redacted, err := json.Marshal(r.value("", val))
+	if err != nil {
+		return js
+	}
+	return redacted
+}
+
+func (r redactionRules) value(path string, val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+
+			_, byName := r.names[k]
+			_, byPath := r.paths[fieldPath]
+			if byName || byPath {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = r.value(fieldPath, field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.value(path, item)
+		}
+	}
+	return val
+}
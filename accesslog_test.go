@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/testdata/greet"
+	"github.com/vadimi/grpc-ditto/testdata/hello"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// syncBuffer is written by the server and read by the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+func TestMockServerAccessLog(t *testing.T) {
+	log := logger.NewLogger()
+
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+		logger: log,
+	}
+
+	bob := greetMock()
+	bob.ID = "bob"
+	s.matcher, err = dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{bob, helloStreamMock()}), dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	out := &syncBuffer{}
+	accessLog := newAccessLog(out, 22, []string{"authorization", "name"})
+	server := grpc.NewServer(grpc.ChainStreamInterceptor(accessLog.streamInterceptor))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+	defer stopTestServer(server)
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret", "x-run", "42")
+	_, err = greet.NewGreeterClient(cc).SayHello(ctx, &greet.HelloRequest{Name: "Bob"})
+	require.NoError(t, err)
+
+	stream, err := hello.NewHelloServiceClient(cc).Hello(context.Background(), &hello.HelloRequest{Name: "all"})
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+
+	require.Eventually(t, func() bool { return len(out.lines()) == 2 }, time.Second, 10*time.Millisecond)
+
+	var unary, streaming accessLogEntry
+	lines := out.lines()
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &unary))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &streaming))
+
+	assert.Equal(t, "/greet.Greeter/SayHello", unary.Method)
+	assert.Equal(t, "bob", unary.MockID)
+	assert.Equal(t, outcomeMatched, unary.Outcome)
+	assert.Equal(t, "OK", unary.Code)
+	assert.NotEmpty(t, unary.Peer)
+	assert.Equal(t, []string{redactedValue}, unary.Metadata["authorization"])
+	assert.Equal(t, []string{"42"}, unary.Metadata["x-run"])
+	assert.JSONEq(t, `{"name": "[REDACTED]"}`, string(unary.Request))
+	require.Len(t, unary.Responses, 1)
+	// the response exceeds the size limit
+	assert.True(t, unary.Truncated)
+	assert.Equal(t, `"{\"message\":\"hello Bob\"..."`, string(unary.Responses[0]))
+
+	assert.Equal(t, "/ditto.example.HelloService/Hello", streaming.Method)
+	assert.Len(t, streaming.Responses, 2)
+	assert.GreaterOrEqual(t, streaming.DurationMs, 0.0)
+}
+
+func TestOpenAccessLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	out, closeLog, err := openAccessLog(path)
+	require.NoError(t, err)
+
+	_, err = fmt.Fprintln(out, `{"method":"/greet.Greeter/SayHello"}`)
+	require.NoError(t, err)
+	require.NoError(t, closeLog())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"method\":\"/greet.Greeter/SayHello\"}\n", string(data))
+
+	_, err = fmt.Fprintln(out, "closed")
+	assert.Error(t, err, "file is closed")
+
+	out, closeLog, err = openAccessLog("stdout")
+	require.NoError(t, err)
+	require.NoError(t, closeLog())
+	assert.Same(t, os.Stdout, out)
+}
+
+func TestRedactionRules(t *testing.T) {
+	rules := newRedactionRules([]string{"password", "$.card.number", " "})
+
+	redacted := rules.body([]byte(`{
+		"user": {"login": "bob", "password": "secret"},
+		"card": {"number": "4111", "cvv": 1},
+		"items": [{"card": {"number": "1"}}],
+		"amount": 12345678901234567890
+	}`))
+
+	assert.JSONEq(t, `{
+		"user": {"login": "bob", "password": "[REDACTED]"},
+		"card": {"number": "[REDACTED]", "cvv": 1},
+		"items": [{"card": {"number": "1"}}],
+		"amount": 12345678901234567890
+	}`, string(redacted))
+
+	assert.Equal(t, []byte(`not json`), rules.body([]byte(`not json`)))
+
+	rules = newRedactionRules([]string{"Authorization"})
+	assert.Equal(t, map[string][]string{
+		"authorization": {"[REDACTED]"},
+		"x-request-id":  {"1"},
+	}, rules.metadata(metadata.Pairs("authorization", "Bearer token", "x-request-id", "1")))
+}
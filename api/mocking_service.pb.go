@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.34.2
+// 	protoc-gen-go v1.36.8
 // 	protoc        v3.21.9
 // source: mocking_service.proto
 
@@ -10,9 +10,12 @@ import (
 	code "google.golang.org/genproto/googleapis/rpc/code"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -23,20 +26,17 @@ const (
 )
 
 type AddMockRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mock          *DittoMock             `protobuf:"bytes,1,opt,name=mock,proto3" json:"mock,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Mock *DittoMock `protobuf:"bytes,1,opt,name=mock,proto3" json:"mock,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AddMockRequest) Reset() {
 	*x = AddMockRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_mocking_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AddMockRequest) String() string {
@@ -47,7 +47,7 @@ func (*AddMockRequest) ProtoMessage() {}
 
 func (x *AddMockRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mocking_service_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -70,18 +70,16 @@ func (x *AddMockRequest) GetMock() *DittoMock {
 }
 
 type AddMockResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AddMockResponse) Reset() {
 	*x = AddMockResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_mocking_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *AddMockResponse) String() string {
@@ -92,7 +90,7 @@ func (*AddMockResponse) ProtoMessage() {}
 
 func (x *AddMockResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mocking_service_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -108,21 +106,29 @@ func (*AddMockResponse) Descriptor() ([]byte, []int) {
 }
 
 type DittoMock struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Request  *DittoRequest          `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Response []*DittoResponse       `protobuf:"bytes,2,rep,name=response,proto3" json:"response,omitempty"`
+	// optional mock identifier used in diagnostics,
+	// file name and mock index are used for mocks loaded from files if it's not set
+	Id string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// server streaming: keeps the stream open after the responses are sent
+	Stream *StreamOptions `protobuf:"bytes,4,opt,name=stream,proto3" json:"stream,omitempty"`
+	// alternative responses, one of them is chosen randomly by weight for every call,
+	// it can't be used together with response
+	Responses []*WeightedResponse `protobuf:"bytes,5,rep,name=responses,proto3" json:"responses,omitempty"`
+	// delay before the responses are sent like ``2s``,
+	// the call fails with DEADLINE_EXCEEDED if the client deadline comes first
+	Delay         string `protobuf:"bytes,6,opt,name=delay,proto3" json:"delay,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Request  *DittoRequest    `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
-	Response []*DittoResponse `protobuf:"bytes,2,rep,name=response,proto3" json:"response,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DittoMock) Reset() {
 	*x = DittoMock{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_mocking_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DittoMock) String() string {
@@ -133,7 +139,7 @@ func (*DittoMock) ProtoMessage() {}
 
 func (x *DittoMock) ProtoReflect() protoreflect.Message {
 	mi := &file_mocking_service_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -162,27 +168,215 @@ func (x *DittoMock) GetResponse() []*DittoResponse {
 	return nil
 }
 
+func (x *DittoMock) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DittoMock) GetStream() *StreamOptions {
+	if x != nil {
+		return x.Stream
+	}
+	return nil
+}
+
+func (x *DittoMock) GetResponses() []*WeightedResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *DittoMock) GetDelay() string {
+	if x != nil {
+		return x.Delay
+	}
+	return ""
+}
+
+type WeightedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// relative weight of the alternative, 1 if it's not set, 0 disables the alternative
+	Weight        *uint32          `protobuf:"varint,1,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	Response      []*DittoResponse `protobuf:"bytes,2,rep,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeightedResponse) Reset() {
+	*x = WeightedResponse{}
+	mi := &file_mocking_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeightedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeightedResponse) ProtoMessage() {}
+
+func (x *WeightedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeightedResponse.ProtoReflect.Descriptor instead.
+func (*WeightedResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WeightedResponse) GetWeight() uint32 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *WeightedResponse) GetResponse() []*DittoResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+// StreamOptions control the lifetime of server streams
+type StreamOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the stream stays open after the responses are sent until the client cancels it
+	KeepOpen bool `protobuf:"varint,1,opt,name=keep_open,json=keepOpen,proto3" json:"keep_open,omitempty"`
+	// heartbeat message is sent periodically while the stream is open, it implies keep_open
+	Heartbeat     *Heartbeat `protobuf:"bytes,2,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOptions) Reset() {
+	*x = StreamOptions{}
+	mi := &file_mocking_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOptions) ProtoMessage() {}
+
+func (x *StreamOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOptions.ProtoReflect.Descriptor instead.
+func (*StreamOptions) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamOptions) GetKeepOpen() bool {
+	if x != nil {
+		return x.KeepOpen
+	}
+	return false
+}
+
+func (x *StreamOptions) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+type Heartbeat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// delay between messages like ``30s``
+	Interval string `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
+	// go template of the message, yaml and json are supported inside of the template,
+	// the template has access to ``.Index`` (starting from 0) and ``.Request``
+	BodyTemplate  string `protobuf:"bytes,2,opt,name=body_template,json=bodyTemplate,proto3" json:"body_template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_mocking_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Heartbeat) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetBodyTemplate() string {
+	if x != nil {
+		return x.BodyTemplate
+	}
+	return ""
+}
+
 // DittoRequest represents request matching object. It matches requests first by method and then by patterns.
 // All patterns must match in order for a request to match.
 // If no matches are found the service will return “Unimplemented“ grpc error.
 // If there multiple matches are found the first one is what gets returned, the other ones will be ignored.
 type DittoRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// fully qualified grpc method like ``/package.full.name.UserService/Update``
+	Method        string              `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	BodyPatterns  []*DittoBodyPattern `protobuf:"bytes,2,rep,name=body_patterns,json=bodyPatterns,proto3" json:"body_patterns,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	// fully qualified grpc method like “/package.full.name.UserService/Update“
-	Method       string              `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
-	BodyPatterns []*DittoBodyPattern `protobuf:"bytes,2,rep,name=body_patterns,json=bodyPatterns,proto3" json:"body_patterns,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DittoRequest) Reset() {
 	*x = DittoRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_mocking_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DittoRequest) String() string {
@@ -192,8 +386,8 @@ func (x *DittoRequest) String() string {
 func (*DittoRequest) ProtoMessage() {}
 
 func (x *DittoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_mocking_service_proto_msgTypes[6]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -205,7 +399,7 @@ func (x *DittoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DittoRequest.ProtoReflect.Descriptor instead.
 func (*DittoRequest) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{3}
+	return file_mocking_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *DittoRequest) GetMethod() string {
@@ -223,25 +417,23 @@ func (x *DittoRequest) GetBodyPatterns() []*DittoBodyPattern {
 }
 
 type DittoResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to Response:
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
 	//
 	//	*DittoResponse_Body
 	//	*DittoResponse_Status
 	//	*DittoResponse_BodyTemplate
-	Response isDittoResponse_Response `protobuf_oneof:"response"`
+	//	*DittoResponse_Generate
+	Response      isDittoResponse_Response `protobuf_oneof:"response"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DittoResponse) Reset() {
 	*x = DittoResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_mocking_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DittoResponse) String() string {
@@ -251,8 +443,8 @@ func (x *DittoResponse) String() string {
 func (*DittoResponse) ProtoMessage() {}
 
 func (x *DittoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_mocking_service_proto_msgTypes[7]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -264,37 +456,52 @@ func (x *DittoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DittoResponse.ProtoReflect.Descriptor instead.
 func (*DittoResponse) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{4}
+	return file_mocking_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (m *DittoResponse) GetResponse() isDittoResponse_Response {
-	if m != nil {
-		return m.Response
+func (x *DittoResponse) GetResponse() isDittoResponse_Response {
+	if x != nil {
+		return x.Response
 	}
 	return nil
 }
 
 func (x *DittoResponse) GetBody() *structpb.Struct {
-	if x, ok := x.GetResponse().(*DittoResponse_Body); ok {
-		return x.Body
+	if x != nil {
+		if x, ok := x.Response.(*DittoResponse_Body); ok {
+			return x.Body
+		}
 	}
 	return nil
 }
 
 func (x *DittoResponse) GetStatus() *RpcStatus {
-	if x, ok := x.GetResponse().(*DittoResponse_Status); ok {
-		return x.Status
+	if x != nil {
+		if x, ok := x.Response.(*DittoResponse_Status); ok {
+			return x.Status
+		}
 	}
 	return nil
 }
 
 func (x *DittoResponse) GetBodyTemplate() string {
-	if x, ok := x.GetResponse().(*DittoResponse_BodyTemplate); ok {
-		return x.BodyTemplate
+	if x != nil {
+		if x, ok := x.Response.(*DittoResponse_BodyTemplate); ok {
+			return x.BodyTemplate
+		}
 	}
 	return ""
 }
 
+func (x *DittoResponse) GetGenerate() *ResponseGenerator {
+	if x != nil {
+		if x, ok := x.Response.(*DittoResponse_Generate); ok {
+			return x.Generate
+		}
+	}
+	return nil
+}
+
 type isDittoResponse_Response interface {
 	isDittoResponse_Response()
 }
@@ -314,39 +521,60 @@ type DittoResponse_BodyTemplate struct {
 	BodyTemplate string `protobuf:"bytes,3,opt,name=body_template,json=bodyTemplate,proto3,oneof"`
 }
 
+type DittoResponse_Generate struct {
+	// generates multiple messages of server streaming response
+	Generate *ResponseGenerator `protobuf:"bytes,4,opt,name=generate,proto3,oneof"`
+}
+
 func (*DittoResponse_Body) isDittoResponse_Response() {}
 
 func (*DittoResponse_Status) isDittoResponse_Response() {}
 
 func (*DittoResponse_BodyTemplate) isDittoResponse_Response() {}
 
-type RpcStatus struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (*DittoResponse_Generate) isDittoResponse_Response() {}
 
-	Code    code.Code `protobuf:"varint,1,opt,name=code,proto3,enum=google.rpc.Code" json:"code,omitempty"`
-	Message string    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+// ResponseGenerator produces messages of server streaming response from a template
+// evaluated for every message. The template has access to “.Index“ (starting from 0),
+// “.Item“ (the element of foreach array), “.Count“ (the number of messages)
+// and “.Request“ (the request json, array of messages for client streaming).
+//
+// Examples
+// ^^^^^^^^
+//
+// { "repeat": 10000, "body_template": "{ \"id\": \"{{.Index}}\" }", "interval": "10ms" }
+// { "foreach": "$.items", "body_template": "{ \"sku\": \"{{.Item.sku}}\", \"done\": true }" }
+type ResponseGenerator struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Source:
+	//
+	//	*ResponseGenerator_Repeat
+	//	*ResponseGenerator_Foreach
+	Source isResponseGenerator_Source `protobuf_oneof:"source"`
+	// go template of the message, yaml and json are supported inside of the template
+	BodyTemplate string `protobuf:"bytes,3,opt,name=body_template,json=bodyTemplate,proto3" json:"body_template,omitempty"`
+	// delay between messages like ``100ms``
+	Interval      string `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RpcStatus) Reset() {
-	*x = RpcStatus{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *ResponseGenerator) Reset() {
+	*x = ResponseGenerator{}
+	mi := &file_mocking_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *RpcStatus) String() string {
+func (x *ResponseGenerator) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RpcStatus) ProtoMessage() {}
+func (*ResponseGenerator) ProtoMessage() {}
 
-func (x *RpcStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ResponseGenerator) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[8]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -356,146 +584,2258 @@ func (x *RpcStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RpcStatus.ProtoReflect.Descriptor instead.
-func (*RpcStatus) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ResponseGenerator.ProtoReflect.Descriptor instead.
+func (*ResponseGenerator) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *RpcStatus) GetCode() code.Code {
+func (x *ResponseGenerator) GetSource() isResponseGenerator_Source {
 	if x != nil {
-		return x.Code
+		return x.Source
 	}
-	return code.Code(0)
+	return nil
 }
 
-func (x *RpcStatus) GetMessage() string {
+func (x *ResponseGenerator) GetRepeat() uint32 {
 	if x != nil {
-		return x.Message
+		if x, ok := x.Source.(*ResponseGenerator_Repeat); ok {
+			return x.Repeat
+		}
 	}
-	return ""
+	return 0
 }
 
-type DittoBodyPattern struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *ResponseGenerator) GetForeach() string {
+	if x != nil {
+		if x, ok := x.Source.(*ResponseGenerator_Foreach); ok {
+			return x.Foreach
+		}
+	}
+	return ""
+}
 
-	// Types that are assignable to Pattern:
-	//
-	//	*DittoBodyPattern_EqualToJson
-	//	*DittoBodyPattern_MatchesJsonpath
-	Pattern isDittoBodyPattern_Pattern `protobuf_oneof:"pattern"`
+func (x *ResponseGenerator) GetBodyTemplate() string {
+	if x != nil {
+		return x.BodyTemplate
+	}
+	return ""
 }
 
-func (x *DittoBodyPattern) Reset() {
-	*x = DittoBodyPattern{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *ResponseGenerator) GetInterval() string {
+	if x != nil {
+		return x.Interval
 	}
+	return ""
 }
 
-func (x *DittoBodyPattern) String() string {
-	return protoimpl.X.MessageStringOf(x)
+type isResponseGenerator_Source interface {
+	isResponseGenerator_Source()
 }
 
-func (*DittoBodyPattern) ProtoMessage() {}
+type ResponseGenerator_Repeat struct {
+	// number of messages
+	Repeat uint32 `protobuf:"varint,1,opt,name=repeat,proto3,oneof"`
+}
 
-func (x *DittoBodyPattern) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+type ResponseGenerator_Foreach struct {
+	// JSONPath expression selecting an array of the request, one message is sent per array element,
+	// one message per value is sent if the expression selects multiple values
+	Foreach string `protobuf:"bytes,2,opt,name=foreach,proto3,oneof"`
 }
 
-// Deprecated: Use DittoBodyPattern.ProtoReflect.Descriptor instead.
-func (*DittoBodyPattern) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{6}
+func (*ResponseGenerator_Repeat) isResponseGenerator_Source() {}
+
+func (*ResponseGenerator_Foreach) isResponseGenerator_Source() {}
+
+type RpcStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          code.Code              `protobuf:"varint,1,opt,name=code,proto3,enum=google.rpc.Code" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RpcStatus) Reset() {
+	*x = RpcStatus{}
+	mi := &file_mocking_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RpcStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RpcStatus) ProtoMessage() {}
+
+func (x *RpcStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RpcStatus.ProtoReflect.Descriptor instead.
+func (*RpcStatus) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RpcStatus) GetCode() code.Code {
+	if x != nil {
+		return x.Code
+	}
+	return code.Code(0)
+}
+
+func (x *RpcStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// DittoBodyPattern matches request body. Combinators can be nested and are combined
+// with the pattern itself, so all of the set conditions must hold.
+//
+// Examples
+// ^^^^^^^^
+//
+// { "any_of": [{ "matches_jsonpath": { "expression": "$.name", "eq": "Bob" } },
+// { "matches_jsonpath": { "expression": "$.name", "eq": "Alice" } }] }
+// { "not": { "matches_jsonpath": { "expression": "$.premium", "eq": "true" } } }
+// { "includes_json": { "user": { "name": "Bob" } }, "ignore_array_order": true }
+// { "matches_cel": "request.name == 'Bob' && 'beta' in metadata['x-features']" }
+// { "matches_field": { "field": "amount", "gt": "9007199254740993" } }
+//
+// Patterns of client streaming requests can be applied to every message of the stream,
+// e.g. a stream of 2 to 10 chunks that ends with a commit message:
+//
+// { "message_count": { "min": 2, "max": 10 },
+// "sequence": [{ "skip": true }, { "message": { "matches_field": { "field": "commit", "eq": "true" } } }] }
+type DittoBodyPattern struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Pattern:
+	//
+	//	*DittoBodyPattern_EqualToJson
+	//	*DittoBodyPattern_MatchesJsonpath
+	//	*DittoBodyPattern_IncludesJson
+	//	*DittoBodyPattern_MatchesCel
+	//	*DittoBodyPattern_MatchesField
+	Pattern isDittoBodyPattern_Pattern `protobuf_oneof:"pattern"`
+	// at least one of the patterns must match
+	AnyOf []*DittoBodyPattern `protobuf:"bytes,3,rep,name=any_of,json=anyOf,proto3" json:"any_of,omitempty"`
+	// all of the patterns must match
+	AllOf []*DittoBodyPattern `protobuf:"bytes,4,rep,name=all_of,json=allOf,proto3" json:"all_of,omitempty"`
+	// the pattern must not match
+	Not *DittoBodyPattern `protobuf:"bytes,5,opt,name=not,proto3" json:"not,omitempty"`
+	// equal_to_json ignores request fields that are not present in the pattern at any level
+	IgnoreExtraFields bool `protobuf:"varint,7,opt,name=ignore_extra_fields,json=ignoreExtraFields,proto3" json:"ignore_extra_fields,omitempty"`
+	// equal_to_json compares arrays ignoring the order of the elements
+	IgnoreArrayOrder bool `protobuf:"varint,8,opt,name=ignore_array_order,json=ignoreArrayOrder,proto3" json:"ignore_array_order,omitempty"`
+	// client streaming: at least one message must match the pattern
+	AnyMessage *DittoBodyPattern `protobuf:"bytes,11,opt,name=any_message,json=anyMessage,proto3" json:"any_message,omitempty"`
+	// client streaming: all messages must match the pattern
+	EveryMessage *DittoBodyPattern `protobuf:"bytes,12,opt,name=every_message,json=everyMessage,proto3" json:"every_message,omitempty"`
+	// client streaming: the number of messages must be in the range
+	MessageCount *MessageCount `protobuf:"bytes,13,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	// client streaming: messages must match the steps in order, the whole stream must be matched
+	Sequence []*SequenceStep `protobuf:"bytes,14,rep,name=sequence,proto3" json:"sequence,omitempty"`
+	// the time left until the client deadline set with ``grpc-timeout``
+	Deadline      *DeadlinePattern `protobuf:"bytes,15,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DittoBodyPattern) Reset() {
+	*x = DittoBodyPattern{}
+	mi := &file_mocking_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DittoBodyPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DittoBodyPattern) ProtoMessage() {}
+
+func (x *DittoBodyPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DittoBodyPattern.ProtoReflect.Descriptor instead.
+func (*DittoBodyPattern) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DittoBodyPattern) GetPattern() isDittoBodyPattern_Pattern {
+	if x != nil {
+		return x.Pattern
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetEqualToJson() *structpb.Struct {
+	if x != nil {
+		if x, ok := x.Pattern.(*DittoBodyPattern_EqualToJson); ok {
+			return x.EqualToJson
+		}
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetMatchesJsonpath() *JSONPathPattern {
+	if x != nil {
+		if x, ok := x.Pattern.(*DittoBodyPattern_MatchesJsonpath); ok {
+			return x.MatchesJsonpath
+		}
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetIncludesJson() *structpb.Struct {
+	if x != nil {
+		if x, ok := x.Pattern.(*DittoBodyPattern_IncludesJson); ok {
+			return x.IncludesJson
+		}
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetMatchesCel() string {
+	if x != nil {
+		if x, ok := x.Pattern.(*DittoBodyPattern_MatchesCel); ok {
+			return x.MatchesCel
+		}
+	}
+	return ""
+}
+
+func (x *DittoBodyPattern) GetMatchesField() *FieldPattern {
+	if x != nil {
+		if x, ok := x.Pattern.(*DittoBodyPattern_MatchesField); ok {
+			return x.MatchesField
+		}
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetAnyOf() []*DittoBodyPattern {
+	if x != nil {
+		return x.AnyOf
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetAllOf() []*DittoBodyPattern {
+	if x != nil {
+		return x.AllOf
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetNot() *DittoBodyPattern {
+	if x != nil {
+		return x.Not
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetIgnoreExtraFields() bool {
+	if x != nil {
+		return x.IgnoreExtraFields
+	}
+	return false
+}
+
+func (x *DittoBodyPattern) GetIgnoreArrayOrder() bool {
+	if x != nil {
+		return x.IgnoreArrayOrder
+	}
+	return false
+}
+
+func (x *DittoBodyPattern) GetAnyMessage() *DittoBodyPattern {
+	if x != nil {
+		return x.AnyMessage
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetEveryMessage() *DittoBodyPattern {
+	if x != nil {
+		return x.EveryMessage
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetMessageCount() *MessageCount {
+	if x != nil {
+		return x.MessageCount
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetSequence() []*SequenceStep {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
+func (x *DittoBodyPattern) GetDeadline() *DeadlinePattern {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+type isDittoBodyPattern_Pattern interface {
+	isDittoBodyPattern_Pattern()
+}
+
+type DittoBodyPattern_EqualToJson struct {
+	EqualToJson *structpb.Struct `protobuf:"bytes,1,opt,name=equal_to_json,json=equalToJson,proto3,oneof"`
+}
+
+type DittoBodyPattern_MatchesJsonpath struct {
+	MatchesJsonpath *JSONPathPattern `protobuf:"bytes,2,opt,name=matches_jsonpath,json=matchesJsonpath,proto3,oneof"`
+}
+
+type DittoBodyPattern_IncludesJson struct {
+	// the same as equal_to_json with ignore_extra_fields set
+	IncludesJson *structpb.Struct `protobuf:"bytes,6,opt,name=includes_json,json=includesJson,proto3,oneof"`
+}
+
+type DittoBodyPattern_MatchesCel struct {
+	// CEL expression evaluated against the typed ``request`` message (list of messages for client streaming)
+	// and ``metadata`` map of incoming metadata, e.g. ``request.items.size() > 2 && request.currency == 'EUR'``
+	MatchesCel string `protobuf:"bytes,9,opt,name=matches_cel,json=matchesCel,proto3,oneof"`
+}
+
+type DittoBodyPattern_MatchesField struct {
+	MatchesField *FieldPattern `protobuf:"bytes,10,opt,name=matches_field,json=matchesField,proto3,oneof"`
+}
+
+func (*DittoBodyPattern_EqualToJson) isDittoBodyPattern_Pattern() {}
+
+func (*DittoBodyPattern_MatchesJsonpath) isDittoBodyPattern_Pattern() {}
+
+func (*DittoBodyPattern_IncludesJson) isDittoBodyPattern_Pattern() {}
+
+func (*DittoBodyPattern_MatchesCel) isDittoBodyPattern_Pattern() {}
+
+func (*DittoBodyPattern_MatchesField) isDittoBodyPattern_Pattern() {}
+
+// DeadlinePattern matches the time left until the client deadline when the request is matched,
+// e.g. “{ "lt": "500ms" }“ matches the calls that can't wait for a slow response
+type DeadlinePattern struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the client must or must not set the deadline
+	IsSet *bool `protobuf:"varint,1,opt,name=is_set,json=isSet,proto3,oneof" json:"is_set,omitempty"`
+	// less than the duration like ``500ms``, it implies is_set
+	Lt string `protobuf:"bytes,2,opt,name=lt,proto3" json:"lt,omitempty"`
+	// greater than the duration, it implies is_set
+	Gt            string `protobuf:"bytes,3,opt,name=gt,proto3" json:"gt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadlinePattern) Reset() {
+	*x = DeadlinePattern{}
+	mi := &file_mocking_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadlinePattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadlinePattern) ProtoMessage() {}
+
+func (x *DeadlinePattern) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadlinePattern.ProtoReflect.Descriptor instead.
+func (*DeadlinePattern) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeadlinePattern) GetIsSet() bool {
+	if x != nil && x.IsSet != nil {
+		return *x.IsSet
+	}
+	return false
+}
+
+func (x *DeadlinePattern) GetLt() string {
+	if x != nil {
+		return x.Lt
+	}
+	return ""
+}
+
+func (x *DeadlinePattern) GetGt() string {
+	if x != nil {
+		return x.Gt
+	}
+	return ""
+}
+
+// MessageCount is inclusive range of the number of messages, max is unlimited if it's not set
+type MessageCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           uint32                 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           *uint32                `protobuf:"varint,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageCount) Reset() {
+	*x = MessageCount{}
+	mi := &file_mocking_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageCount) ProtoMessage() {}
+
+func (x *MessageCount) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageCount.ProtoReflect.Descriptor instead.
+func (*MessageCount) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MessageCount) GetMin() uint32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MessageCount) GetMax() uint32 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+// SequenceStep matches a single message or skips any number of messages
+type SequenceStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Step:
+	//
+	//	*SequenceStep_Message
+	//	*SequenceStep_Any
+	//	*SequenceStep_Skip
+	Step          isSequenceStep_Step `protobuf_oneof:"step"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SequenceStep) Reset() {
+	*x = SequenceStep{}
+	mi := &file_mocking_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SequenceStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SequenceStep) ProtoMessage() {}
+
+func (x *SequenceStep) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SequenceStep.ProtoReflect.Descriptor instead.
+func (*SequenceStep) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SequenceStep) GetStep() isSequenceStep_Step {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *SequenceStep) GetMessage() *DittoBodyPattern {
+	if x != nil {
+		if x, ok := x.Step.(*SequenceStep_Message); ok {
+			return x.Message
+		}
+	}
+	return nil
+}
+
+func (x *SequenceStep) GetAny() bool {
+	if x != nil {
+		if x, ok := x.Step.(*SequenceStep_Any); ok {
+			return x.Any
+		}
+	}
+	return false
+}
+
+func (x *SequenceStep) GetSkip() bool {
+	if x != nil {
+		if x, ok := x.Step.(*SequenceStep_Skip); ok {
+			return x.Skip
+		}
+	}
+	return false
+}
+
+type isSequenceStep_Step interface {
+	isSequenceStep_Step()
+}
+
+type SequenceStep_Message struct {
+	// the next message must match the pattern
+	Message *DittoBodyPattern `protobuf:"bytes,1,opt,name=message,proto3,oneof"`
+}
+
+type SequenceStep_Any struct {
+	// the next message can be anything
+	Any bool `protobuf:"varint,2,opt,name=any,proto3,oneof"`
+}
+
+type SequenceStep_Skip struct {
+	// zero or more messages of any content
+	Skip bool `protobuf:"varint,3,opt,name=skip,proto3,oneof"`
+}
+
+func (*SequenceStep_Message) isSequenceStep_Step() {}
+
+func (*SequenceStep_Any) isSequenceStep_Step() {}
+
+func (*SequenceStep_Skip) isSequenceStep_Step() {}
+
+// JSONPath pattern supports JSONPath spec
+//
+// Comparison operators are type aware: numbers are compared as numbers (including 64-bit integers
+// rendered as strings), RFC3339 timestamps as time and other strings lexicographically.
+// If the expression selects multiple values all of them must match.
+//
+// Examples
+// ^^^^^^^^
+//
+// { "expression": "$.message_type", "eq": "resource" }
+// { "expression": "$.message_type", "contains": "re" }
+// { "expression": "$.name", "regexp": "^callback[-]svc.*$" }
+// { "expression": "$.amount", "gt": "1000" }
+// { "expression": "$.created_at", "between": { "min": "2024-01-01T00:00:00Z", "max": "2024-02-01T00:00:00Z" } }
+// { "expression": "$.status", "in": ["ACTIVE", "PENDING"] }
+// { "expression": "$.parent", "is_null": true }
+type JSONPathPattern struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Expression string                 `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Types that are valid to be assigned to Operator:
+	//
+	//	*JSONPathPattern_Contains
+	//	*JSONPathPattern_Eq
+	//	*JSONPathPattern_Regexp
+	//	*JSONPathPattern_Gt
+	//	*JSONPathPattern_Gte
+	//	*JSONPathPattern_Lt
+	//	*JSONPathPattern_Lte
+	//	*JSONPathPattern_Between
+	//	*JSONPathPattern_In
+	//	*JSONPathPattern_StartsWith
+	//	*JSONPathPattern_EndsWith
+	//	*JSONPathPattern_Absent
+	//	*JSONPathPattern_IsNull
+	Operator      isJSONPathPattern_Operator `protobuf_oneof:"operator"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JSONPathPattern) Reset() {
+	*x = JSONPathPattern{}
+	mi := &file_mocking_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JSONPathPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JSONPathPattern) ProtoMessage() {}
+
+func (x *JSONPathPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JSONPathPattern.ProtoReflect.Descriptor instead.
+func (*JSONPathPattern) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JSONPathPattern) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetOperator() isJSONPathPattern_Operator {
+	if x != nil {
+		return x.Operator
+	}
+	return nil
+}
+
+func (x *JSONPathPattern) GetContains() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Contains); ok {
+			return x.Contains
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetEq() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Eq); ok {
+			return x.Eq
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetRegexp() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Regexp); ok {
+			return x.Regexp
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetGt() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Gt); ok {
+			return x.Gt
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetGte() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Gte); ok {
+			return x.Gte
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetLt() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Lt); ok {
+			return x.Lt
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetLte() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Lte); ok {
+			return x.Lte
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetBetween() *Range {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Between); ok {
+			return x.Between
+		}
+	}
+	return nil
+}
+
+func (x *JSONPathPattern) GetIn() *structpb.ListValue {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_In); ok {
+			return x.In
+		}
+	}
+	return nil
+}
+
+func (x *JSONPathPattern) GetStartsWith() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_StartsWith); ok {
+			return x.StartsWith
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetEndsWith() string {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_EndsWith); ok {
+			return x.EndsWith
+		}
+	}
+	return ""
+}
+
+func (x *JSONPathPattern) GetAbsent() bool {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_Absent); ok {
+			return x.Absent
+		}
+	}
+	return false
+}
+
+func (x *JSONPathPattern) GetIsNull() bool {
+	if x != nil {
+		if x, ok := x.Operator.(*JSONPathPattern_IsNull); ok {
+			return x.IsNull
+		}
+	}
+	return false
+}
+
+type isJSONPathPattern_Operator interface {
+	isJSONPathPattern_Operator()
+}
+
+type JSONPathPattern_Contains struct {
+	Contains string `protobuf:"bytes,2,opt,name=contains,proto3,oneof"`
+}
+
+type JSONPathPattern_Eq struct {
+	Eq string `protobuf:"bytes,3,opt,name=eq,proto3,oneof"`
+}
+
+type JSONPathPattern_Regexp struct {
+	Regexp string `protobuf:"bytes,4,opt,name=regexp,proto3,oneof"`
+}
+
+type JSONPathPattern_Gt struct {
+	Gt string `protobuf:"bytes,5,opt,name=gt,proto3,oneof"`
+}
+
+type JSONPathPattern_Gte struct {
+	Gte string `protobuf:"bytes,6,opt,name=gte,proto3,oneof"`
+}
+
+type JSONPathPattern_Lt struct {
+	Lt string `protobuf:"bytes,7,opt,name=lt,proto3,oneof"`
+}
+
+type JSONPathPattern_Lte struct {
+	Lte string `protobuf:"bytes,8,opt,name=lte,proto3,oneof"`
+}
+
+type JSONPathPattern_Between struct {
+	// inclusive range
+	Between *Range `protobuf:"bytes,9,opt,name=between,proto3,oneof"`
+}
+
+type JSONPathPattern_In struct {
+	// matches if the value equals to any of the list values
+	In *structpb.ListValue `protobuf:"bytes,10,opt,name=in,proto3,oneof"`
+}
+
+type JSONPathPattern_StartsWith struct {
+	StartsWith string `protobuf:"bytes,11,opt,name=starts_with,json=startsWith,proto3,oneof"`
+}
+
+type JSONPathPattern_EndsWith struct {
+	EndsWith string `protobuf:"bytes,12,opt,name=ends_with,json=endsWith,proto3,oneof"`
+}
+
+type JSONPathPattern_Absent struct {
+	// true matches if the expression selects nothing, false if it selects anything
+	Absent bool `protobuf:"varint,13,opt,name=absent,proto3,oneof"`
+}
+
+type JSONPathPattern_IsNull struct {
+	// true matches null values, false matches values that are not null
+	IsNull bool `protobuf:"varint,14,opt,name=is_null,json=isNull,proto3,oneof"`
+}
+
+func (*JSONPathPattern_Contains) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Eq) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Regexp) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Gt) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Gte) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Lt) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Lte) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Between) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_In) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_StartsWith) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_EndsWith) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_Absent) isJSONPathPattern_Operator() {}
+
+func (*JSONPathPattern_IsNull) isJSONPathPattern_Operator() {}
+
+// FieldPattern matches a field of the request message by its protobuf value rather than json rendering:
+// enums match by name or number, 64-bit integers are compared as integers, bytes are provided as base64
+// or hex with “0x“ prefix, timestamps as RFC3339 and durations like “1.5s“, wrapper types are unwrapped.
+// Messages can be compared for equality using their json form.
+//
+// Examples
+// ^^^^^^^^
+//
+// { "field": "status", "in": ["ACTIVE", 2] }
+// { "field": "items[0].price", "gte": "100" }
+// { "field": "labels[env]", "eq": "prod" }
+// { "field": "created_at", "between": { "min": "2024-01-01T00:00:00Z", "max": "2024-02-01T00:00:00Z" } }
+type FieldPattern struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// original field names separated by dots, e.g. ``user.address.city``,
+	// repeated fields and maps can be indexed: ``items[0].sku``, ``labels[env]``,
+	// messages of client streaming requests can be indexed too: ``[0].name``.
+	// If the path selects multiple values all of them must match.
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Types that are valid to be assigned to Operator:
+	//
+	//	*FieldPattern_Eq
+	//	*FieldPattern_Gt
+	//	*FieldPattern_Gte
+	//	*FieldPattern_Lt
+	//	*FieldPattern_Lte
+	//	*FieldPattern_Between
+	//	*FieldPattern_In
+	//	*FieldPattern_Contains
+	//	*FieldPattern_Regexp
+	//	*FieldPattern_StartsWith
+	//	*FieldPattern_EndsWith
+	//	*FieldPattern_IsSet
+	Operator      isFieldPattern_Operator `protobuf_oneof:"operator"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldPattern) Reset() {
+	*x = FieldPattern{}
+	mi := &file_mocking_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldPattern) ProtoMessage() {}
+
+func (x *FieldPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldPattern.ProtoReflect.Descriptor instead.
+func (*FieldPattern) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FieldPattern) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetOperator() isFieldPattern_Operator {
+	if x != nil {
+		return x.Operator
+	}
+	return nil
+}
+
+func (x *FieldPattern) GetEq() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Eq); ok {
+			return x.Eq
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetGt() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Gt); ok {
+			return x.Gt
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetGte() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Gte); ok {
+			return x.Gte
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetLt() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Lt); ok {
+			return x.Lt
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetLte() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Lte); ok {
+			return x.Lte
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetBetween() *Range {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Between); ok {
+			return x.Between
+		}
+	}
+	return nil
+}
+
+func (x *FieldPattern) GetIn() *structpb.ListValue {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_In); ok {
+			return x.In
+		}
+	}
+	return nil
+}
+
+func (x *FieldPattern) GetContains() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Contains); ok {
+			return x.Contains
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetRegexp() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_Regexp); ok {
+			return x.Regexp
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetStartsWith() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_StartsWith); ok {
+			return x.StartsWith
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetEndsWith() string {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_EndsWith); ok {
+			return x.EndsWith
+		}
+	}
+	return ""
+}
+
+func (x *FieldPattern) GetIsSet() bool {
+	if x != nil {
+		if x, ok := x.Operator.(*FieldPattern_IsSet); ok {
+			return x.IsSet
+		}
+	}
+	return false
+}
+
+type isFieldPattern_Operator interface {
+	isFieldPattern_Operator()
+}
+
+type FieldPattern_Eq struct {
+	Eq string `protobuf:"bytes,2,opt,name=eq,proto3,oneof"`
+}
+
+type FieldPattern_Gt struct {
+	Gt string `protobuf:"bytes,3,opt,name=gt,proto3,oneof"`
+}
+
+type FieldPattern_Gte struct {
+	Gte string `protobuf:"bytes,4,opt,name=gte,proto3,oneof"`
+}
+
+type FieldPattern_Lt struct {
+	Lt string `protobuf:"bytes,5,opt,name=lt,proto3,oneof"`
+}
+
+type FieldPattern_Lte struct {
+	Lte string `protobuf:"bytes,6,opt,name=lte,proto3,oneof"`
+}
+
+type FieldPattern_Between struct {
+	Between *Range `protobuf:"bytes,7,opt,name=between,proto3,oneof"`
+}
+
+type FieldPattern_In struct {
+	In *structpb.ListValue `protobuf:"bytes,8,opt,name=in,proto3,oneof"`
+}
+
+type FieldPattern_Contains struct {
+	Contains string `protobuf:"bytes,9,opt,name=contains,proto3,oneof"`
+}
+
+type FieldPattern_Regexp struct {
+	Regexp string `protobuf:"bytes,10,opt,name=regexp,proto3,oneof"`
+}
+
+type FieldPattern_StartsWith struct {
+	StartsWith string `protobuf:"bytes,11,opt,name=starts_with,json=startsWith,proto3,oneof"`
+}
+
+type FieldPattern_EndsWith struct {
+	EndsWith string `protobuf:"bytes,12,opt,name=ends_with,json=endsWith,proto3,oneof"`
+}
+
+type FieldPattern_IsSet struct {
+	// true matches if the field is set: messages are present, scalars are not default, lists and maps are not empty
+	IsSet bool `protobuf:"varint,13,opt,name=is_set,json=isSet,proto3,oneof"`
+}
+
+func (*FieldPattern_Eq) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Gt) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Gte) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Lt) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Lte) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Between) isFieldPattern_Operator() {}
+
+func (*FieldPattern_In) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Contains) isFieldPattern_Operator() {}
+
+func (*FieldPattern_Regexp) isFieldPattern_Operator() {}
+
+func (*FieldPattern_StartsWith) isFieldPattern_Operator() {}
+
+func (*FieldPattern_EndsWith) isFieldPattern_Operator() {}
+
+func (*FieldPattern_IsSet) isFieldPattern_Operator() {}
+
+type Range struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           string                 `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           string                 `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	mi := &file_mocking_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Range) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *Range) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+type ClearRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearRequest) Reset() {
+	*x = ClearRequest{}
+	mi := &file_mocking_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearRequest) ProtoMessage() {}
+
+func (x *ClearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearRequest.ProtoReflect.Descriptor instead.
+func (*ClearRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{17}
+}
+
+type ClearResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearResponse) Reset() {
+	*x = ClearResponse{}
+	mi := &file_mocking_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearResponse) ProtoMessage() {}
+
+func (x *ClearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearResponse.ProtoReflect.Descriptor instead.
+func (*ClearResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{18}
+}
+
+type ListUnmatchedRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUnmatchedRequestsRequest) Reset() {
+	*x = ListUnmatchedRequestsRequest{}
+	mi := &file_mocking_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUnmatchedRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUnmatchedRequestsRequest) ProtoMessage() {}
+
+func (x *ListUnmatchedRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUnmatchedRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListUnmatchedRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{19}
+}
+
+type ListUnmatchedRequestsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the most recent request goes last
+	Requests      []*UnmatchedRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUnmatchedRequestsResponse) Reset() {
+	*x = ListUnmatchedRequestsResponse{}
+	mi := &file_mocking_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUnmatchedRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUnmatchedRequestsResponse) ProtoMessage() {}
+
+func (x *ListUnmatchedRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUnmatchedRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListUnmatchedRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListUnmatchedRequestsResponse) GetRequests() []*UnmatchedRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type UnmatchedRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Method string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// request body, client streaming requests are arrays of messages
+	Body *structpb.Value `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// mocks of the method that are the closest to the request, the best one goes first
+	Candidates    []*MatchCandidate `protobuf:"bytes,4,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmatchedRequest) Reset() {
+	*x = UnmatchedRequest{}
+	mi := &file_mocking_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmatchedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmatchedRequest) ProtoMessage() {}
+
+func (x *UnmatchedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmatchedRequest.ProtoReflect.Descriptor instead.
+func (*UnmatchedRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UnmatchedRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *UnmatchedRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *UnmatchedRequest) GetBody() *structpb.Value {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *UnmatchedRequest) GetCandidates() []*MatchCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+type ListCanceledRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCanceledRequestsRequest) Reset() {
+	*x = ListCanceledRequestsRequest{}
+	mi := &file_mocking_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCanceledRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCanceledRequestsRequest) ProtoMessage() {}
+
+func (x *ListCanceledRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCanceledRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListCanceledRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{22}
+}
+
+type ListCanceledRequestsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the most recent request goes last
+	Requests      []*CanceledRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCanceledRequestsResponse) Reset() {
+	*x = ListCanceledRequestsResponse{}
+	mi := &file_mocking_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCanceledRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCanceledRequestsResponse) ProtoMessage() {}
+
+func (x *ListCanceledRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCanceledRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListCanceledRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListCanceledRequestsResponse) GetRequests() []*CanceledRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type CanceledRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Method string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// request body, it's not set if the call was canceled while the request was received
+	Body *structpb.Value `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// matched mock, it's not set if the call was canceled before the request was matched
+	MockId string `protobuf:"bytes,4,opt,name=mock_id,json=mockId,proto3" json:"mock_id,omitempty"`
+	// CANCELLED or DEADLINE_EXCEEDED
+	Code code.Code `protobuf:"varint,5,opt,name=code,proto3,enum=google.rpc.Code" json:"code,omitempty"`
+	// the stage the call was canceled at: fault, receive, match, delay, send or keep_open
+	Stage string `protobuf:"bytes,6,opt,name=stage,proto3" json:"stage,omitempty"`
+	// client timeout, it's not set if the client didn't set the deadline
+	Timeout *durationpb.Duration `protobuf:"bytes,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// time from the start of the call to the cancellation
+	Elapsed       *durationpb.Duration `protobuf:"bytes,8,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CanceledRequest) Reset() {
+	*x = CanceledRequest{}
+	mi := &file_mocking_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CanceledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanceledRequest) ProtoMessage() {}
+
+func (x *CanceledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanceledRequest.ProtoReflect.Descriptor instead.
+func (*CanceledRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CanceledRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *CanceledRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *CanceledRequest) GetBody() *structpb.Value {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+func (x *CanceledRequest) GetMockId() string {
+	if x != nil {
+		return x.MockId
+	}
+	return ""
+}
+
+func (x *CanceledRequest) GetCode() code.Code {
+	if x != nil {
+		return x.Code
+	}
+	return code.Code(0)
+}
+
+func (x *CanceledRequest) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *CanceledRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *CanceledRequest) GetElapsed() *durationpb.Duration {
+	if x != nil {
+		return x.Elapsed
+	}
+	return nil
+}
+
+type MatchCandidate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MockId          string                 `protobuf:"bytes,1,opt,name=mock_id,json=mockId,proto3" json:"mock_id,omitempty"`
+	MatchedPatterns int32                  `protobuf:"varint,2,opt,name=matched_patterns,json=matchedPatterns,proto3" json:"matched_patterns,omitempty"`
+	TotalPatterns   int32                  `protobuf:"varint,3,opt,name=total_patterns,json=totalPatterns,proto3" json:"total_patterns,omitempty"`
+	Mismatches      []*PatternMismatch     `protobuf:"bytes,4,rep,name=mismatches,proto3" json:"mismatches,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MatchCandidate) Reset() {
+	*x = MatchCandidate{}
+	mi := &file_mocking_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchCandidate) ProtoMessage() {}
+
+func (x *MatchCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchCandidate.ProtoReflect.Descriptor instead.
+func (*MatchCandidate) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MatchCandidate) GetMockId() string {
+	if x != nil {
+		return x.MockId
+	}
+	return ""
+}
+
+func (x *MatchCandidate) GetMatchedPatterns() int32 {
+	if x != nil {
+		return x.MatchedPatterns
+	}
+	return 0
+}
+
+func (x *MatchCandidate) GetTotalPatterns() int32 {
+	if x != nil {
+		return x.TotalPatterns
+	}
+	return 0
+}
+
+func (x *MatchCandidate) GetMismatches() []*PatternMismatch {
+	if x != nil {
+		return x.Mismatches
+	}
+	return nil
+}
+
+// PatternMismatch describes why a body pattern didn't match the request
+type PatternMismatch struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	PatternIndex int32                  `protobuf:"varint,1,opt,name=pattern_index,json=patternIndex,proto3" json:"pattern_index,omitempty"`
+	// JSONPath expression, ``$`` is used for patterns that apply to the whole request
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Expected      string `protobuf:"bytes,3,opt,name=expected,proto3" json:"expected,omitempty"`
+	Actual        string `protobuf:"bytes,4,opt,name=actual,proto3" json:"actual,omitempty"`
+	Reason        string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatternMismatch) Reset() {
+	*x = PatternMismatch{}
+	mi := &file_mocking_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatternMismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatternMismatch) ProtoMessage() {}
+
+func (x *PatternMismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatternMismatch.ProtoReflect.Descriptor instead.
+func (*PatternMismatch) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PatternMismatch) GetPatternIndex() int32 {
+	if x != nil {
+		return x.PatternIndex
+	}
+	return 0
+}
+
+func (x *PatternMismatch) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PatternMismatch) GetExpected() string {
+	if x != nil {
+		return x.Expected
+	}
+	return ""
+}
+
+func (x *PatternMismatch) GetActual() string {
+	if x != nil {
+		return x.Actual
+	}
+	return ""
+}
+
+func (x *PatternMismatch) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// StreamSelector chooses open streams by id or by method and request metadata,
+// all of the set fields must match
+type StreamSelector struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	StreamId string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	// fully qualified grpc method like ``/package.full.name.EventService/Watch``
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// metadata values the stream request must have
+	Metadata      map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSelector) Reset() {
+	*x = StreamSelector{}
+	mi := &file_mocking_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSelector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSelector) ProtoMessage() {}
+
+func (x *StreamSelector) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSelector.ProtoReflect.Descriptor instead.
+func (*StreamSelector) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *StreamSelector) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *StreamSelector) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *StreamSelector) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ListStreamsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// all open streams are returned if it's not set
+	Selector      *StreamSelector `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStreamsRequest) Reset() {
+	*x = ListStreamsRequest{}
+	mi := &file_mocking_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStreamsRequest) ProtoMessage() {}
+
+func (x *ListStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStreamsRequest.ProtoReflect.Descriptor instead.
+func (*ListStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListStreamsRequest) GetSelector() *StreamSelector {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+type ListStreamsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the oldest stream goes first
+	Streams       []*OpenStream `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStreamsResponse) Reset() {
+	*x = ListStreamsResponse{}
+	mi := &file_mocking_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStreamsResponse) ProtoMessage() {}
+
+func (x *ListStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStreamsResponse.ProtoReflect.Descriptor instead.
+func (*ListStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListStreamsResponse) GetStreams() []*OpenStream {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+type OpenStream struct {
+	state    protoimpl.MessageState     `protogen:"open.v1"`
+	Id       string                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method   string                     `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Started  *timestamppb.Timestamp     `protobuf:"bytes,3,opt,name=started,proto3" json:"started,omitempty"`
+	Metadata map[string]*MetadataValues `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// request body, client streaming requests are arrays of messages
+	Body          *structpb.Value `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenStream) Reset() {
+	*x = OpenStream{}
+	mi := &file_mocking_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenStream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenStream) ProtoMessage() {}
+
+func (x *OpenStream) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenStream.ProtoReflect.Descriptor instead.
+func (*OpenStream) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *OpenStream) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OpenStream) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *OpenStream) GetStarted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Started
+	}
+	return nil
+}
+
+func (x *OpenStream) GetMetadata() map[string]*MetadataValues {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *OpenStream) GetBody() *structpb.Value {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type MetadataValues struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataValues) Reset() {
+	*x = MetadataValues{}
+	mi := &file_mocking_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataValues) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataValues) ProtoMessage() {}
+
+func (x *MetadataValues) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataValues.ProtoReflect.Descriptor instead.
+func (*MetadataValues) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MetadataValues) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type PushMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// stream_id or method is required
+	Selector *StreamSelector `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// the message is validated against the method output type
+	Body          *structpb.Struct `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushMessageRequest) Reset() {
+	*x = PushMessageRequest{}
+	mi := &file_mocking_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushMessageRequest) ProtoMessage() {}
+
+func (x *PushMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushMessageRequest.ProtoReflect.Descriptor instead.
+func (*PushMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PushMessageRequest) GetSelector() *StreamSelector {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *PushMessageRequest) GetBody() *structpb.Struct {
+	if x != nil {
+		return x.Body
+	}
+	return nil
+}
+
+type PushMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StreamIds     []string               `protobuf:"bytes,1,rep,name=stream_ids,json=streamIds,proto3" json:"stream_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (m *DittoBodyPattern) GetPattern() isDittoBodyPattern_Pattern {
-	if m != nil {
-		return m.Pattern
-	}
-	return nil
+func (x *PushMessageResponse) Reset() {
+	*x = PushMessageResponse{}
+	mi := &file_mocking_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DittoBodyPattern) GetEqualToJson() *structpb.Struct {
-	if x, ok := x.GetPattern().(*DittoBodyPattern_EqualToJson); ok {
-		return x.EqualToJson
+func (x *PushMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushMessageResponse) ProtoMessage() {}
+
+func (x *PushMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *DittoBodyPattern) GetMatchesJsonpath() *JSONPathPattern {
-	if x, ok := x.GetPattern().(*DittoBodyPattern_MatchesJsonpath); ok {
-		return x.MatchesJsonpath
+// Deprecated: Use PushMessageResponse.ProtoReflect.Descriptor instead.
+func (*PushMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *PushMessageResponse) GetStreamIds() []string {
+	if x != nil {
+		return x.StreamIds
 	}
 	return nil
 }
 
-type isDittoBodyPattern_Pattern interface {
-	isDittoBodyPattern_Pattern()
+type CloseStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// stream_id or method is required
+	Selector *StreamSelector `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// streams are finished successfully if it's not set
+	Status        *RpcStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type DittoBodyPattern_EqualToJson struct {
-	EqualToJson *structpb.Struct `protobuf:"bytes,1,opt,name=equal_to_json,json=equalToJson,proto3,oneof"`
+func (x *CloseStreamRequest) Reset() {
+	*x = CloseStreamRequest{}
+	mi := &file_mocking_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type DittoBodyPattern_MatchesJsonpath struct {
-	MatchesJsonpath *JSONPathPattern `protobuf:"bytes,2,opt,name=matches_jsonpath,json=matchesJsonpath,proto3,oneof"`
+func (x *CloseStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DittoBodyPattern_EqualToJson) isDittoBodyPattern_Pattern() {}
+func (*CloseStreamRequest) ProtoMessage() {}
 
-func (*DittoBodyPattern_MatchesJsonpath) isDittoBodyPattern_Pattern() {}
+func (x *CloseStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-// JSONPath pattern supports JSONPath spec
-//
-// Examples
-// ^^^^^^^^
-//
-// { "expression": "$.message_type", "eq": "resource" }
-// { "expression": "$.message_type", "contains": "re" }
-// { "expression": "$.name", "regexp": "^callback[-]svc.*$" }
-type JSONPathPattern struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// Deprecated: Use CloseStreamRequest.ProtoReflect.Descriptor instead.
+func (*CloseStreamRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CloseStreamRequest) GetSelector() *StreamSelector {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *CloseStreamRequest) GetStatus() *RpcStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type CloseStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StreamIds     []string               `protobuf:"bytes,1,rep,name=stream_ids,json=streamIds,proto3" json:"stream_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-	Expression string `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
-	// Types that are assignable to Operator:
-	//
-	//	*JSONPathPattern_Contains
-	//	*JSONPathPattern_Eq
-	//	*JSONPathPattern_Regexp
-	Operator isJSONPathPattern_Operator `protobuf_oneof:"operator"`
+func (x *CloseStreamResponse) Reset() {
+	*x = CloseStreamResponse{}
+	mi := &file_mocking_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *JSONPathPattern) Reset() {
-	*x = JSONPathPattern{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[7]
+func (x *CloseStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseStreamResponse) ProtoMessage() {}
+
+func (x *CloseStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[35]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
+	return mi.MessageOf(x)
 }
 
-func (x *JSONPathPattern) String() string {
+// Deprecated: Use CloseStreamResponse.ProtoReflect.Descriptor instead.
+func (*CloseStreamResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CloseStreamResponse) GetStreamIds() []string {
+	if x != nil {
+		return x.StreamIds
+	}
+	return nil
+}
+
+// FaultRule injects failures into the calls of the matching methods.
+// The fault is applied before the request is matched unless after_messages is set
+type FaultRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// optional rule identifier used in logs
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// glob of fully qualified grpc method like ``/package.full.name.UserService/*``,
+	// all methods are matched if it's not set
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// percentage of the matching calls the fault is injected into from 0 to 100, 100 if it's not set
+	Percentage *float64 `protobuf:"fixed64,3,opt,name=percentage,proto3,oneof" json:"percentage,omitempty"`
+	// metadata values the request must have
+	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// latency added before the call is handled like ``250ms``
+	Delay string `protobuf:"bytes,5,opt,name=delay,proto3" json:"delay,omitempty"`
+	// status returned instead of the mock response
+	Status *RpcStatus `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// the client connection is reset
+	ResetConnection bool `protobuf:"varint,7,opt,name=reset_connection,json=resetConnection,proto3" json:"reset_connection,omitempty"`
+	// the fault is applied after the number of response messages are sent,
+	// the stream is truncated successfully if status, reset_connection and reset_stream are not set
+	AfterMessages *uint32 `protobuf:"varint,8,opt,name=after_messages,json=afterMessages,proto3,oneof" json:"after_messages,omitempty"`
+	// only the http/2 stream of the call is reset, other calls of the connection are kept
+	ResetStream   bool `protobuf:"varint,9,opt,name=reset_stream,json=resetStream,proto3" json:"reset_stream,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FaultRule) Reset() {
+	*x = FaultRule{}
+	mi := &file_mocking_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FaultRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JSONPathPattern) ProtoMessage() {}
+func (*FaultRule) ProtoMessage() {}
 
-func (x *JSONPathPattern) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *FaultRule) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[36]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -505,92 +2845,176 @@ func (x *JSONPathPattern) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JSONPathPattern.ProtoReflect.Descriptor instead.
-func (*JSONPathPattern) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use FaultRule.ProtoReflect.Descriptor instead.
+func (*FaultRule) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *JSONPathPattern) GetExpression() string {
+func (x *FaultRule) GetId() string {
 	if x != nil {
-		return x.Expression
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FaultRule) GetMethod() string {
+	if x != nil {
+		return x.Method
 	}
 	return ""
 }
 
-func (m *JSONPathPattern) GetOperator() isJSONPathPattern_Operator {
-	if m != nil {
-		return m.Operator
+func (x *FaultRule) GetPercentage() float64 {
+	if x != nil && x.Percentage != nil {
+		return *x.Percentage
+	}
+	return 0
+}
+
+func (x *FaultRule) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
 	}
 	return nil
 }
 
-func (x *JSONPathPattern) GetContains() string {
-	if x, ok := x.GetOperator().(*JSONPathPattern_Contains); ok {
-		return x.Contains
+func (x *FaultRule) GetDelay() string {
+	if x != nil {
+		return x.Delay
 	}
 	return ""
 }
 
-func (x *JSONPathPattern) GetEq() string {
-	if x, ok := x.GetOperator().(*JSONPathPattern_Eq); ok {
-		return x.Eq
+func (x *FaultRule) GetStatus() *RpcStatus {
+	if x != nil {
+		return x.Status
 	}
-	return ""
+	return nil
 }
 
-func (x *JSONPathPattern) GetRegexp() string {
-	if x, ok := x.GetOperator().(*JSONPathPattern_Regexp); ok {
-		return x.Regexp
+func (x *FaultRule) GetResetConnection() bool {
+	if x != nil {
+		return x.ResetConnection
 	}
-	return ""
+	return false
 }
 
-type isJSONPathPattern_Operator interface {
-	isJSONPathPattern_Operator()
+func (x *FaultRule) GetAfterMessages() uint32 {
+	if x != nil && x.AfterMessages != nil {
+		return *x.AfterMessages
+	}
+	return 0
 }
 
-type JSONPathPattern_Contains struct {
-	Contains string `protobuf:"bytes,2,opt,name=contains,proto3,oneof"`
+func (x *FaultRule) GetResetStream() bool {
+	if x != nil {
+		return x.ResetStream
+	}
+	return false
 }
 
-type JSONPathPattern_Eq struct {
-	Eq string `protobuf:"bytes,3,opt,name=eq,proto3,oneof"`
+type AddFaultRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *FaultRule             `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type JSONPathPattern_Regexp struct {
-	Regexp string `protobuf:"bytes,4,opt,name=regexp,proto3,oneof"`
+func (x *AddFaultRuleRequest) Reset() {
+	*x = AddFaultRuleRequest{}
+	mi := &file_mocking_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (*JSONPathPattern_Contains) isJSONPathPattern_Operator() {}
+func (x *AddFaultRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func (*JSONPathPattern_Eq) isJSONPathPattern_Operator() {}
+func (*AddFaultRuleRequest) ProtoMessage() {}
 
-func (*JSONPathPattern_Regexp) isJSONPathPattern_Operator() {}
+func (x *AddFaultRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-type ClearRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+// Deprecated: Use AddFaultRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddFaultRuleRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AddFaultRuleRequest) GetRule() *FaultRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type AddFaultRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearRequest) Reset() {
-	*x = ClearRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[8]
+func (x *AddFaultRuleResponse) Reset() {
+	*x = AddFaultRuleResponse{}
+	mi := &file_mocking_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddFaultRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddFaultRuleResponse) ProtoMessage() {}
+
+func (x *AddFaultRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[38]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
+	return mi.MessageOf(x)
 }
 
-func (x *ClearRequest) String() string {
+// Deprecated: Use AddFaultRuleResponse.ProtoReflect.Descriptor instead.
+func (*AddFaultRuleResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{38}
+}
+
+type ListFaultRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultRulesRequest) Reset() {
+	*x = ListFaultRulesRequest{}
+	mi := &file_mocking_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultRulesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearRequest) ProtoMessage() {}
+func (*ListFaultRulesRequest) ProtoMessage() {}
 
-func (x *ClearRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ListFaultRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[39]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -600,35 +3024,113 @@ func (x *ClearRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearRequest.ProtoReflect.Descriptor instead.
-func (*ClearRequest) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ListFaultRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFaultRulesRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{39}
 }
 
-type ClearResponse struct {
-	state         protoimpl.MessageState
+type ListFaultRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*FaultRule           `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFaultRulesResponse) Reset() {
+	*x = ListFaultRulesResponse{}
+	mi := &file_mocking_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFaultRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFaultRulesResponse) ProtoMessage() {}
+
+func (x *ListFaultRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFaultRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFaultRulesResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListFaultRulesResponse) GetRules() []*FaultRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ClearFaultRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearResponse) Reset() {
-	*x = ClearResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_mocking_service_proto_msgTypes[9]
+func (x *ClearFaultRulesRequest) Reset() {
+	*x = ClearFaultRulesRequest{}
+	mi := &file_mocking_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearFaultRulesRequest) ProtoMessage() {}
+
+func (x *ClearFaultRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[41]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
+	return mi.MessageOf(x)
 }
 
-func (x *ClearResponse) String() string {
+// Deprecated: Use ClearFaultRulesRequest.ProtoReflect.Descriptor instead.
+func (*ClearFaultRulesRequest) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{41}
+}
+
+type ClearFaultRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearFaultRulesResponse) Reset() {
+	*x = ClearFaultRulesResponse{}
+	mi := &file_mocking_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearFaultRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearResponse) ProtoMessage() {}
+func (*ClearFaultRulesResponse) ProtoMessage() {}
 
-func (x *ClearResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mocking_service_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ClearFaultRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mocking_service_proto_msgTypes[42]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -638,137 +3140,388 @@ func (x *ClearResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearResponse.ProtoReflect.Descriptor instead.
-func (*ClearResponse) Descriptor() ([]byte, []int) {
-	return file_mocking_service_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ClearFaultRulesResponse.ProtoReflect.Descriptor instead.
+func (*ClearFaultRulesResponse) Descriptor() ([]byte, []int) {
+	return file_mocking_service_proto_rawDescGZIP(), []int{42}
 }
 
 var File_mocking_service_proto protoreflect.FileDescriptor
 
-var file_mocking_service_proto_rawDesc = []byte{
-	0x0a, 0x15, 0x6d, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x67, 0x72, 0x70, 0x63, 0x64, 0x69, 0x74,
-	0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63,
-	0x2f, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3e, 0x0a, 0x0e, 0x41,
-	0x64, 0x64, 0x4d, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a,
-	0x04, 0x6d, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x74, 0x74,
-	0x6f, 0x4d, 0x6f, 0x63, 0x6b, 0x52, 0x04, 0x6d, 0x6f, 0x63, 0x6b, 0x22, 0x11, 0x0a, 0x0f, 0x41,
-	0x64, 0x64, 0x4d, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x7c,
-	0x0a, 0x09, 0x44, 0x69, 0x74, 0x74, 0x6f, 0x4d, 0x6f, 0x63, 0x6b, 0x12, 0x35, 0x0a, 0x07, 0x72,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67,
-	0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x74,
-	0x74, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x38, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x74, 0x74, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6c, 0x0a, 0x0c,
-	0x44, 0x69, 0x74, 0x74, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65,
-	0x74, 0x68, 0x6f, 0x64, 0x12, 0x44, 0x0a, 0x0d, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x70, 0x61, 0x74,
-	0x74, 0x65, 0x72, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x44, 0x69, 0x74, 0x74,
-	0x6f, 0x42, 0x6f, 0x64, 0x79, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x52, 0x0c, 0x62, 0x6f,
-	0x64, 0x79, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x44,
-	0x69, 0x74, 0x74, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04,
-	0x62, 0x6f, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72,
-	0x75, 0x63, 0x74, 0x48, 0x00, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x32, 0x0a, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x70, 0x63, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x25, 0x0a, 0x0d, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x62, 0x6f, 0x64, 0x79, 0x54, 0x65,
-	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x4b, 0x0a, 0x09, 0x52, 0x70, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x24, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52,
-	0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
-	0xa9, 0x01, 0x0a, 0x10, 0x44, 0x69, 0x74, 0x74, 0x6f, 0x42, 0x6f, 0x64, 0x79, 0x50, 0x61, 0x74,
-	0x74, 0x65, 0x72, 0x6e, 0x12, 0x3d, 0x0a, 0x0d, 0x65, 0x71, 0x75, 0x61, 0x6c, 0x5f, 0x74, 0x6f,
-	0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
-	0x72, 0x75, 0x63, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x65, 0x71, 0x75, 0x61, 0x6c, 0x54, 0x6f, 0x4a,
-	0x73, 0x6f, 0x6e, 0x12, 0x4b, 0x0a, 0x10, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x5f, 0x6a,
-	0x73, 0x6f, 0x6e, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
-	0x67, 0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x53,
-	0x4f, 0x4e, 0x50, 0x61, 0x74, 0x68, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x48, 0x00, 0x52,
-	0x0f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x4a, 0x73, 0x6f, 0x6e, 0x70, 0x61, 0x74, 0x68,
-	0x42, 0x09, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x22, 0x87, 0x01, 0x0a, 0x0f,
-	0x4a, 0x53, 0x4f, 0x4e, 0x50, 0x61, 0x74, 0x68, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12,
-	0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x1c, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x48, 0x00, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x10, 0x0a,
-	0x02, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x02, 0x65, 0x71, 0x12,
-	0x18, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x65, 0x78, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48,
-	0x00, 0x52, 0x06, 0x72, 0x65, 0x67, 0x65, 0x78, 0x70, 0x42, 0x0a, 0x0a, 0x08, 0x6f, 0x70, 0x65,
-	0x72, 0x61, 0x74, 0x6f, 0x72, 0x22, 0x0e, 0x0a, 0x0c, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x0f, 0x0a, 0x0d, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x9e, 0x01, 0x0a, 0x0e, 0x4d, 0x6f, 0x63, 0x6b, 0x69,
-	0x6e, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x48, 0x0a, 0x07, 0x41, 0x64, 0x64,
-	0x4d, 0x6f, 0x63, 0x6b, 0x12, 0x1d, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x4d, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x41, 0x64, 0x64, 0x4d, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x05, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x12, 0x1b, 0x2e, 0x67,
-	0x72, 0x70, 0x63, 0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x65,
-	0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x64, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x17, 0x5a, 0x05, 0x2e, 0x3b, 0x61, 0x70, 0x69,
-	0xaa, 0x02, 0x0d, 0x47, 0x72, 0x70, 0x63, 0x44, 0x69, 0x74, 0x74, 0x6f, 0x2e, 0x41, 0x70, 0x69,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+const file_mocking_service_proto_rawDesc = "" +
+	"\n" +
+	"\x15mocking_service.proto\x12\rgrpcditto.api\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x15google/rpc/code.proto\">\n" +
+	"\x0eAddMockRequest\x12,\n" +
+	"\x04mock\x18\x01 \x01(\v2\x18.grpcditto.api.DittoMockR\x04mock\"\x11\n" +
+	"\x0fAddMockResponse\"\x97\x02\n" +
+	"\tDittoMock\x125\n" +
+	"\arequest\x18\x01 \x01(\v2\x1b.grpcditto.api.DittoRequestR\arequest\x128\n" +
+	"\bresponse\x18\x02 \x03(\v2\x1c.grpcditto.api.DittoResponseR\bresponse\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x124\n" +
+	"\x06stream\x18\x04 \x01(\v2\x1c.grpcditto.api.StreamOptionsR\x06stream\x12=\n" +
+	"\tresponses\x18\x05 \x03(\v2\x1f.grpcditto.api.WeightedResponseR\tresponses\x12\x14\n" +
+	"\x05delay\x18\x06 \x01(\tR\x05delay\"t\n" +
+	"\x10WeightedResponse\x12\x1b\n" +
+	"\x06weight\x18\x01 \x01(\rH\x00R\x06weight\x88\x01\x01\x128\n" +
+	"\bresponse\x18\x02 \x03(\v2\x1c.grpcditto.api.DittoResponseR\bresponseB\t\n" +
+	"\a_weight\"d\n" +
+	"\rStreamOptions\x12\x1b\n" +
+	"\tkeep_open\x18\x01 \x01(\bR\bkeepOpen\x126\n" +
+	"\theartbeat\x18\x02 \x01(\v2\x18.grpcditto.api.HeartbeatR\theartbeat\"L\n" +
+	"\tHeartbeat\x12\x1a\n" +
+	"\binterval\x18\x01 \x01(\tR\binterval\x12#\n" +
+	"\rbody_template\x18\x02 \x01(\tR\fbodyTemplate\"l\n" +
+	"\fDittoRequest\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12D\n" +
+	"\rbody_patterns\x18\x02 \x03(\v2\x1f.grpcditto.api.DittoBodyPatternR\fbodyPatterns\"\xe5\x01\n" +
+	"\rDittoResponse\x12-\n" +
+	"\x04body\x18\x01 \x01(\v2\x17.google.protobuf.StructH\x00R\x04body\x122\n" +
+	"\x06status\x18\x02 \x01(\v2\x18.grpcditto.api.RpcStatusH\x00R\x06status\x12%\n" +
+	"\rbody_template\x18\x03 \x01(\tH\x00R\fbodyTemplate\x12>\n" +
+	"\bgenerate\x18\x04 \x01(\v2 .grpcditto.api.ResponseGeneratorH\x00R\bgenerateB\n" +
+	"\n" +
+	"\bresponse\"\x94\x01\n" +
+	"\x11ResponseGenerator\x12\x18\n" +
+	"\x06repeat\x18\x01 \x01(\rH\x00R\x06repeat\x12\x1a\n" +
+	"\aforeach\x18\x02 \x01(\tH\x00R\aforeach\x12#\n" +
+	"\rbody_template\x18\x03 \x01(\tR\fbodyTemplate\x12\x1a\n" +
+	"\binterval\x18\x04 \x01(\tR\bintervalB\b\n" +
+	"\x06source\"K\n" +
+	"\tRpcStatus\x12$\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x10.google.rpc.CodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x90\a\n" +
+	"\x10DittoBodyPattern\x12=\n" +
+	"\requal_to_json\x18\x01 \x01(\v2\x17.google.protobuf.StructH\x00R\vequalToJson\x12K\n" +
+	"\x10matches_jsonpath\x18\x02 \x01(\v2\x1e.grpcditto.api.JSONPathPatternH\x00R\x0fmatchesJsonpath\x12>\n" +
+	"\rincludes_json\x18\x06 \x01(\v2\x17.google.protobuf.StructH\x00R\fincludesJson\x12!\n" +
+	"\vmatches_cel\x18\t \x01(\tH\x00R\n" +
+	"matchesCel\x12B\n" +
+	"\rmatches_field\x18\n" +
+	" \x01(\v2\x1b.grpcditto.api.FieldPatternH\x00R\fmatchesField\x126\n" +
+	"\x06any_of\x18\x03 \x03(\v2\x1f.grpcditto.api.DittoBodyPatternR\x05anyOf\x126\n" +
+	"\x06all_of\x18\x04 \x03(\v2\x1f.grpcditto.api.DittoBodyPatternR\x05allOf\x121\n" +
+	"\x03not\x18\x05 \x01(\v2\x1f.grpcditto.api.DittoBodyPatternR\x03not\x12.\n" +
+	"\x13ignore_extra_fields\x18\a \x01(\bR\x11ignoreExtraFields\x12,\n" +
+	"\x12ignore_array_order\x18\b \x01(\bR\x10ignoreArrayOrder\x12@\n" +
+	"\vany_message\x18\v \x01(\v2\x1f.grpcditto.api.DittoBodyPatternR\n" +
+	"anyMessage\x12D\n" +
+	"\revery_message\x18\f \x01(\v2\x1f.grpcditto.api.DittoBodyPatternR\feveryMessage\x12@\n" +
+	"\rmessage_count\x18\r \x01(\v2\x1b.grpcditto.api.MessageCountR\fmessageCount\x127\n" +
+	"\bsequence\x18\x0e \x03(\v2\x1b.grpcditto.api.SequenceStepR\bsequence\x12:\n" +
+	"\bdeadline\x18\x0f \x01(\v2\x1e.grpcditto.api.DeadlinePatternR\bdeadlineB\t\n" +
+	"\apattern\"X\n" +
+	"\x0fDeadlinePattern\x12\x1a\n" +
+	"\x06is_set\x18\x01 \x01(\bH\x00R\x05isSet\x88\x01\x01\x12\x0e\n" +
+	"\x02lt\x18\x02 \x01(\tR\x02lt\x12\x0e\n" +
+	"\x02gt\x18\x03 \x01(\tR\x02gtB\t\n" +
+	"\a_is_set\"?\n" +
+	"\fMessageCount\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\rR\x03min\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\rH\x00R\x03max\x88\x01\x01B\x06\n" +
+	"\x04_max\"}\n" +
+	"\fSequenceStep\x12;\n" +
+	"\amessage\x18\x01 \x01(\v2\x1f.grpcditto.api.DittoBodyPatternH\x00R\amessage\x12\x12\n" +
+	"\x03any\x18\x02 \x01(\bH\x00R\x03any\x12\x14\n" +
+	"\x04skip\x18\x03 \x01(\bH\x00R\x04skipB\x06\n" +
+	"\x04step\"\xaa\x03\n" +
+	"\x0fJSONPathPattern\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x01 \x01(\tR\n" +
+	"expression\x12\x1c\n" +
+	"\bcontains\x18\x02 \x01(\tH\x00R\bcontains\x12\x10\n" +
+	"\x02eq\x18\x03 \x01(\tH\x00R\x02eq\x12\x18\n" +
+	"\x06regexp\x18\x04 \x01(\tH\x00R\x06regexp\x12\x10\n" +
+	"\x02gt\x18\x05 \x01(\tH\x00R\x02gt\x12\x12\n" +
+	"\x03gte\x18\x06 \x01(\tH\x00R\x03gte\x12\x10\n" +
+	"\x02lt\x18\a \x01(\tH\x00R\x02lt\x12\x12\n" +
+	"\x03lte\x18\b \x01(\tH\x00R\x03lte\x120\n" +
+	"\abetween\x18\t \x01(\v2\x14.grpcditto.api.RangeH\x00R\abetween\x12,\n" +
+	"\x02in\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.ListValueH\x00R\x02in\x12!\n" +
+	"\vstarts_with\x18\v \x01(\tH\x00R\n" +
+	"startsWith\x12\x1d\n" +
+	"\tends_with\x18\f \x01(\tH\x00R\bendsWith\x12\x18\n" +
+	"\x06absent\x18\r \x01(\bH\x00R\x06absent\x12\x19\n" +
+	"\ais_null\x18\x0e \x01(\bH\x00R\x06isNullB\n" +
+	"\n" +
+	"\boperator\"\x81\x03\n" +
+	"\fFieldPattern\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x10\n" +
+	"\x02eq\x18\x02 \x01(\tH\x00R\x02eq\x12\x10\n" +
+	"\x02gt\x18\x03 \x01(\tH\x00R\x02gt\x12\x12\n" +
+	"\x03gte\x18\x04 \x01(\tH\x00R\x03gte\x12\x10\n" +
+	"\x02lt\x18\x05 \x01(\tH\x00R\x02lt\x12\x12\n" +
+	"\x03lte\x18\x06 \x01(\tH\x00R\x03lte\x120\n" +
+	"\abetween\x18\a \x01(\v2\x14.grpcditto.api.RangeH\x00R\abetween\x12,\n" +
+	"\x02in\x18\b \x01(\v2\x1a.google.protobuf.ListValueH\x00R\x02in\x12\x1c\n" +
+	"\bcontains\x18\t \x01(\tH\x00R\bcontains\x12\x18\n" +
+	"\x06regexp\x18\n" +
+	" \x01(\tH\x00R\x06regexp\x12!\n" +
+	"\vstarts_with\x18\v \x01(\tH\x00R\n" +
+	"startsWith\x12\x1d\n" +
+	"\tends_with\x18\f \x01(\tH\x00R\bendsWith\x12\x17\n" +
+	"\x06is_set\x18\r \x01(\bH\x00R\x05isSetB\n" +
+	"\n" +
+	"\boperator\"+\n" +
+	"\x05Range\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\tR\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\tR\x03max\"\x0e\n" +
+	"\fClearRequest\"\x0f\n" +
+	"\rClearResponse\"\x1e\n" +
+	"\x1cListUnmatchedRequestsRequest\"\\\n" +
+	"\x1dListUnmatchedRequestsResponse\x12;\n" +
+	"\brequests\x18\x01 \x03(\v2\x1f.grpcditto.api.UnmatchedRequestR\brequests\"\xc5\x01\n" +
+	"\x10UnmatchedRequest\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12*\n" +
+	"\x04body\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x04body\x12=\n" +
+	"\n" +
+	"candidates\x18\x04 \x03(\v2\x1d.grpcditto.api.MatchCandidateR\n" +
+	"candidates\"\x1d\n" +
+	"\x1bListCanceledRequestsRequest\"Z\n" +
+	"\x1cListCanceledRequestsResponse\x12:\n" +
+	"\brequests\x18\x01 \x03(\v2\x1e.grpcditto.api.CanceledRequestR\brequests\"\xc4\x02\n" +
+	"\x0fCanceledRequest\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12*\n" +
+	"\x04body\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x04body\x12\x17\n" +
+	"\amock_id\x18\x04 \x01(\tR\x06mockId\x12$\n" +
+	"\x04code\x18\x05 \x01(\x0e2\x10.google.rpc.CodeR\x04code\x12\x14\n" +
+	"\x05stage\x18\x06 \x01(\tR\x05stage\x123\n" +
+	"\atimeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\atimeout\x123\n" +
+	"\aelapsed\x18\b \x01(\v2\x19.google.protobuf.DurationR\aelapsed\"\xbb\x01\n" +
+	"\x0eMatchCandidate\x12\x17\n" +
+	"\amock_id\x18\x01 \x01(\tR\x06mockId\x12)\n" +
+	"\x10matched_patterns\x18\x02 \x01(\x05R\x0fmatchedPatterns\x12%\n" +
+	"\x0etotal_patterns\x18\x03 \x01(\x05R\rtotalPatterns\x12>\n" +
+	"\n" +
+	"mismatches\x18\x04 \x03(\v2\x1e.grpcditto.api.PatternMismatchR\n" +
+	"mismatches\"\x96\x01\n" +
+	"\x0fPatternMismatch\x12#\n" +
+	"\rpattern_index\x18\x01 \x01(\x05R\fpatternIndex\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1a\n" +
+	"\bexpected\x18\x03 \x01(\tR\bexpected\x12\x16\n" +
+	"\x06actual\x18\x04 \x01(\tR\x06actual\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\xcb\x01\n" +
+	"\x0eStreamSelector\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12G\n" +
+	"\bmetadata\x18\x03 \x03(\v2+.grpcditto.api.StreamSelector.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"O\n" +
+	"\x12ListStreamsRequest\x129\n" +
+	"\bselector\x18\x01 \x01(\v2\x1d.grpcditto.api.StreamSelectorR\bselector\"J\n" +
+	"\x13ListStreamsResponse\x123\n" +
+	"\astreams\x18\x01 \x03(\v2\x19.grpcditto.api.OpenStreamR\astreams\"\xb7\x02\n" +
+	"\n" +
+	"OpenStream\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x124\n" +
+	"\astarted\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\astarted\x12C\n" +
+	"\bmetadata\x18\x04 \x03(\v2'.grpcditto.api.OpenStream.MetadataEntryR\bmetadata\x12*\n" +
+	"\x04body\x18\x05 \x01(\v2\x16.google.protobuf.ValueR\x04body\x1aZ\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.grpcditto.api.MetadataValuesR\x05value:\x028\x01\"(\n" +
+	"\x0eMetadataValues\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"|\n" +
+	"\x12PushMessageRequest\x129\n" +
+	"\bselector\x18\x01 \x01(\v2\x1d.grpcditto.api.StreamSelectorR\bselector\x12+\n" +
+	"\x04body\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x04body\"4\n" +
+	"\x13PushMessageResponse\x12\x1d\n" +
+	"\n" +
+	"stream_ids\x18\x01 \x03(\tR\tstreamIds\"\x81\x01\n" +
+	"\x12CloseStreamRequest\x129\n" +
+	"\bselector\x18\x01 \x01(\v2\x1d.grpcditto.api.StreamSelectorR\bselector\x120\n" +
+	"\x06status\x18\x02 \x01(\v2\x18.grpcditto.api.RpcStatusR\x06status\"4\n" +
+	"\x13CloseStreamResponse\x12\x1d\n" +
+	"\n" +
+	"stream_ids\x18\x01 \x03(\tR\tstreamIds\"\xbd\x03\n" +
+	"\tFaultRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12#\n" +
+	"\n" +
+	"percentage\x18\x03 \x01(\x01H\x00R\n" +
+	"percentage\x88\x01\x01\x12B\n" +
+	"\bmetadata\x18\x04 \x03(\v2&.grpcditto.api.FaultRule.MetadataEntryR\bmetadata\x12\x14\n" +
+	"\x05delay\x18\x05 \x01(\tR\x05delay\x120\n" +
+	"\x06status\x18\x06 \x01(\v2\x18.grpcditto.api.RpcStatusR\x06status\x12)\n" +
+	"\x10reset_connection\x18\a \x01(\bR\x0fresetConnection\x12*\n" +
+	"\x0eafter_messages\x18\b \x01(\rH\x01R\rafterMessages\x88\x01\x01\x12!\n" +
+	"\freset_stream\x18\t \x01(\bR\vresetStream\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\r\n" +
+	"\v_percentageB\x11\n" +
+	"\x0f_after_messages\"C\n" +
+	"\x13AddFaultRuleRequest\x12,\n" +
+	"\x04rule\x18\x01 \x01(\v2\x18.grpcditto.api.FaultRuleR\x04rule\"\x16\n" +
+	"\x14AddFaultRuleResponse\"\x17\n" +
+	"\x15ListFaultRulesRequest\"H\n" +
+	"\x16ListFaultRulesResponse\x12.\n" +
+	"\x05rules\x18\x01 \x03(\v2\x18.grpcditto.api.FaultRuleR\x05rules\"\x18\n" +
+	"\x16ClearFaultRulesRequest\"\x19\n" +
+	"\x17ClearFaultRulesResponse2\x9f\a\n" +
+	"\x0eMockingService\x12H\n" +
+	"\aAddMock\x12\x1d.grpcditto.api.AddMockRequest\x1a\x1e.grpcditto.api.AddMockResponse\x12B\n" +
+	"\x05Clear\x12\x1b.grpcditto.api.ClearRequest\x1a\x1c.grpcditto.api.ClearResponse\x12r\n" +
+	"\x15ListUnmatchedRequests\x12+.grpcditto.api.ListUnmatchedRequestsRequest\x1a,.grpcditto.api.ListUnmatchedRequestsResponse\x12o\n" +
+	"\x14ListCanceledRequests\x12*.grpcditto.api.ListCanceledRequestsRequest\x1a+.grpcditto.api.ListCanceledRequestsResponse\x12T\n" +
+	"\vListStreams\x12!.grpcditto.api.ListStreamsRequest\x1a\".grpcditto.api.ListStreamsResponse\x12T\n" +
+	"\vPushMessage\x12!.grpcditto.api.PushMessageRequest\x1a\".grpcditto.api.PushMessageResponse\x12T\n" +
+	"\vCloseStream\x12!.grpcditto.api.CloseStreamRequest\x1a\".grpcditto.api.CloseStreamResponse\x12W\n" +
+	"\fAddFaultRule\x12\".grpcditto.api.AddFaultRuleRequest\x1a#.grpcditto.api.AddFaultRuleResponse\x12]\n" +
+	"\x0eListFaultRules\x12$.grpcditto.api.ListFaultRulesRequest\x1a%.grpcditto.api.ListFaultRulesResponse\x12`\n" +
+	"\x0fClearFaultRules\x12%.grpcditto.api.ClearFaultRulesRequest\x1a&.grpcditto.api.ClearFaultRulesResponseB\x17Z\x05.;api\xaa\x02\rGrpcDitto.Apib\x06proto3"
 
 var (
 	file_mocking_service_proto_rawDescOnce sync.Once
-	file_mocking_service_proto_rawDescData = file_mocking_service_proto_rawDesc
+	file_mocking_service_proto_rawDescData []byte
 )
 
 func file_mocking_service_proto_rawDescGZIP() []byte {
 	file_mocking_service_proto_rawDescOnce.Do(func() {
-		file_mocking_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_mocking_service_proto_rawDescData)
+		file_mocking_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mocking_service_proto_rawDesc), len(file_mocking_service_proto_rawDesc)))
 	})
 	return file_mocking_service_proto_rawDescData
 }
 
-var file_mocking_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_mocking_service_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_mocking_service_proto_goTypes = []any{
-	(*AddMockRequest)(nil),   // 0: grpcditto.api.AddMockRequest
-	(*AddMockResponse)(nil),  // 1: grpcditto.api.AddMockResponse
-	(*DittoMock)(nil),        // 2: grpcditto.api.DittoMock
-	(*DittoRequest)(nil),     // 3: grpcditto.api.DittoRequest
-	(*DittoResponse)(nil),    // 4: grpcditto.api.DittoResponse
-	(*RpcStatus)(nil),        // 5: grpcditto.api.RpcStatus
-	(*DittoBodyPattern)(nil), // 6: grpcditto.api.DittoBodyPattern
-	(*JSONPathPattern)(nil),  // 7: grpcditto.api.JSONPathPattern
-	(*ClearRequest)(nil),     // 8: grpcditto.api.ClearRequest
-	(*ClearResponse)(nil),    // 9: grpcditto.api.ClearResponse
-	(*structpb.Struct)(nil),  // 10: google.protobuf.Struct
-	(code.Code)(0),           // 11: google.rpc.Code
+	(*AddMockRequest)(nil),                // 0: grpcditto.api.AddMockRequest
+	(*AddMockResponse)(nil),               // 1: grpcditto.api.AddMockResponse
+	(*DittoMock)(nil),                     // 2: grpcditto.api.DittoMock
+	(*WeightedResponse)(nil),              // 3: grpcditto.api.WeightedResponse
+	(*StreamOptions)(nil),                 // 4: grpcditto.api.StreamOptions
+	(*Heartbeat)(nil),                     // 5: grpcditto.api.Heartbeat
+	(*DittoRequest)(nil),                  // 6: grpcditto.api.DittoRequest
+	(*DittoResponse)(nil),                 // 7: grpcditto.api.DittoResponse
+	(*ResponseGenerator)(nil),             // 8: grpcditto.api.ResponseGenerator
+	(*RpcStatus)(nil),                     // 9: grpcditto.api.RpcStatus
+	(*DittoBodyPattern)(nil),              // 10: grpcditto.api.DittoBodyPattern
+	(*DeadlinePattern)(nil),               // 11: grpcditto.api.DeadlinePattern
+	(*MessageCount)(nil),                  // 12: grpcditto.api.MessageCount
+	(*SequenceStep)(nil),                  // 13: grpcditto.api.SequenceStep
+	(*JSONPathPattern)(nil),               // 14: grpcditto.api.JSONPathPattern
+	(*FieldPattern)(nil),                  // 15: grpcditto.api.FieldPattern
+	(*Range)(nil),                         // 16: grpcditto.api.Range
+	(*ClearRequest)(nil),                  // 17: grpcditto.api.ClearRequest
+	(*ClearResponse)(nil),                 // 18: grpcditto.api.ClearResponse
+	(*ListUnmatchedRequestsRequest)(nil),  // 19: grpcditto.api.ListUnmatchedRequestsRequest
+	(*ListUnmatchedRequestsResponse)(nil), // 20: grpcditto.api.ListUnmatchedRequestsResponse
+	(*UnmatchedRequest)(nil),              // 21: grpcditto.api.UnmatchedRequest
+	(*ListCanceledRequestsRequest)(nil),   // 22: grpcditto.api.ListCanceledRequestsRequest
+	(*ListCanceledRequestsResponse)(nil),  // 23: grpcditto.api.ListCanceledRequestsResponse
+	(*CanceledRequest)(nil),               // 24: grpcditto.api.CanceledRequest
+	(*MatchCandidate)(nil),                // 25: grpcditto.api.MatchCandidate
+	(*PatternMismatch)(nil),               // 26: grpcditto.api.PatternMismatch
+	(*StreamSelector)(nil),                // 27: grpcditto.api.StreamSelector
+	(*ListStreamsRequest)(nil),            // 28: grpcditto.api.ListStreamsRequest
+	(*ListStreamsResponse)(nil),           // 29: grpcditto.api.ListStreamsResponse
+	(*OpenStream)(nil),                    // 30: grpcditto.api.OpenStream
+	(*MetadataValues)(nil),                // 31: grpcditto.api.MetadataValues
+	(*PushMessageRequest)(nil),            // 32: grpcditto.api.PushMessageRequest
+	(*PushMessageResponse)(nil),           // 33: grpcditto.api.PushMessageResponse
+	(*CloseStreamRequest)(nil),            // 34: grpcditto.api.CloseStreamRequest
+	(*CloseStreamResponse)(nil),           // 35: grpcditto.api.CloseStreamResponse
+	(*FaultRule)(nil),                     // 36: grpcditto.api.FaultRule
+	(*AddFaultRuleRequest)(nil),           // 37: grpcditto.api.AddFaultRuleRequest
+	(*AddFaultRuleResponse)(nil),          // 38: grpcditto.api.AddFaultRuleResponse
+	(*ListFaultRulesRequest)(nil),         // 39: grpcditto.api.ListFaultRulesRequest
+	(*ListFaultRulesResponse)(nil),        // 40: grpcditto.api.ListFaultRulesResponse
+	(*ClearFaultRulesRequest)(nil),        // 41: grpcditto.api.ClearFaultRulesRequest
+	(*ClearFaultRulesResponse)(nil),       // 42: grpcditto.api.ClearFaultRulesResponse
+	nil,                                   // 43: grpcditto.api.StreamSelector.MetadataEntry
+	nil,                                   // 44: grpcditto.api.OpenStream.MetadataEntry
+	nil,                                   // 45: grpcditto.api.FaultRule.MetadataEntry
+	(*structpb.Struct)(nil),               // 46: google.protobuf.Struct
+	(code.Code)(0),                        // 47: google.rpc.Code
+	(*structpb.ListValue)(nil),            // 48: google.protobuf.ListValue
+	(*timestamppb.Timestamp)(nil),         // 49: google.protobuf.Timestamp
+	(*structpb.Value)(nil),                // 50: google.protobuf.Value
+	(*durationpb.Duration)(nil),           // 51: google.protobuf.Duration
 }
 var file_mocking_service_proto_depIdxs = []int32{
 	2,  // 0: grpcditto.api.AddMockRequest.mock:type_name -> grpcditto.api.DittoMock
-	3,  // 1: grpcditto.api.DittoMock.request:type_name -> grpcditto.api.DittoRequest
-	4,  // 2: grpcditto.api.DittoMock.response:type_name -> grpcditto.api.DittoResponse
-	6,  // 3: grpcditto.api.DittoRequest.body_patterns:type_name -> grpcditto.api.DittoBodyPattern
-	10, // 4: grpcditto.api.DittoResponse.body:type_name -> google.protobuf.Struct
-	5,  // 5: grpcditto.api.DittoResponse.status:type_name -> grpcditto.api.RpcStatus
-	11, // 6: grpcditto.api.RpcStatus.code:type_name -> google.rpc.Code
-	10, // 7: grpcditto.api.DittoBodyPattern.equal_to_json:type_name -> google.protobuf.Struct
-	7,  // 8: grpcditto.api.DittoBodyPattern.matches_jsonpath:type_name -> grpcditto.api.JSONPathPattern
-	0,  // 9: grpcditto.api.MockingService.AddMock:input_type -> grpcditto.api.AddMockRequest
-	8,  // 10: grpcditto.api.MockingService.Clear:input_type -> grpcditto.api.ClearRequest
-	1,  // 11: grpcditto.api.MockingService.AddMock:output_type -> grpcditto.api.AddMockResponse
-	9,  // 12: grpcditto.api.MockingService.Clear:output_type -> grpcditto.api.ClearResponse
-	11, // [11:13] is the sub-list for method output_type
-	9,  // [9:11] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	6,  // 1: grpcditto.api.DittoMock.request:type_name -> grpcditto.api.DittoRequest
+	7,  // 2: grpcditto.api.DittoMock.response:type_name -> grpcditto.api.DittoResponse
+	4,  // 3: grpcditto.api.DittoMock.stream:type_name -> grpcditto.api.StreamOptions
+	3,  // 4: grpcditto.api.DittoMock.responses:type_name -> grpcditto.api.WeightedResponse
+	7,  // 5: grpcditto.api.WeightedResponse.response:type_name -> grpcditto.api.DittoResponse
+	5,  // 6: grpcditto.api.StreamOptions.heartbeat:type_name -> grpcditto.api.Heartbeat
+	10, // 7: grpcditto.api.DittoRequest.body_patterns:type_name -> grpcditto.api.DittoBodyPattern
+	46, // 8: grpcditto.api.DittoResponse.body:type_name -> google.protobuf.Struct
+	9,  // 9: grpcditto.api.DittoResponse.status:type_name -> grpcditto.api.RpcStatus
+	8,  // 10: grpcditto.api.DittoResponse.generate:type_name -> grpcditto.api.ResponseGenerator
+	47, // 11: grpcditto.api.RpcStatus.code:type_name -> google.rpc.Code
+	46, // 12: grpcditto.api.DittoBodyPattern.equal_to_json:type_name -> google.protobuf.Struct
+	14, // 13: grpcditto.api.DittoBodyPattern.matches_jsonpath:type_name -> grpcditto.api.JSONPathPattern
+	46, // 14: grpcditto.api.DittoBodyPattern.includes_json:type_name -> google.protobuf.Struct
+	15, // 15: grpcditto.api.DittoBodyPattern.matches_field:type_name -> grpcditto.api.FieldPattern
+	10, // 16: grpcditto.api.DittoBodyPattern.any_of:type_name -> grpcditto.api.DittoBodyPattern
+	10, // 17: grpcditto.api.DittoBodyPattern.all_of:type_name -> grpcditto.api.DittoBodyPattern
+	10, // 18: grpcditto.api.DittoBodyPattern.not:type_name -> grpcditto.api.DittoBodyPattern
+	10, // 19: grpcditto.api.DittoBodyPattern.any_message:type_name -> grpcditto.api.DittoBodyPattern
+	10, // 20: grpcditto.api.DittoBodyPattern.every_message:type_name -> grpcditto.api.DittoBodyPattern
+	12, // 21: grpcditto.api.DittoBodyPattern.message_count:type_name -> grpcditto.api.MessageCount
+	13, // 22: grpcditto.api.DittoBodyPattern.sequence:type_name -> grpcditto.api.SequenceStep
+	11, // 23: grpcditto.api.DittoBodyPattern.deadline:type_name -> grpcditto.api.DeadlinePattern
+	10, // 24: grpcditto.api.SequenceStep.message:type_name -> grpcditto.api.DittoBodyPattern
+	16, // 25: grpcditto.api.JSONPathPattern.between:type_name -> grpcditto.api.Range
+	48, // 26: grpcditto.api.JSONPathPattern.in:type_name -> google.protobuf.ListValue
+	16, // 27: grpcditto.api.FieldPattern.between:type_name -> grpcditto.api.Range
+	48, // 28: grpcditto.api.FieldPattern.in:type_name -> google.protobuf.ListValue
+	21, // 29: grpcditto.api.ListUnmatchedRequestsResponse.requests:type_name -> grpcditto.api.UnmatchedRequest
+	49, // 30: grpcditto.api.UnmatchedRequest.time:type_name -> google.protobuf.Timestamp
+	50, // 31: grpcditto.api.UnmatchedRequest.body:type_name -> google.protobuf.Value
+	25, // 32: grpcditto.api.UnmatchedRequest.candidates:type_name -> grpcditto.api.MatchCandidate
+	24, // 33: grpcditto.api.ListCanceledRequestsResponse.requests:type_name -> grpcditto.api.CanceledRequest
+	49, // 34: grpcditto.api.CanceledRequest.time:type_name -> google.protobuf.Timestamp
+	50, // 35: grpcditto.api.CanceledRequest.body:type_name -> google.protobuf.Value
+	47, // 36: grpcditto.api.CanceledRequest.code:type_name -> google.rpc.Code
+	51, // 37: grpcditto.api.CanceledRequest.timeout:type_name -> google.protobuf.Duration
+	51, // 38: grpcditto.api.CanceledRequest.elapsed:type_name -> google.protobuf.Duration
+	26, // 39: grpcditto.api.MatchCandidate.mismatches:type_name -> grpcditto.api.PatternMismatch
+	43, // 40: grpcditto.api.StreamSelector.metadata:type_name -> grpcditto.api.StreamSelector.MetadataEntry
+	27, // 41: grpcditto.api.ListStreamsRequest.selector:type_name -> grpcditto.api.StreamSelector
+	30, // 42: grpcditto.api.ListStreamsResponse.streams:type_name -> grpcditto.api.OpenStream
+	49, // 43: grpcditto.api.OpenStream.started:type_name -> google.protobuf.Timestamp
+	44, // 44: grpcditto.api.OpenStream.metadata:type_name -> grpcditto.api.OpenStream.MetadataEntry
+	50, // 45: grpcditto.api.OpenStream.body:type_name -> google.protobuf.Value
+	27, // 46: grpcditto.api.PushMessageRequest.selector:type_name -> grpcditto.api.StreamSelector
+	46, // 47: grpcditto.api.PushMessageRequest.body:type_name -> google.protobuf.Struct
+	27, // 48: grpcditto.api.CloseStreamRequest.selector:type_name -> grpcditto.api.StreamSelector
+	9,  // 49: grpcditto.api.CloseStreamRequest.status:type_name -> grpcditto.api.RpcStatus
+	45, // 50: grpcditto.api.FaultRule.metadata:type_name -> grpcditto.api.FaultRule.MetadataEntry
+	9,  // 51: grpcditto.api.FaultRule.status:type_name -> grpcditto.api.RpcStatus
+	36, // 52: grpcditto.api.AddFaultRuleRequest.rule:type_name -> grpcditto.api.FaultRule
+	36, // 53: grpcditto.api.ListFaultRulesResponse.rules:type_name -> grpcditto.api.FaultRule
+	31, // 54: grpcditto.api.OpenStream.MetadataEntry.value:type_name -> grpcditto.api.MetadataValues
+	0,  // 55: grpcditto.api.MockingService.AddMock:input_type -> grpcditto.api.AddMockRequest
+	17, // 56: grpcditto.api.MockingService.Clear:input_type -> grpcditto.api.ClearRequest
+	19, // 57: grpcditto.api.MockingService.ListUnmatchedRequests:input_type -> grpcditto.api.ListUnmatchedRequestsRequest
+	22, // 58: grpcditto.api.MockingService.ListCanceledRequests:input_type -> grpcditto.api.ListCanceledRequestsRequest
+	28, // 59: grpcditto.api.MockingService.ListStreams:input_type -> grpcditto.api.ListStreamsRequest
+	32, // 60: grpcditto.api.MockingService.PushMessage:input_type -> grpcditto.api.PushMessageRequest
+	34, // 61: grpcditto.api.MockingService.CloseStream:input_type -> grpcditto.api.CloseStreamRequest
+	37, // 62: grpcditto.api.MockingService.AddFaultRule:input_type -> grpcditto.api.AddFaultRuleRequest
+	39, // 63: grpcditto.api.MockingService.ListFaultRules:input_type -> grpcditto.api.ListFaultRulesRequest
+	41, // 64: grpcditto.api.MockingService.ClearFaultRules:input_type -> grpcditto.api.ClearFaultRulesRequest
+	1,  // 65: grpcditto.api.MockingService.AddMock:output_type -> grpcditto.api.AddMockResponse
+	18, // 66: grpcditto.api.MockingService.Clear:output_type -> grpcditto.api.ClearResponse
+	20, // 67: grpcditto.api.MockingService.ListUnmatchedRequests:output_type -> grpcditto.api.ListUnmatchedRequestsResponse
+	23, // 68: grpcditto.api.MockingService.ListCanceledRequests:output_type -> grpcditto.api.ListCanceledRequestsResponse
+	29, // 69: grpcditto.api.MockingService.ListStreams:output_type -> grpcditto.api.ListStreamsResponse
+	33, // 70: grpcditto.api.MockingService.PushMessage:output_type -> grpcditto.api.PushMessageResponse
+	35, // 71: grpcditto.api.MockingService.CloseStream:output_type -> grpcditto.api.CloseStreamResponse
+	38, // 72: grpcditto.api.MockingService.AddFaultRule:output_type -> grpcditto.api.AddFaultRuleResponse
+	40, // 73: grpcditto.api.MockingService.ListFaultRules:output_type -> grpcditto.api.ListFaultRulesResponse
+	42, // 74: grpcditto.api.MockingService.ClearFaultRules:output_type -> grpcditto.api.ClearFaultRulesResponse
+	65, // [65:75] is the sub-list for method output_type
+	55, // [55:65] is the sub-list for method input_type
+	55, // [55:55] is the sub-list for extension type_name
+	55, // [55:55] is the sub-list for extension extendee
+	0,  // [0:55] is the sub-list for field type_name
 }
 
 func init() { file_mocking_service_proto_init() }
@@ -776,149 +3529,68 @@ func file_mocking_service_proto_init() {
 	if File_mocking_service_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_mocking_service_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*AddMockRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*AddMockResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*DittoMock); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*DittoRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*DittoResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*RpcStatus); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*DittoBodyPattern); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*JSONPathPattern); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*ClearRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_mocking_service_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*ClearResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
-	file_mocking_service_proto_msgTypes[4].OneofWrappers = []any{
+	file_mocking_service_proto_msgTypes[3].OneofWrappers = []any{}
+	file_mocking_service_proto_msgTypes[7].OneofWrappers = []any{
 		(*DittoResponse_Body)(nil),
 		(*DittoResponse_Status)(nil),
 		(*DittoResponse_BodyTemplate)(nil),
+		(*DittoResponse_Generate)(nil),
 	}
-	file_mocking_service_proto_msgTypes[6].OneofWrappers = []any{
+	file_mocking_service_proto_msgTypes[8].OneofWrappers = []any{
+		(*ResponseGenerator_Repeat)(nil),
+		(*ResponseGenerator_Foreach)(nil),
+	}
+	file_mocking_service_proto_msgTypes[10].OneofWrappers = []any{
 		(*DittoBodyPattern_EqualToJson)(nil),
 		(*DittoBodyPattern_MatchesJsonpath)(nil),
+		(*DittoBodyPattern_IncludesJson)(nil),
+		(*DittoBodyPattern_MatchesCel)(nil),
+		(*DittoBodyPattern_MatchesField)(nil),
 	}
-	file_mocking_service_proto_msgTypes[7].OneofWrappers = []any{
+	file_mocking_service_proto_msgTypes[11].OneofWrappers = []any{}
+	file_mocking_service_proto_msgTypes[12].OneofWrappers = []any{}
+	file_mocking_service_proto_msgTypes[13].OneofWrappers = []any{
+		(*SequenceStep_Message)(nil),
+		(*SequenceStep_Any)(nil),
+		(*SequenceStep_Skip)(nil),
+	}
+	file_mocking_service_proto_msgTypes[14].OneofWrappers = []any{
 		(*JSONPathPattern_Contains)(nil),
 		(*JSONPathPattern_Eq)(nil),
 		(*JSONPathPattern_Regexp)(nil),
+		(*JSONPathPattern_Gt)(nil),
+		(*JSONPathPattern_Gte)(nil),
+		(*JSONPathPattern_Lt)(nil),
+		(*JSONPathPattern_Lte)(nil),
+		(*JSONPathPattern_Between)(nil),
+		(*JSONPathPattern_In)(nil),
+		(*JSONPathPattern_StartsWith)(nil),
+		(*JSONPathPattern_EndsWith)(nil),
+		(*JSONPathPattern_Absent)(nil),
+		(*JSONPathPattern_IsNull)(nil),
+	}
+	file_mocking_service_proto_msgTypes[15].OneofWrappers = []any{
+		(*FieldPattern_Eq)(nil),
+		(*FieldPattern_Gt)(nil),
+		(*FieldPattern_Gte)(nil),
+		(*FieldPattern_Lt)(nil),
+		(*FieldPattern_Lte)(nil),
+		(*FieldPattern_Between)(nil),
+		(*FieldPattern_In)(nil),
+		(*FieldPattern_Contains)(nil),
+		(*FieldPattern_Regexp)(nil),
+		(*FieldPattern_StartsWith)(nil),
+		(*FieldPattern_EndsWith)(nil),
+		(*FieldPattern_IsSet)(nil),
 	}
+	file_mocking_service_proto_msgTypes[36].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_mocking_service_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mocking_service_proto_rawDesc), len(file_mocking_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -927,7 +3599,6 @@ func file_mocking_service_proto_init() {
 		MessageInfos:      file_mocking_service_proto_msgTypes,
 	}.Build()
 	File_mocking_service_proto = out.File
-	file_mocking_service_proto_rawDesc = nil
 	file_mocking_service_proto_goTypes = nil
 	file_mocking_service_proto_depIdxs = nil
 }
@@ -19,8 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MockingService_AddMock_FullMethodName = "/grpcditto.api.MockingService/AddMock"
-	MockingService_Clear_FullMethodName   = "/grpcditto.api.MockingService/Clear"
+	MockingService_AddMock_FullMethodName               = "/grpcditto.api.MockingService/AddMock"
+	MockingService_Clear_FullMethodName                 = "/grpcditto.api.MockingService/Clear"
+	MockingService_ListUnmatchedRequests_FullMethodName = "/grpcditto.api.MockingService/ListUnmatchedRequests"
+	MockingService_ListCanceledRequests_FullMethodName  = "/grpcditto.api.MockingService/ListCanceledRequests"
+	MockingService_ListStreams_FullMethodName           = "/grpcditto.api.MockingService/ListStreams"
+	MockingService_PushMessage_FullMethodName           = "/grpcditto.api.MockingService/PushMessage"
+	MockingService_CloseStream_FullMethodName           = "/grpcditto.api.MockingService/CloseStream"
+	MockingService_AddFaultRule_FullMethodName          = "/grpcditto.api.MockingService/AddFaultRule"
+	MockingService_ListFaultRules_FullMethodName        = "/grpcditto.api.MockingService/ListFaultRules"
+	MockingService_ClearFaultRules_FullMethodName       = "/grpcditto.api.MockingService/ClearFaultRules"
 )
 
 // MockingServiceClient is the client API for MockingService service.
@@ -29,8 +37,25 @@ const (
 type MockingServiceClient interface {
 	// AddMock adds new mock to the server
 	AddMock(ctx context.Context, in *AddMockRequest, opts ...grpc.CallOption) (*AddMockResponse, error)
-	// Delete all mocks
+	// Delete all mocks and unmatched requests
 	Clear(ctx context.Context, in *ClearRequest, opts ...grpc.CallOption) (*ClearResponse, error)
+	// ListUnmatchedRequests returns recent requests that didn't match any mock
+	// together with the closest mocks and the reasons they didn't match
+	ListUnmatchedRequests(ctx context.Context, in *ListUnmatchedRequestsRequest, opts ...grpc.CallOption) (*ListUnmatchedRequestsResponse, error)
+	// ListCanceledRequests returns recent calls that were canceled by the client or exceeded the client deadline
+	ListCanceledRequests(ctx context.Context, in *ListCanceledRequestsRequest, opts ...grpc.CallOption) (*ListCanceledRequestsResponse, error)
+	// ListStreams returns server and bidi streams that are currently open
+	ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error)
+	// PushMessage sends the message to the selected open streams
+	PushMessage(ctx context.Context, in *PushMessageRequest, opts ...grpc.CallOption) (*PushMessageResponse, error)
+	// CloseStream finishes the selected open streams with the status
+	CloseStream(ctx context.Context, in *CloseStreamRequest, opts ...grpc.CallOption) (*CloseStreamResponse, error)
+	// AddFaultRule adds new fault rule, rules are evaluated in the order they are added
+	AddFaultRule(ctx context.Context, in *AddFaultRuleRequest, opts ...grpc.CallOption) (*AddFaultRuleResponse, error)
+	// ListFaultRules returns active fault rules
+	ListFaultRules(ctx context.Context, in *ListFaultRulesRequest, opts ...grpc.CallOption) (*ListFaultRulesResponse, error)
+	// ClearFaultRules deletes all fault rules
+	ClearFaultRules(ctx context.Context, in *ClearFaultRulesRequest, opts ...grpc.CallOption) (*ClearFaultRulesResponse, error)
 }
 
 type mockingServiceClient struct {
@@ -61,14 +86,111 @@ func (c *mockingServiceClient) Clear(ctx context.Context, in *ClearRequest, opts
 	return out, nil
 }
 
+func (c *mockingServiceClient) ListUnmatchedRequests(ctx context.Context, in *ListUnmatchedRequestsRequest, opts ...grpc.CallOption) (*ListUnmatchedRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUnmatchedRequestsResponse)
+	err := c.cc.Invoke(ctx, MockingService_ListUnmatchedRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) ListCanceledRequests(ctx context.Context, in *ListCanceledRequestsRequest, opts ...grpc.CallOption) (*ListCanceledRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCanceledRequestsResponse)
+	err := c.cc.Invoke(ctx, MockingService_ListCanceledRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) ListStreams(ctx context.Context, in *ListStreamsRequest, opts ...grpc.CallOption) (*ListStreamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStreamsResponse)
+	err := c.cc.Invoke(ctx, MockingService_ListStreams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) PushMessage(ctx context.Context, in *PushMessageRequest, opts ...grpc.CallOption) (*PushMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PushMessageResponse)
+	err := c.cc.Invoke(ctx, MockingService_PushMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) CloseStream(ctx context.Context, in *CloseStreamRequest, opts ...grpc.CallOption) (*CloseStreamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseStreamResponse)
+	err := c.cc.Invoke(ctx, MockingService_CloseStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) AddFaultRule(ctx context.Context, in *AddFaultRuleRequest, opts ...grpc.CallOption) (*AddFaultRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddFaultRuleResponse)
+	err := c.cc.Invoke(ctx, MockingService_AddFaultRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) ListFaultRules(ctx context.Context, in *ListFaultRulesRequest, opts ...grpc.CallOption) (*ListFaultRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFaultRulesResponse)
+	err := c.cc.Invoke(ctx, MockingService_ListFaultRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mockingServiceClient) ClearFaultRules(ctx context.Context, in *ClearFaultRulesRequest, opts ...grpc.CallOption) (*ClearFaultRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearFaultRulesResponse)
+	err := c.cc.Invoke(ctx, MockingService_ClearFaultRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MockingServiceServer is the server API for MockingService service.
 // All implementations must embed UnimplementedMockingServiceServer
 // for forward compatibility.
 type MockingServiceServer interface {
 	// AddMock adds new mock to the server
 	AddMock(context.Context, *AddMockRequest) (*AddMockResponse, error)
-	// Delete all mocks
+	// Delete all mocks and unmatched requests
 	Clear(context.Context, *ClearRequest) (*ClearResponse, error)
+	// ListUnmatchedRequests returns recent requests that didn't match any mock
+	// together with the closest mocks and the reasons they didn't match
+	ListUnmatchedRequests(context.Context, *ListUnmatchedRequestsRequest) (*ListUnmatchedRequestsResponse, error)
+	// ListCanceledRequests returns recent calls that were canceled by the client or exceeded the client deadline
+	ListCanceledRequests(context.Context, *ListCanceledRequestsRequest) (*ListCanceledRequestsResponse, error)
+	// ListStreams returns server and bidi streams that are currently open
+	ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error)
+	// PushMessage sends the message to the selected open streams
+	PushMessage(context.Context, *PushMessageRequest) (*PushMessageResponse, error)
+	// CloseStream finishes the selected open streams with the status
+	CloseStream(context.Context, *CloseStreamRequest) (*CloseStreamResponse, error)
+	// AddFaultRule adds new fault rule, rules are evaluated in the order they are added
+	AddFaultRule(context.Context, *AddFaultRuleRequest) (*AddFaultRuleResponse, error)
+	// ListFaultRules returns active fault rules
+	ListFaultRules(context.Context, *ListFaultRulesRequest) (*ListFaultRulesResponse, error)
+	// ClearFaultRules deletes all fault rules
+	ClearFaultRules(context.Context, *ClearFaultRulesRequest) (*ClearFaultRulesResponse, error)
 	mustEmbedUnimplementedMockingServiceServer()
 }
 
@@ -85,6 +207,30 @@ func (UnimplementedMockingServiceServer) AddMock(context.Context, *AddMockReques
 func (UnimplementedMockingServiceServer) Clear(context.Context, *ClearRequest) (*ClearResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Clear not implemented")
 }
+func (UnimplementedMockingServiceServer) ListUnmatchedRequests(context.Context, *ListUnmatchedRequestsRequest) (*ListUnmatchedRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnmatchedRequests not implemented")
+}
+func (UnimplementedMockingServiceServer) ListCanceledRequests(context.Context, *ListCanceledRequestsRequest) (*ListCanceledRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCanceledRequests not implemented")
+}
+func (UnimplementedMockingServiceServer) ListStreams(context.Context, *ListStreamsRequest) (*ListStreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStreams not implemented")
+}
+func (UnimplementedMockingServiceServer) PushMessage(context.Context, *PushMessageRequest) (*PushMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushMessage not implemented")
+}
+func (UnimplementedMockingServiceServer) CloseStream(context.Context, *CloseStreamRequest) (*CloseStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseStream not implemented")
+}
+func (UnimplementedMockingServiceServer) AddFaultRule(context.Context, *AddFaultRuleRequest) (*AddFaultRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddFaultRule not implemented")
+}
+func (UnimplementedMockingServiceServer) ListFaultRules(context.Context, *ListFaultRulesRequest) (*ListFaultRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFaultRules not implemented")
+}
+func (UnimplementedMockingServiceServer) ClearFaultRules(context.Context, *ClearFaultRulesRequest) (*ClearFaultRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearFaultRules not implemented")
+}
 func (UnimplementedMockingServiceServer) mustEmbedUnimplementedMockingServiceServer() {}
 func (UnimplementedMockingServiceServer) testEmbeddedByValue()                        {}
 
@@ -142,6 +288,150 @@ func _MockingService_Clear_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MockingService_ListUnmatchedRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnmatchedRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).ListUnmatchedRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_ListUnmatchedRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).ListUnmatchedRequests(ctx, req.(*ListUnmatchedRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_ListCanceledRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCanceledRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).ListCanceledRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_ListCanceledRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).ListCanceledRequests(ctx, req.(*ListCanceledRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_ListStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).ListStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_ListStreams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).ListStreams(ctx, req.(*ListStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_PushMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PushMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).PushMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_PushMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).PushMessage(ctx, req.(*PushMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_CloseStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).CloseStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_CloseStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).CloseStream(ctx, req.(*CloseStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_AddFaultRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddFaultRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).AddFaultRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_AddFaultRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).AddFaultRule(ctx, req.(*AddFaultRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_ListFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFaultRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).ListFaultRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_ListFaultRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).ListFaultRules(ctx, req.(*ListFaultRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MockingService_ClearFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearFaultRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MockingServiceServer).ClearFaultRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MockingService_ClearFaultRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MockingServiceServer).ClearFaultRules(ctx, req.(*ClearFaultRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MockingService_ServiceDesc is the grpc.ServiceDesc for MockingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -157,6 +447,38 @@ var MockingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Clear",
 			Handler:    _MockingService_Clear_Handler,
 		},
+		{
+			MethodName: "ListUnmatchedRequests",
+			Handler:    _MockingService_ListUnmatchedRequests_Handler,
+		},
+		{
+			MethodName: "ListCanceledRequests",
+			Handler:    _MockingService_ListCanceledRequests_Handler,
+		},
+		{
+			MethodName: "ListStreams",
+			Handler:    _MockingService_ListStreams_Handler,
+		},
+		{
+			MethodName: "PushMessage",
+			Handler:    _MockingService_PushMessage_Handler,
+		},
+		{
+			MethodName: "CloseStream",
+			Handler:    _MockingService_CloseStream_Handler,
+		},
+		{
+			MethodName: "AddFaultRule",
+			Handler:    _MockingService_AddFaultRule_Handler,
+		},
+		{
+			MethodName: "ListFaultRules",
+			Handler:    _MockingService_ListFaultRules_Handler,
+		},
+		{
+			MethodName: "ClearFaultRules",
+			Handler:    _MockingService_ClearFaultRules_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "mocking_service.proto",
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"sigs.k8s.io/yaml"
+)
+
+// serverConfig is the format of --config file, every setting has the flag of the same meaning.
+// Flags and environment variables take precedence over the file, relative paths are resolved
+// against the directory of the file.
+type serverConfig struct {
+	Listen   []string    `json:"listen"`
+	PortFile string      `json:"port_file"`
+	Proto    protoConfig `json:"proto"`
+	Mocks    stringList  `json:"mocks"`
+	// MocksInclude and MocksExclude filter mock files found in directories and by globs
+	MocksInclude []string        `json:"mocks_include"`
+	MocksExclude []string        `json:"mocks_exclude"`
+	TLS          tlsConfig       `json:"tls"`
+	Unmatched    string          `json:"unmatched"`
+	Services     []serviceConfig `json:"services"`
+	Admin        adminConfig     `json:"admin"`
+	Log          logConfig       `json:"log"`
+	Tracing      tracingConfig   `json:"tracing"`
+	Faults       string          `json:"faults"`
+	Seed         *int64          `json:"seed"`
+}
+
+// stringList is a list that can also be written as a single string
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+type protoConfig struct {
+	Paths   []string `json:"paths"`
+	Imports []string `json:"imports"`
+}
+
+type tlsConfig struct {
+	CertFile     string `json:"cert_file"`
+	KeyFile      string `json:"key_file"`
+	ClientCAFile string `json:"client_ca_file"`
+}
+
+// serviceConfig holds per service settings
+type serviceConfig struct {
+	// Name is fully qualified service name, listen also accepts globs like users.*
+	Name string `json:"name"`
+	// Listen serves the service on its own address, services with the same address share the listener
+	Listen    string `json:"listen"`
+	Unmatched string `json:"unmatched"`
+}
+
+type adminConfig struct {
+	Listen      []string `json:"listen"`
+	MetricsPort int      `json:"metrics_port"`
+}
+
+type logConfig struct {
+	Level            string   `json:"level"`
+	Format           string   `json:"format"`
+	AccessLog        string   `json:"access_log"`
+	AccessLogRedact  []string `json:"access_log_redact"`
+	AccessLogMaxBody *int     `json:"access_log_max_body"`
+}
+
+type tracingConfig struct {
+	Exporter string `json:"exporter"`
+	Endpoint string `json:"endpoint"`
+	Echo     bool   `json:"echo"`
+}
+
+// loadConfig reads the config file, unknown settings are errors
+func loadConfig(path string) (*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &serverConfig{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	cfg.resolvePaths(filepath.Dir(path))
+	return cfg, nil
+}
+
+func (c *serverConfig) validate() error {
+	for i, svc := range c.Services {
+		switch {
+		case svc.Name == "":
+			return fmt.Errorf("services[%d]: name is required", i)
+		case svc.Listen == "" && svc.Unmatched == "":
+			return fmt.Errorf("services[%d] %s: listen or unmatched is required", i, svc.Name)
+		case svc.Unmatched != "" && strings.ContainsAny(svc.Name, "*?["):
+			return fmt.Errorf("services[%d] %s: unmatched requires service name, not a pattern", i, svc.Name)
+		}
+	}
+
+	return nil
+}
+
+func (c *serverConfig) resolvePaths(dir string) {
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	for i := range c.Proto.Paths {
+		c.Proto.Paths[i] = resolve(c.Proto.Paths[i])
+	}
+	for i := range c.Proto.Imports {
+		c.Proto.Imports[i] = resolve(c.Proto.Imports[i])
+	}
+
+	for i, p := range c.Mocks {
+		if p != dittomock.StdinPath {
+			c.Mocks[i] = resolve(p)
+		}
+	}
+	c.PortFile = resolve(c.PortFile)
+	c.Faults = resolve(c.Faults)
+	c.TLS.CertFile = resolve(c.TLS.CertFile)
+	c.TLS.KeyFile = resolve(c.TLS.KeyFile)
+	c.TLS.ClientCAFile = resolve(c.TLS.ClientCAFile)
+
+	if c.Log.AccessLog != "stdout" && c.Log.AccessLog != "stderr" {
+		c.Log.AccessLog = resolve(c.Log.AccessLog)
+	}
+}
+
+// applyConfig sets the flags that are not set on the command line or by environment variables
+func applyConfig(ctx *cli.Context, c *serverConfig) error {
+	var err error
+	set := func(name string, values ...string) {
+		if err != nil || ctx.IsSet(name) {
+			return
+		}
+		for _, v := range values {
+			if v == "" {
+				continue
+			}
+			if setErr := ctx.Set(name, v); setErr != nil {
+				err = fmt.Errorf("%s: %w", name, setErr)
+				return
+			}
+		}
+	}
+
+	set("listen", c.Listen...)
+	set("port-file", c.PortFile)
+	set("proto", c.Proto.Paths...)
+	set("protoimports", c.Proto.Imports...)
+	set("mocks", c.Mocks...)
+	set("mocks-include", c.MocksInclude...)
+	set("mocks-exclude", c.MocksExclude...)
+	set("tls-cert", c.TLS.CertFile)
+	set("tls-key", c.TLS.KeyFile)
+	set("tls-client-ca", c.TLS.ClientCAFile)
+	set("unmatched", c.Unmatched)
+	set("admin-listen", c.Admin.Listen...)
+	set("loglevel", c.Log.Level)
+	set("log-format", c.Log.Format)
+	set("access-log", c.Log.AccessLog)
+	set("access-log-redact", c.Log.AccessLogRedact...)
+	set("trace-exporter", c.Tracing.Exporter)
+	set("trace-endpoint", c.Tracing.Endpoint)
+	set("faults", c.Faults)
+
+	if c.Admin.MetricsPort > 0 {
+		set("metrics-port", strconv.Itoa(c.Admin.MetricsPort))
+	}
+	if c.Log.AccessLogMaxBody != nil {
+		set("access-log-max-body", strconv.Itoa(*c.Log.AccessLogMaxBody))
+	}
+	if c.Tracing.Echo {
+		set("trace-echo", "true")
+	}
+	if c.Seed != nil {
+		set("seed", strconv.FormatInt(*c.Seed, 10))
+	}
+
+	serviceListen, serviceUnmatched := c.serviceFlags()
+	set("service-listen", serviceListen...)
+	set("unmatched-service", serviceUnmatched...)
+
+	return err
+}
+
+// serviceFlags converts per service settings to --service-listen and --unmatched-service values
+func (c *serverConfig) serviceFlags() (listen, unmatched []string) {
+	var addrs []string
+	services := map[string][]string{}
+	for _, svc := range c.Services {
+		if svc.Unmatched != "" {
+			unmatched = append(unmatched, svc.Name+"="+svc.Unmatched)
+		}
+		if svc.Listen == "" {
+			continue
+		}
+		if _, ok := services[svc.Listen]; !ok {
+			addrs = append(addrs, svc.Listen)
+		}
+		services[svc.Listen] = append(services[svc.Listen], svc.Name)
+	}
+
+	for _, addr := range addrs {
+		listen = append(listen, strings.Join(services[addr], ",")+"="+addr)
+	}
+
+	return listen, unmatched
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+// runWithConfig parses the args like the mock server and returns the context with the config applied
+func runWithConfig(t *testing.T, args ...string) (*cli.Context, error) {
+	var result *cli.Context
+	app := cli.NewApp()
+	app.Flags = serverFlags()
+	app.Action = func(ctx *cli.Context) error {
+		cfg, err := loadConfig(ctx.String("config"))
+		if err != nil {
+			return err
+		}
+		result = ctx
+		return applyConfig(ctx, cfg)
+	}
+
+	err := app.Run(append([]string{"grpc-ditto"}, args...))
+	return result, err
+}
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "ditto.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestConfig(t *testing.T) {
+	path := writeConfig(t, `
+listen: [":0", "unix:///tmp/ditto.sock"]
+proto:
+  paths: [protos, /abs/protos]
+mocks: mocks
+unmatched: status:NOT_FOUND
+services:
+- name: users.UserService
+  listen: ":50051"
+- name: users.AdminService
+  listen: ":50051"
+  unmatched: generate
+- name: orders.*
+  listen: ":50052"
+admin:
+  listen: [":51001"]
+  metrics_port: 9090
+log:
+  level: warn
+  access_log: stdout
+  access_log_max_body: 0
+tracing:
+  echo: true
+seed: 42
+`)
+	dir := filepath.Dir(path)
+
+	t.Setenv("DITTO_UNMATCHED", "status:UNAVAILABLE")
+	ctx, err := runWithConfig(t, "--config", path, "--loglevel", "error")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{":0", "unix:///tmp/ditto.sock"}, ctx.StringSlice("listen"))
+	assert.False(t, ctx.IsSet("port"))
+	assert.Equal(t, []string{filepath.Join(dir, "protos"), "/abs/protos"}, ctx.StringSlice("proto"))
+	assert.Equal(t, []string{filepath.Join(dir, "mocks")}, ctx.StringSlice("mocks"))
+	assert.Equal(t, "status:UNAVAILABLE", ctx.String("unmatched"), "environment overrides the file")
+	assert.Equal(t, "error", ctx.String("loglevel"), "flags override the file")
+	assert.Equal(t, []string{"users.UserService,users.AdminService=:50051", "orders.*=:50052"}, ctx.StringSlice("service-listen"))
+	assert.Equal(t, []string{"users.AdminService=generate"}, ctx.StringSlice("unmatched-service"))
+	assert.Equal(t, []string{":51001"}, ctx.StringSlice("admin-listen"))
+	assert.Equal(t, 9090, ctx.Int("metrics-port"))
+	assert.Equal(t, "stdout", ctx.String("access-log"))
+	assert.Equal(t, 0, ctx.Int("access-log-max-body"))
+	assert.True(t, ctx.Bool("trace-echo"))
+	assert.Equal(t, int64(42), ctx.Int64("seed"))
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := map[string]string{
+		`unknown field "listen_port"`: "listen: [':0']\nlisten_port: 1",
+		"services[0]: name":           "services: [{listen: ':1'}]",
+		"listen or unmatched":         "services: [{name: a.B}]",
+		"not a pattern":               "services: [{name: 'a.*', unmatched: generate}]",
+		"cannot unmarshal":            "listen: 5",
+	}
+
+	for msg, content := range tests {
+		_, err := runWithConfig(t, "--config", writeConfig(t, content))
+		assert.ErrorContains(t, err, msg)
+	}
+
+	ctx, err := runWithConfig(t, "--config", writeConfig(t, "mocks: [base, '-']\nmocks_exclude: ['*.draft.yaml']"))
+	require.NoError(t, err)
+	assert.Equal(t, "-", ctx.StringSlice("mocks")[1], "stdin is not resolved as a path")
+	assert.Equal(t, []string{"*.draft.yaml"}, ctx.StringSlice("mocks-exclude"))
+
+	_, err = runWithConfig(t, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestServerCredentials(t *testing.T) {
+	creds, err := serverCredentials("", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	_, err = serverCredentials("server.crt", "", "")
+	assert.ErrorContains(t, err, "must be set together")
+
+	_, err = serverCredentials("", "", "ca.crt")
+	assert.ErrorContains(t, err, "requires server certificate")
+
+	_, err = serverCredentials(filepath.Join(t.TempDir(), "server.crt"), "server.key", "")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
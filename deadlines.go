@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deadlineSlack is the time before the server deadline when client cancellation is treated as
+// exceeded deadline, clients reset the stream when their deadline comes which is a bit earlier
+// than the deadline the server calculates from grpc-timeout
+const deadlineSlack = 20 * time.Millisecond
+
+// stages of the mock call reported for canceled calls
+const (
+	stageFault    = "fault"
+	stageReceive  = "receive"
+	stageMatch    = "match"
+	stageDelay    = "delay"
+	stageSend     = "send"
+	stageKeepOpen = "keep_open"
+)
+
+// contextError returns Canceled or DeadlineExceeded status if the call is done, nil otherwise
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// sleep waits for d or until the call is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// recordCanceled adds the call canceled by the client or finished by the client deadline to the journal
+func (s *mockServer) recordCanceled(ctx context.Context, method string, started time.Time, call *callInfo) {
+	code := status.FromContextError(ctx.Err()).Code()
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < deadlineSlack {
+		code = codes.DeadlineExceeded
+	}
+	e := dittomock.CanceledEntry{
+		Time:    time.Now(),
+		Method:  method,
+		Code:    code,
+		Elapsed: time.Since(started),
+	}
+	if call != nil {
+		e.Body = call.request
+		e.MockID = call.mockID
+		e.Stage = call.stage
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		e.Timeout = deadline.Sub(started)
+	}
+
+	s.logger.Infow("call canceled", "method", method, "code", code, "stage", e.Stage, "elapsed", e.Elapsed)
+
+	if s.cancellations != nil {
+		s.cancellations.Record(e)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	apicode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMockServerDeadlines(t *testing.T) {
+	addr, s, stop := startStreamTestServer(t)
+	defer stop()
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	admin := api.NewMockingServiceClient(cc)
+
+	addMock := func(id, name, delay string, deadline *api.DeadlinePattern) {
+		mock, err := dittomock.FromProto(&api.DittoMock{
+			Id: id,
+			Request: &api.DittoRequest{
+				Method: "/ditto.example.HelloService/Hello",
+				BodyPatterns: []*api.DittoBodyPattern{
+					{
+						Pattern: &api.DittoBodyPattern_MatchesJsonpath{MatchesJsonpath: &api.JSONPathPattern{
+							Expression: "$.name",
+							Operator:   &api.JSONPathPattern_Eq{Eq: name},
+						}},
+						Deadline: deadline,
+					},
+				},
+			},
+			Response: []*api.DittoResponse{
+				{Response: &api.DittoResponse_BodyTemplate{BodyTemplate: `name: "` + id + `"`}},
+			},
+			Delay: delay,
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.matcher.AddMock(mock))
+	}
+
+	canceled := func(t *testing.T, stage string) *api.CanceledRequest {
+		var found *api.CanceledRequest
+		require.Eventually(t, func() bool {
+			resp, err := admin.ListCanceledRequests(context.Background(), &api.ListCanceledRequestsRequest{})
+			require.NoError(t, err)
+			for _, r := range resp.GetRequests() {
+				if r.GetStage() == stage {
+					found = r
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond)
+		return found
+	}
+
+	t.Run("DelayExceedsDeadline", func(t *testing.T) {
+		addMock("slow", "slow", "2s", nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := helloStream(ctx, t, addr, "slow").Recv()
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Less(t, time.Since(start), time.Second)
+
+		r := canceled(t, stageDelay)
+		assert.Equal(t, "/ditto.example.HelloService/Hello", r.GetMethod())
+		assert.Equal(t, "slow", r.GetMockId())
+		assert.Equal(t, apicode.Code_DEADLINE_EXCEEDED, r.GetCode())
+		assert.Equal(t, "slow", r.GetBody().GetStructValue().GetFields()["name"].GetStringValue())
+		assert.NotNil(t, r.GetTimeout())
+		assert.LessOrEqual(t, r.GetTimeout().AsDuration(), 100*time.Millisecond)
+	})
+
+	t.Run("ClientCancelsOpenStream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := helloStream(ctx, t, addr, "open")
+		_, err := stream.Recv()
+		require.NoError(t, err)
+		cancel()
+
+		r := canceled(t, stageKeepOpen)
+		assert.Equal(t, apicode.Code_CANCELLED, r.GetCode())
+		assert.Nil(t, r.GetTimeout())
+	})
+
+	t.Run("DeadlinePattern", func(t *testing.T) {
+		addMock("hurry short", "hurry", "", &api.DeadlinePattern{Lt: "1s"})
+		addMock("hurry none", "hurry", "", &api.DeadlinePattern{IsSet: proto.Bool(false)})
+		addMock("hurry long", "hurry", "", &api.DeadlinePattern{Gt: "1s"})
+
+		for name, timeout := range map[string]time.Duration{
+			"hurry short": 500 * time.Millisecond,
+			"hurry long":  time.Minute,
+			"hurry none":  0,
+		} {
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			msg, err := helloStream(ctx, t, addr, "hurry").Recv()
+			require.NoError(t, err)
+			assert.Equal(t, name, msg.GetName())
+		}
+	})
+}
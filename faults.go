@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// errStreamTruncated ends the stream successfully when the fault rule doesn't have status
+var errStreamTruncated = errors.New("stream truncated by fault rule")
+
+// faultStream applies the fault rule once the number of response messages are sent
+type faultStream struct {
+	grpc.ServerStream
+	srv  *mockServer
+	rule *dittomock.FaultRule
+	left uint32
+}
+
+func (s *faultStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+
+	s.left--
+	if s.left == 0 {
+		return s.srv.fail(s.Context(), s.rule)
+	}
+	return nil
+}
+
+// injectFault applies the fault rule chosen for the call, the returned stream
+// applies the fault after response messages are sent
+func (s *mockServer) injectFault(stream grpc.ServerStream) (grpc.ServerStream, error) {
+	if s.faults == nil {
+		return stream, nil
+	}
+
+	ctx := stream.Context()
+	fullMethodName, _ := grpc.Method(ctx)
+	md, _ := metadata.FromIncomingContext(ctx)
+	rule := s.faults.Pick(fullMethodName, md)
+	if rule == nil {
+		return stream, nil
+	}
+
+	s.logger.Infow("inject fault", "rule", rule.ID, "method", fullMethodName)
+	if rule.Delay > 0 {
+		callInfoFromContext(ctx).setStage(stageFault)
+		if err := sleep(ctx, rule.Delay); err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.AfterMessages != nil && *rule.AfterMessages > 0 {
+		return &faultStream{ServerStream: stream, srv: s, rule: rule, left: *rule.AfterMessages}, nil
+	}
+
+	if rule.AfterMessages == nil && rule.Status == nil && !rule.ResetConnection && !rule.ResetStream {
+		return stream, nil
+	}
+
+	if err := s.fail(ctx, rule); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// fail returns the error the call has to be finished with
+func (s *mockServer) fail(ctx context.Context, rule *dittomock.FaultRule) error {
+	switch {
+	case rule.ResetConnection:
+		if !s.conns.reset(ctx) {
+			s.logger.Warnw("connection not found, fault rule returns status instead of reset", "rule", rule.ID)
+		}
+		return status.Error(codes.Unavailable, "connection reset by fault rule")
+	case rule.ResetStream:
+		if !s.conns.resetStream(ctx) {
+			s.logger.Warnw("stream not found, fault rule returns status instead of reset", "rule", rule.ID)
+		}
+		return status.Error(codes.Internal, "stream reset by fault rule")
+	case rule.Status != nil:
+		return status.Error(rule.Status.Code, rule.Status.Message)
+	}
+	return errStreamTruncated
+}
+
+// connTracker keeps accepted connections, so fault rules can reset the connection or the stream of a call
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[net.Addr]*trackedConn
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{
+		conns: map[net.Addr]*trackedConn{},
+	}
+}
+
+// wrap returns the credentials that keep the connections after the handshake,
+// insecure credentials are used if creds is nil
+func (t *connTracker) wrap(creds credentials.TransportCredentials) credentials.TransportCredentials {
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	return &trackedCredentials{TransportCredentials: creds, tracker: t}
+}
+
+// trackedCredentials tracks the connections after the handshake, tls connections
+// are tracked decrypted, so frames can be written to the streams
+type trackedCredentials struct {
+	credentials.TransportCredentials
+	tracker *connTracker
+}
+
+func (c *trackedCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// peers of unix sockets don't have unique addresses, so every connection gets
+	// its own address value to find the connection by the call peer
+	tracked := &trackedConn{
+		Conn:    conn,
+		raw:     rawConn,
+		addr:    &trackedAddr{addr: conn.RemoteAddr()},
+		tracker: c.tracker,
+	}
+	c.tracker.mu.Lock()
+	c.tracker.conns[tracked.addr] = tracked
+	c.tracker.mu.Unlock()
+
+	return tracked, info, nil
+}
+
+func (c *trackedCredentials) Clone() credentials.TransportCredentials {
+	return &trackedCredentials{TransportCredentials: c.TransportCredentials.Clone(), tracker: c.tracker}
+}
+
+// find returns the connection of the call
+func (t *connTracker) find(ctx context.Context) (*trackedConn, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, ok := t.conns[p.Addr]
+	return conn, ok
+}
+
+// reset closes the connection of the call without graceful shutdown,
+// false is returned if the connection is not tracked
+func (t *connTracker) reset(ctx context.Context) bool {
+	conn, ok := t.find(ctx)
+	if !ok {
+		return false
+	}
+
+	if tcp, ok := conn.raw.(*net.TCPConn); ok {
+		// zero linger sends RST instead of FIN
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+	return true
+}
+
+// resetStream aborts the stream of the call with RST_STREAM frame, the connection and its other calls are kept,
+// false is returned if the connection is not tracked or the stream id is unknown
+func (t *connTracker) resetStream(ctx context.Context) bool {
+	conn, ok := t.find(ctx)
+	if !ok {
+		return false
+	}
+
+	id, ok := streamID(ctx)
+	if !ok {
+		return false
+	}
+
+	return conn.resetStream(id) == nil
+}
+
+// streamID returns http/2 stream id of the call, grpc doesn't expose it,
+// so it's read from the transport stream
+func streamID(ctx context.Context) (id uint32, ok bool) {
+	defer func() {
+		if recover() != nil {
+			id, ok = 0, false
+		}
+	}()
+
+	v := reflect.ValueOf(grpc.ServerTransportStreamFromContext(ctx))
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return 0, false
+	}
+
+	f := v.Elem().FieldByName("id")
+	if f.Kind() != reflect.Uint32 {
+		return 0, false
+	}
+	return uint32(f.Uint()), true
+}
+
+func (t *connTracker) remove(conn *trackedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.conns, conn.addr)
+}
+
+// trackedAddr is a unique address of the tracked connection
+type trackedAddr struct {
+	addr net.Addr
+}
+
+func (a *trackedAddr) Network() string {
+	if a.addr == nil {
+		return ""
+	}
+	return a.addr.Network()
+}
+
+func (a *trackedAddr) String() string {
+	if a.addr == nil {
+		return ""
+	}
+	return a.addr.String()
+}
+
+const (
+	http2FrameHeaderLen  = 9
+	http2FrameRSTStream  = 0x3
+	http2ErrCodeInternal = 0x2
+)
+
+// trackedConn follows the boundaries of http/2 frames the server writes,
+// so RST_STREAM frames can be written between them
+type trackedConn struct {
+	net.Conn
+	raw     net.Conn
+	addr    *trackedAddr
+	tracker *connTracker
+	once    sync.Once
+
+	mu sync.Mutex
+	// header of the frame being written and the number of its bytes written
+	header    [http2FrameHeaderLen]byte
+	headerLen int
+	// payload bytes of the frame left to write
+	payloadLeft int
+	// streams to reset once the frame is written
+	resets []uint32
+}
+
+func (c *trackedConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func (c *trackedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for len(p) > 0 {
+		if err := c.writeResets(); err != nil {
+			return n, err
+		}
+
+		k := c.advance(p)
+		w, err := c.Conn.Write(p[:k])
+		n += w
+		if err != nil {
+			return n, err
+		}
+		p = p[k:]
+	}
+
+	return n, c.writeResets()
+}
+
+// advance follows the frames in p, it returns the number of bytes until the end of the frame
+func (c *trackedConn) advance(p []byte) int {
+	i := 0
+	for i < len(p) {
+		if c.headerLen < http2FrameHeaderLen {
+			m := copy(c.header[c.headerLen:], p[i:])
+			c.headerLen += m
+			i += m
+			if c.headerLen < http2FrameHeaderLen {
+				continue
+			}
+			c.payloadLeft = int(c.header[0])<<16 | int(c.header[1])<<8 | int(c.header[2])
+		}
+
+		m := min(c.payloadLeft, len(p)-i)
+		c.payloadLeft -= m
+		i += m
+		if c.payloadLeft == 0 {
+			c.headerLen = 0
+			return i
+		}
+	}
+	return i
+}
+
+// resetStream writes RST_STREAM frame, the frame is delayed until the frame being written is complete
+func (c *trackedConn) resetStream(id uint32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resets = append(c.resets, id)
+	return c.writeResets()
+}
+
+// writeResets writes pending RST_STREAM frames if the previous frame is complete
+func (c *trackedConn) writeResets() error {
+	if c.headerLen > 0 || len(c.resets) == 0 {
+		return nil
+	}
+
+	frames := make([]byte, 0, len(c.resets)*(http2FrameHeaderLen+4))
+	for _, id := range c.resets {
+		frames = append(frames, 0, 0, 4, http2FrameRSTStream, 0)
+		frames = binary.BigEndian.AppendUint32(frames, id&(1<<31-1))
+		frames = binary.BigEndian.AppendUint32(frames, http2ErrCodeInternal)
+	}
+	c.resets = nil
+
+	_, err := c.Conn.Write(frames)
+	return err
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.tracker.remove(c)
+	})
+	return c.Conn.Close()
+}
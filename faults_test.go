@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/testdata/hello"
+	apicode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMockServerFaults(t *testing.T) {
+	addr, _, stop := startStreamTestServer(t)
+	defer stop()
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	admin := api.NewMockingServiceClient(cc)
+
+	addRule := func(t *testing.T, rule *api.FaultRule) {
+		_, err := admin.AddFaultRule(context.Background(), &api.AddFaultRuleRequest{Rule: rule})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, err := admin.ClearFaultRules(context.Background(), &api.ClearFaultRulesRequest{})
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("Status", func(t *testing.T) {
+		addRule(t, &api.FaultRule{
+			Method:   "/ditto.example.HelloService/*",
+			Metadata: map[string]string{"x-chaos": "on"},
+			Status:   &api.RpcStatus{Code: apicode.Code_UNAVAILABLE, Message: "chaos"},
+		})
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-chaos", "on")
+		_, err := helloStream(ctx, t, addr, "watch").Recv()
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		msg, err := helloStream(context.Background(), t, addr, "watch").Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "hello watch", msg.GetName())
+	})
+
+	t.Run("Delay", func(t *testing.T) {
+		addRule(t, &api.FaultRule{Delay: "50ms"})
+
+		start := time.Now()
+		_, err := helloStream(context.Background(), t, addr, "watch").Recv()
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("TruncateAfterMessages", func(t *testing.T) {
+		addRule(t, &api.FaultRule{AfterMessages: proto.Uint32(2)})
+
+		stream := helloStream(context.Background(), t, addr, "watch")
+		var names []string
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, msg.GetName())
+		}
+		assert.Equal(t, []string{"hello watch", "tick 0"}, names)
+	})
+
+	t.Run("ResetConnection", func(t *testing.T) {
+		addRule(t, &api.FaultRule{AfterMessages: proto.Uint32(1), ResetConnection: true})
+
+		// messages sent right before the reset may be lost
+		stream := helloStream(context.Background(), t, addr, "watch")
+		for {
+			_, err := stream.Recv()
+			if err != nil {
+				assert.Equal(t, codes.Unavailable, status.Code(err))
+				break
+			}
+		}
+	})
+
+	t.Run("ResetStream", func(t *testing.T) {
+		addRule(t, &api.FaultRule{Metadata: map[string]string{"x-chaos": "reset"}, AfterMessages: proto.Uint32(1), ResetStream: true})
+
+		client := hello.NewHelloServiceClient(cc)
+		kept, err := client.Hello(context.Background(), &hello.HelloRequest{Name: "watch"})
+		require.NoError(t, err)
+		_, err = kept.Recv()
+		require.NoError(t, err)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-chaos", "reset")
+		stream, err := client.Hello(ctx, &hello.HelloRequest{Name: "watch"})
+		require.NoError(t, err)
+		for {
+			_, err := stream.Recv()
+			if err != nil {
+				assert.Equal(t, codes.Internal, status.Code(err))
+				assert.Contains(t, status.Convert(err).Message(), "RST_STREAM")
+				break
+			}
+		}
+
+		// other streams of the connection are kept
+		_, err = kept.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, connectivity.Ready, cc.GetState())
+	})
+
+	t.Run("ZeroPercentage", func(t *testing.T) {
+		addRule(t, &api.FaultRule{Percentage: proto.Float64(0), Status: &api.RpcStatus{Code: apicode.Code_INTERNAL}})
+
+		_, err := helloStream(context.Background(), t, addr, "watch").Recv()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		addRule(t, &api.FaultRule{Id: "slow", Method: "/ditto.example.HelloService/Hello", Delay: "1ms"})
+
+		rules, err := admin.ListFaultRules(context.Background(), &api.ListFaultRulesRequest{})
+		require.NoError(t, err)
+		require.Len(t, rules.GetRules(), 1)
+		assert.Equal(t, "slow", rules.GetRules()[0].GetId())
+		assert.Equal(t, "1ms", rules.GetRules()[0].GetDelay())
+
+		_, err = admin.AddFaultRule(context.Background(), &api.AddFaultRuleRequest{Rule: &api.FaultRule{Method: "/svc/*"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestMockServerResetUnixConnection(t *testing.T) {
+	_, s, stop := startStreamTestServer(t)
+	defer stop()
+
+	server := grpc.NewServer(grpc.Creds(s.conns.wrap(nil)))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	defer stopTestServer(server)
+
+	sock := filepath.Join(t.TempDir(), "ditto.sock")
+	lis, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	go server.Serve(lis)
+
+	s.faults.Add(dittomock.FaultRule{Percentage: 100, AfterMessages: proto.Uint32(1), ResetConnection: true})
+
+	cc, err := grpc.Dial("unix://"+sock, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	stream, err := hello.NewHelloServiceClient(cc).Hello(context.Background(), &hello.HelloRequest{Name: "watch"})
+	require.NoError(t, err)
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+			break
+		}
+	}
+
+	// the connection is closed instead of the status returned
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for cc.GetState() == connectivity.Ready {
+		require.True(t, cc.WaitForStateChange(ctx, connectivity.Ready), "connection is not reset")
+	}
+}
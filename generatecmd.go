@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/vadimi/grpc-ditto/internal/mockgen"
+
+	"github.com/urfave/cli"
+)
+
+func newGenerateCmd() func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		descrs, err := parseProtoFiles(ctx)
+		if err != nil {
+			return err
+		}
+
+		format := ctx.String("format")
+		outDir := ctx.String("out")
+		if outDir != "" {
+			if err := os.MkdirAll(outDir, 0o755); err != nil {
+				return err
+			}
+		}
+
+		var services []*desc.ServiceDescriptor
+		for _, d := range descrs {
+			services = append(services, d.GetServices()...)
+		}
+
+		if outDir == "" {
+			return writeMocks(os.Stdout, services, format)
+		}
+
+		for _, sd := range services {
+			filename := filepath.Join(outDir, fmt.Sprintf("%s.%s", sd.GetFullyQualifiedName(), format))
+			if err := writeMocksFile(filename, mockgen.GenerateService(sd), format); err != nil {
+				return err
+			}
+			fmt.Fprintln(ctx.App.Writer, filename)
+		}
+
+		return nil
+	}
+}
+
+// writeMocks writes the mocks of all the services as one json array
+// or as yaml documents of every service separated by ---
+func writeMocks(w io.Writer, services []*desc.ServiceDescriptor, format string) error {
+	if strings.EqualFold(format, mockgen.FormatJSON) {
+		var mocks []mockgen.Mock
+		for _, sd := range services {
+			mocks = append(mocks, mockgen.GenerateService(sd)...)
+		}
+		return mockgen.Write(w, mocks, format)
+	}
+
+	for _, sd := range services {
+		if err := mockgen.Write(w, mockgen.GenerateService(sd), format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMocksFile(filename string, mocks []mockgen.Mock, format string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := mockgen.Write(f, mocks, format); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
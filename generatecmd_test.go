@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/mockgen"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteMocksOfSeveralServices(t *testing.T) {
+	var services []*desc.ServiceDescriptor
+	for _, name := range []string{"greet.proto", "hello.proto"} {
+		fd, err := findFileDescriptor(name)
+		require.NoError(t, err)
+		services = append(services, fd.GetServices()...)
+	}
+
+	total := 0
+	for _, sd := range services {
+		total += len(sd.GetMethods())
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeMocks(buf, services, mockgen.FormatJSON))
+	var mocks []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &mocks), "one json array")
+	assert.Len(t, mocks, total)
+
+	buf.Reset()
+	require.NoError(t, writeMocks(buf, services, mockgen.FormatYAML))
+	dec := yaml.NewDecoder(buf)
+	docs, count := 0, 0
+	for {
+		var doc []map[string]interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		docs++
+		count += len(doc)
+	}
+	assert.Equal(t, len(services), docs, "yaml document per service")
+	assert.Equal(t, total, count)
+}
@@ -0,0 +1,180 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// celProgram is matches_cel expression compiled for the method input
+type celProgram struct {
+	method *desc.MethodDescriptor
+	// types resolve google.protobuf.Any values of the request
+	types *protoregistry.Types
+	// stream is true if the request is the list of client streaming messages
+	stream bool
+	prg    cel.Program
+	// err is set if the expression can't be compiled, such patterns never match
+	err error
+}
+
+// CheckCEL parses and type checks the expression against the method input message,
+// stream is true if the expression is applied to all messages of client streaming request
+// and false for a single message
+func CheckCEL(method *desc.MethodDescriptor, expr string, stream bool) error {
+	_, err := compileCEL(method, expr, stream)
+	return err
+}
+
+// compileCEL builds the program, the expression has access to:
+//
+//	request  - the input message, or the list of messages of client streaming request
+//	metadata - incoming metadata as map(string, list(string))
+func compileCEL(method *desc.MethodDescriptor, expr string, stream bool) (*celProgram, error) {
+	if method == nil {
+		return nil, errors.New("method descriptor is required to compile cel expression")
+	}
+
+	reqType := cel.ObjectType(method.GetInputType().GetFullyQualifiedName())
+	if stream {
+		reqType = cel.ListType(reqType)
+	}
+
+	env, err := cel.NewEnv(
+		cel.TypeDescs(method.GetFile().UnwrapFile()),
+		cel.Variable("request", reqType),
+		cel.Variable("metadata", cel.MapType(cel.StringType, cel.ListType(cel.StringType))),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must return bool, got %s", ast.OutputType())
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	return &celProgram{method: method, types: inputTypes(method), stream: stream, prg: prg}, nil
+}
+
+// celMatcher evaluates the compiled expression of the pattern
+func celMatcher(req *matchRequest, pattern DittoBodyPattern) (bool, error) {
+	p := pattern.cel
+	if p == nil {
+		return false, fmt.Errorf("cel expression %q is not compiled", pattern.MatchesCEL)
+	}
+	if p.err != nil {
+		return false, p.err
+	}
+
+	input, err := req.celInput(p.method, p.types, p.stream)
+	if err != nil {
+		return false, err
+	}
+
+	md := map[string][]string(req.metadata)
+	if md == nil {
+		md = map[string][]string{}
+	}
+
+	out, _, err := p.prg.Eval(map[string]interface{}{
+		"request":  input,
+		"metadata": md,
+	})
+	if err != nil {
+		return false, fmt.Errorf("cel evaluation: %w", err)
+	}
+
+	result, ok := out.Value().(bool)
+	return ok && result, nil
+}
+
+// celInput converts request json to protobuf messages once per request,
+// the messages are shared by matches_cel and matches_field patterns
+func (r *matchRequest) celInput(method *desc.MethodDescriptor, types *protoregistry.Types, stream bool) (interface{}, error) {
+	if r.celValue != nil || r.celErr != nil {
+		return r.celValue, r.celErr
+	}
+
+	r.celValue, r.celErr = requestMessages(method, types, r.json, stream)
+	return r.celValue, r.celErr
+}
+
+func requestMessages(method *desc.MethodDescriptor, types *protoregistry.Types, js []byte, stream bool) (interface{}, error) {
+	md := method.GetInputType().UnwrapMessage()
+	opts := protojson.UnmarshalOptions{Resolver: types}
+	if !stream {
+		msg := dynamicpb.NewMessage(md)
+		if err := opts.Unmarshal(js, msg); err != nil {
+			return nil, fmt.Errorf("request conversion: %w", err)
+		}
+		return msg, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(js, &items); err != nil {
+		return nil, fmt.Errorf("request conversion: %w", err)
+	}
+
+	msgs := make([]proto.Message, 0, len(items))
+	for _, item := range items {
+		msg := dynamicpb.NewMessage(md)
+		if err := opts.Unmarshal(item, msg); err != nil {
+			return nil, fmt.Errorf("request conversion: %w", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// inputTypes registers messages of the method file and its dependencies,
+// so that google.protobuf.Any fields of user types can be converted
+func inputTypes(method *desc.MethodDescriptor) *protoregistry.Types {
+	types := &protoregistry.Types{}
+	seen := map[string]bool{}
+
+	var register func(fd *desc.FileDescriptor)
+	register = func(fd *desc.FileDescriptor) {
+		if seen[fd.GetName()] {
+			return
+		}
+		seen[fd.GetName()] = true
+
+		for _, dep := range fd.GetDependencies() {
+			register(dep)
+		}
+		registerMessages(types, fd.UnwrapFile().Messages())
+	}
+	register(method.GetFile())
+
+	return types
+}
+
+func registerMessages(types *protoregistry.Types, msgs protoreflect.MessageDescriptors) {
+	for i := 0; i < msgs.Len(); i++ {
+		md := msgs.Get(i)
+		if md.IsMapEntry() {
+			continue
+		}
+		// names are unique across the files of the method, so registration can't fail
+		_ = types.RegisterMessage(dynamicpb.NewMessageType(md))
+		registerMessages(types, md.Messages())
+	}
+}
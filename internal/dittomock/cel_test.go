@@ -0,0 +1,157 @@
+package dittomock
+
+import (
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+const celTestProto = `syntax = "proto3";
+
+package cel.test;
+
+import "google/protobuf/timestamp.proto";
+
+service OrderService {
+  rpc Create(Order) returns (Order);
+  rpc Upload(stream Order) returns (Order);
+}
+
+message Order {
+  string currency = 1;
+  int64 amount = 2;
+  repeated Item items = 3;
+  google.protobuf.Timestamp created_at = 4;
+}
+
+message Item {
+  string sku = 1;
+}
+`
+
+func celTestResolver(t *testing.T) func(string) *desc.MethodDescriptor {
+	p := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{
+			"cel.proto": celTestProto,
+		}),
+	}
+	fds, err := p.ParseFiles("cel.proto")
+	require.NoError(t, err)
+
+	sd := fds[0].FindService("cel.test.OrderService")
+	return func(method string) *desc.MethodDescriptor {
+		for _, m := range sd.GetMethods() {
+			if "/"+sd.GetFullyQualifiedName()+"/"+m.GetName() == method {
+				return m
+			}
+		}
+		return nil
+	}
+}
+
+func TestCELMatching(t *testing.T) {
+	resolve := celTestResolver(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		expr    string
+		json    string
+		md      metadata.MD
+		matched bool
+	}{
+		{
+			name:    "Fields",
+			method:  "/cel.test.OrderService/Create",
+			expr:    "request.items.size() > 2 && request.currency == 'EUR'",
+			json:    `{"currency": "EUR", "amount": "100", "items": [{"sku": "a"}, {"sku": "b"}, {"sku": "c"}], "created_at": null}`,
+			matched: true,
+		},
+		{
+			name:    "FieldsMismatch",
+			method:  "/cel.test.OrderService/Create",
+			expr:    "request.items.size() > 2 && request.currency == 'EUR'",
+			json:    `{"currency": "USD", "amount": "100", "items": [], "created_at": null}`,
+			matched: false,
+		},
+		{
+			name:    "Int64AndTimestamp",
+			method:  "/cel.test.OrderService/Create",
+			expr:    "request.amount > 1000 && request.created_at > timestamp('2024-01-01T00:00:00Z')",
+			json:    `{"currency": "", "amount": "1500", "items": [], "created_at": "2024-06-01T00:00:00Z"}`,
+			matched: true,
+		},
+		{
+			name:    "Metadata",
+			method:  "/cel.test.OrderService/Create",
+			expr:    "'beta' in metadata['x-features']",
+			json:    `{"currency": "", "amount": "0", "items": [], "created_at": null}`,
+			md:      metadata.Pairs("x-features", "alpha", "x-features", "beta"),
+			matched: true,
+		},
+		{
+			name:    "MetadataMissing",
+			method:  "/cel.test.OrderService/Create",
+			expr:    "'x-features' in metadata && 'beta' in metadata['x-features']",
+			json:    `{"currency": "", "amount": "0", "items": [], "created_at": null}`,
+			matched: false,
+		},
+		{
+			name:    "ClientStream",
+			method:  "/cel.test.OrderService/Upload",
+			expr:    "request.size() == 2 && request.all(o, o.currency == 'EUR')",
+			json:    `[{"currency": "EUR", "amount": "1", "items": [], "created_at": null}, {"currency": "EUR", "amount": "2", "items": [], "created_at": null}]`,
+			matched: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := DittoMock{
+				Request: &DittoRequest{
+					Method:       test.method,
+					BodyPatterns: []DittoBodyPattern{{MatchesCEL: test.expr}},
+				},
+			}
+
+			rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+			require.NoError(t, err)
+
+			_, err = rm.Match(test.method, []byte(test.json), WithMetadata(test.md))
+			if test.matched {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNotMatched)
+			}
+		})
+	}
+}
+
+func TestCELCompileErrors(t *testing.T) {
+	resolve := celTestResolver(t)
+	method := resolve("/cel.test.OrderService/Create")
+
+	assert.NoError(t, CheckCEL(method, "request.currency == 'EUR'", false))
+	assert.ErrorContains(t, CheckCEL(method, "request.currency ==", false), "Syntax error")
+	assert.ErrorContains(t, CheckCEL(method, "request.price > 1", false), "price")
+	assert.ErrorContains(t, CheckCEL(method, "request.currency", false), "expression must return bool")
+	assert.Error(t, CheckCEL(nil, "true", false))
+
+	mock := DittoMock{
+		Request: &DittoRequest{
+			Method:       "/cel.test.OrderService/Create",
+			BodyPatterns: []DittoBodyPattern{{MatchesCEL: "request.price > 1"}},
+		},
+	}
+	_, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+	assert.ErrorContains(t, err, "invalid cel expression")
+
+	rm, err := NewRequestMatcher(WithMethodResolver(resolve))
+	require.NoError(t, err)
+	assert.ErrorContains(t, rm.AddMock(mock), "invalid cel expression")
+	assert.Empty(t, rm.Mocks())
+}
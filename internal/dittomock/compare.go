@@ -0,0 +1,200 @@
+package dittomock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spyzhov/ajson"
+)
+
+// nodeMatcher returns the matcher of comparison operators applied to every selected value,
+// it's nil if the pattern uses one of the original operators
+func (w *JSONPathWrapper) nodeMatcher(prg *compiledJSONPath) func(node *ajson.Node) (bool, error) {
+	switch {
+	case w.IsNull != nil:
+		isNull := *w.IsNull
+		return func(node *ajson.Node) (bool, error) {
+			return node.IsNull() == isNull, nil
+		}
+	case w.Between != nil:
+		return func(node *ajson.Node) (bool, error) {
+			minCmp, err := compareNode(node, w.Between.Min)
+			if err != nil || minCmp < 0 {
+				return false, err
+			}
+			maxCmp, err := compareNode(node, w.Between.Max)
+			return maxCmp <= 0, err
+		}
+	case w.In != nil:
+		return func(node *ajson.Node) (bool, error) {
+			for i, v := range w.In {
+				// values of a different type are not equal
+				if ok, err := equalNode(node, v, prg.in[i]); ok && err == nil {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	case w.StartsWith != "":
+		return func(node *ajson.Node) (bool, error) {
+			return strings.HasPrefix(nodeString(node), w.StartsWith), nil
+		}
+	case w.EndsWith != "":
+		return func(node *ajson.Node) (bool, error) {
+			return strings.HasSuffix(nodeString(node), w.EndsWith), nil
+		}
+	case w.Gt != "":
+		return compareMatcher(w.Gt, func(c int) bool { return c > 0 })
+	case w.Gte != "":
+		return compareMatcher(w.Gte, func(c int) bool { return c >= 0 })
+	case w.Lt != "":
+		return compareMatcher(w.Lt, func(c int) bool { return c < 0 })
+	case w.Lte != "":
+		return compareMatcher(w.Lte, func(c int) bool { return c <= 0 })
+	}
+
+	return nil
+}
+
+func compareMatcher(val string, accept func(c int) bool) func(node *ajson.Node) (bool, error) {
+	return func(node *ajson.Node) (bool, error) {
+		c, err := compareNode(node, val)
+		if err != nil {
+			return false, err
+		}
+		return accept(c), nil
+	}
+}
+
+// equalNode compares the value using eq operator rules:
+// strings are compared case insensitive, objects and arrays as json
+// with the operand decoded when the pattern was compiled
+func equalNode(node *ajson.Node, val string, operand *compiledJSON) (bool, error) {
+	switch node.Type() {
+	case ajson.String:
+		strVal, _ := node.GetString()
+		return strings.EqualFold(strVal, val), nil
+	case ajson.Numeric:
+		n, _ := node.GetNumeric()
+		floatVal, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return false, err
+		}
+		return n == floatVal, nil
+	case ajson.Bool:
+		pbVal, err := strconv.ParseBool(val)
+		if err != nil {
+			return false, err
+		}
+		bVal, _ := node.GetBool()
+		return pbVal == bVal, nil
+	case ajson.Object, ajson.Array:
+		if operand.err != nil {
+			return false, operand.err
+		}
+		src, err := canonicalJSON([]byte(node.String()))
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(src, operand.canonical), nil
+	}
+
+	return false, nil
+}
+
+// compareNode returns -1, 0 or 1 if the value is less, equal or greater than val.
+// Strings are compared as timestamps or numbers if both sides can be parsed,
+// 64-bit integers are rendered as strings in requests. Strings of different kinds,
+// e.g. a number and a text, can't be compared.
+func compareNode(node *ajson.Node, val string) (int, error) {
+	switch node.Type() {
+	case ajson.Numeric:
+		n, _ := node.GetNumeric()
+		floatVal, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", val)
+		}
+		return compareFloat(n, floatVal), nil
+	case ajson.String:
+		strVal, _ := node.GetString()
+		kind, valKind := stringKind(strVal), stringKind(val)
+		if kind != valKind {
+			return 0, fmt.Errorf("can't compare %s %q with %s %q", kind, strVal, valKind, val)
+		}
+
+		switch kind {
+		case kindTimestamp:
+			t1, _ := time.Parse(time.RFC3339Nano, strVal)
+			t2, _ := time.Parse(time.RFC3339Nano, val)
+			return t1.Compare(t2), nil
+		case kindNumber:
+			f1, _ := strconv.ParseFloat(strVal, 64)
+			f2, _ := strconv.ParseFloat(val, 64)
+			return compareFloat(f1, f2), nil
+		}
+		return strings.Compare(strVal, val), nil
+	case ajson.Bool:
+		b1, _ := node.GetBool()
+		b2, err := strconv.ParseBool(val)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a boolean", val)
+		}
+		return compareBool(b1, b2), nil
+	}
+
+	return 0, errors.New("only numbers, strings and booleans can be compared")
+}
+
+const (
+	kindString    = "string"
+	kindNumber    = "number"
+	kindTimestamp = "timestamp"
+)
+
+// stringKind returns the kind of the value a string holds
+func stringKind(s string) string {
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return kindTimestamp
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return kindNumber
+	}
+	return kindString
+}
+
+// CompareValues compares two pattern values using the same rules as request values
+func CompareValues(a, b string) (int, error) {
+	return compareNode(ajson.StringNode("", a), b)
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+	return 1
+}
+
+func nodeString(node *ajson.Node) string {
+	if node.Type() == ajson.String {
+		strVal, _ := node.GetString()
+		return strVal
+	}
+	return node.String()
+}
@@ -0,0 +1,166 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/spyzhov/ajson"
+)
+
+// compiledJSONPath is parsed jsonpath expression and compiled regexp of the pattern,
+// eq and in operands are decoded once to be compared with objects and arrays
+type compiledJSONPath struct {
+	commands []string
+	re       *regexp.Regexp
+	equals   *compiledJSON
+	in       []*compiledJSON
+	err      error
+}
+
+func compileJSONPath(w *JSONPathWrapper) *compiledJSONPath {
+	c := &compiledJSONPath{}
+	c.commands, c.err = ajson.ParseJSONPath(w.Expression)
+	if c.err != nil {
+		c.err = fmt.Errorf("jsonpath matching: %w, expr: %s", c.err, w.Expression)
+		return c
+	}
+
+	if w.Regexp != "" {
+		c.re, c.err = regexp.Compile(w.Regexp)
+	}
+
+	c.equals = compileJSON([]byte(w.Equals))
+	for _, v := range w.In {
+		c.in = append(c.in, compileJSON([]byte(v)))
+	}
+
+	return c
+}
+
+// program returns the pattern compiled when the mock was added to the matcher,
+// patterns that were not added to the matcher are compiled on every call
+func (w *JSONPathWrapper) program() *compiledJSONPath {
+	if w.compiled != nil {
+		return w.compiled
+	}
+	return compileJSONPath(w)
+}
+
+// compiledJSON is equal_to_json value decoded and canonicalized once
+type compiledJSON struct {
+	value     interface{}
+	canonical []byte
+	err       error
+}
+
+func compileJSON(js []byte) *compiledJSON {
+	c := &compiledJSON{}
+	if c.err = json.Unmarshal(js, &c.value); c.err != nil {
+		return c
+	}
+	c.canonical, c.err = json.Marshal(c.value)
+	return c
+}
+
+func (p DittoBodyPattern) expectedJSON() *compiledJSON {
+	if p.compiledJSON != nil {
+		return p.compiledJSON
+	}
+	return compileJSON(p.EqualToJson)
+}
+
+// compilePatterns prepares the mock patterns including the nested ones for matching,
+// errors are kept in the patterns, so they are reported again if the patterns are shared.
+// stream is true if the patterns are matched against all messages of client streaming request.
+func compilePatterns(patterns []DittoBodyPattern, method *desc.MethodDescriptor, stream bool) []error {
+	var errs []error
+	for i := range patterns {
+		errs = append(errs, compilePattern(&patterns[i], method, stream)...)
+	}
+	return errs
+}
+
+func compilePattern(p *DittoBodyPattern, method *desc.MethodDescriptor, stream bool) []error {
+	var errs []error
+	if len(p.EqualToJson) > 0 {
+		if p.compiledJSON == nil {
+			p.compiledJSON = compileJSON(p.EqualToJson)
+		}
+		if p.compiledJSON.err != nil {
+			errs = append(errs, fmt.Errorf("invalid equal_to_json: %w", p.compiledJSON.err))
+		}
+	}
+
+	if w := p.MatchesJsonPath; w != nil {
+		if w.compiled == nil {
+			w.compiled = compileJSONPath(w)
+		}
+		if w.compiled.err != nil {
+			errs = append(errs, w.compiled.err)
+		}
+	}
+
+	if p.MatchesCEL != "" {
+		if p.cel == nil {
+			prg, err := compileCEL(method, p.MatchesCEL, stream)
+			if err != nil {
+				prg = &celProgram{err: fmt.Errorf("invalid cel expression %q: %w", p.MatchesCEL, err)}
+			}
+			p.cel = prg
+		}
+		if p.cel.err != nil {
+			errs = append(errs, p.cel.err)
+		}
+	}
+
+	if f := p.MatchesField; f != nil {
+		if f.compiled == nil {
+			c, err := compileField(method, f, stream)
+			if err != nil {
+				c = &compiledField{err: fmt.Errorf("invalid matches_field: %w", err)}
+			}
+			f.compiled = c
+		}
+		if f.compiled.err != nil {
+			errs = append(errs, f.compiled.err)
+		}
+	}
+
+	errs = append(errs, compilePatterns(p.AnyOf, method, stream)...)
+	errs = append(errs, compilePatterns(p.AllOf, method, stream)...)
+	if p.Not != nil {
+		errs = append(errs, compilePattern(p.Not, method, stream)...)
+	}
+
+	// message patterns are matched against single messages of the stream
+	if p.AnyMessage != nil {
+		errs = append(errs, compilePattern(p.AnyMessage, method, false)...)
+	}
+	if p.EveryMessage != nil {
+		errs = append(errs, compilePattern(p.EveryMessage, method, false)...)
+	}
+	for _, step := range p.Sequence {
+		if step.Message != nil {
+			errs = append(errs, compilePattern(step.Message, method, false)...)
+		}
+	}
+	return errs
+}
+
+// jsonRoot parses the request once for all jsonpath patterns
+func (r *matchRequest) jsonRoot() (*ajson.Node, error) {
+	if r.root == nil && r.rootErr == nil {
+		r.root, r.rootErr = ajson.Unmarshal(r.json)
+	}
+	return r.root, r.rootErr
+}
+
+// jsonValue decodes the request once for all equal_to_json patterns
+func (r *matchRequest) jsonValue() *compiledJSON {
+	if r.value == nil {
+		r.value = compileJSON(r.json)
+	}
+	return r.value
+}
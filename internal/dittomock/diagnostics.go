@@ -0,0 +1,384 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxCandidates is the number of the closest mocks reported for unmatched requests
+	maxCandidates = 3
+	// maxValueLen limits the length of values in mismatch descriptions
+	maxValueLen = 256
+)
+
+// NotMatchedError describes why a request didn't match any mock of the method.
+// errors.Is(err, ErrNotMatched) is true for it.
+type NotMatchedError struct {
+	Method string
+	// Candidates are the closest mocks of the method, the best one goes first
+	Candidates []Candidate
+}
+
+func (e *NotMatchedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrNotMatched, e.Summary())
+}
+
+func (e *NotMatchedError) Is(target error) bool {
+	return target == ErrNotMatched
+}
+
+// Summary explains why the closest mock didn't match
+func (e *NotMatchedError) Summary() string {
+	if len(e.Candidates) == 0 {
+		return "no mocks found for the method"
+	}
+
+	c := e.Candidates[0]
+	if len(c.Mismatches) == 0 {
+		return fmt.Sprintf("closest mock %s", c.MockID)
+	}
+
+	summary := fmt.Sprintf("closest mock %s: %s", c.MockID, c.Mismatches[0])
+	if len(c.Mismatches) > 1 {
+		summary += fmt.Sprintf(" (and %d more)", len(c.Mismatches)-1)
+	}
+
+	return summary
+}
+
+// Candidate is a mock that was considered for a request
+type Candidate struct {
+	MockID     string
+	Matched    int
+	Total      int
+	Mismatches []PatternMismatch
+}
+
+// PatternMismatch describes why a body pattern didn't match
+type PatternMismatch struct {
+	Index int
+	// Path is JSONPath of the value that was compared
+	Path     string
+	Expected string
+	Actual   string
+	Reason   string
+}
+
+func (m PatternMismatch) String() string {
+	return fmt.Sprintf("body_patterns[%d] %s", m.Index, m.describe())
+}
+
+func (m PatternMismatch) describe() string {
+	if m.Reason != "" {
+		return fmt.Sprintf("%s: %s", m.Path, m.Reason)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", m.Path, m.Expected, m.Actual)
+}
+
+// closestMocks ranks mocks by the number of matched body patterns
+func closestMocks(req *matchRequest, mocks []DittoMock) []Candidate {
+	candidates := make([]Candidate, 0, len(mocks))
+	for _, mock := range mocks {
+		candidates = append(candidates, explain(req, mock))
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Matched != candidates[j].Matched {
+			return candidates[i].Matched > candidates[j].Matched
+		}
+		return len(candidates[i].Mismatches) < len(candidates[j].Mismatches)
+	})
+
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	return candidates
+}
+
+// explain evaluates every body pattern of the mock
+func explain(req *matchRequest, mock DittoMock) Candidate {
+	c := Candidate{
+		MockID: mock.ID,
+		Total:  len(mock.Request.BodyPatterns),
+	}
+
+	if c.Total == 0 {
+		c.Mismatches = append(c.Mismatches, PatternMismatch{Path: "$", Reason: "mock doesn't have body patterns"})
+		return c
+	}
+
+	for i, pattern := range mock.Request.BodyPatterns {
+		ok, err := matchPattern(req, pattern)
+		if ok && err == nil {
+			c.Matched++
+			continue
+		}
+
+		m := describeMismatch(req, pattern)
+		m.Index = i
+		if err != nil {
+			m.Reason = err.Error()
+		}
+		c.Mismatches = append(c.Mismatches, m)
+	}
+
+	return c
+}
+
+// describeMismatch explains the first part of the pattern that didn't match
+func describeMismatch(req *matchRequest, pattern DittoBodyPattern) PatternMismatch {
+	if len(pattern.EqualToJson) > 0 {
+		if ok, _ := equalToJSONMatcher(req, pattern); !ok {
+			return describeJSONMismatch(req, pattern)
+		}
+	}
+
+	if pattern.MatchesJsonPath != nil {
+		if ok, _ := jsonPathMatcher(req, pattern.MatchesJsonPath); !ok {
+			return describeJSONPathMismatch(req, pattern.MatchesJsonPath)
+		}
+	}
+
+	if pattern.MatchesCEL != "" {
+		ok, err := celMatcher(req, pattern)
+		if err != nil {
+			return PatternMismatch{Path: "$", Reason: err.Error()}
+		}
+		if !ok {
+			return PatternMismatch{Path: "$", Reason: fmt.Sprintf("cel expression %q is false", pattern.MatchesCEL)}
+		}
+	}
+
+	if pattern.MatchesField != nil {
+		if ok, err := fieldMatcher(req, pattern.MatchesField); !ok {
+			return describeFieldMismatch(req, pattern.MatchesField, err)
+		}
+	}
+
+	for i, sub := range pattern.AllOf {
+		if ok, _ := matchPattern(req, sub); !ok {
+			m := describeMismatch(req, sub)
+			m.Path = fmt.Sprintf("all_of[%d] %s", i, m.Path)
+			return m
+		}
+	}
+
+	if len(pattern.AnyOf) > 0 {
+		if ok, _ := matchAny(req, pattern.AnyOf); !ok {
+			alternatives := make([]string, 0, len(pattern.AnyOf))
+			for i, sub := range pattern.AnyOf {
+				m := describeMismatch(req, sub)
+				m.Path = fmt.Sprintf("any_of[%d] %s", i, m.Path)
+				alternatives = append(alternatives, m.describe())
+			}
+			return PatternMismatch{
+				Path:   "$",
+				Reason: truncate("none of any_of patterns matched: " + strings.Join(alternatives, "; ")),
+			}
+		}
+	}
+
+	if pattern.Not != nil {
+		if ok, _ := matchPattern(req, *pattern.Not); ok {
+			return PatternMismatch{Path: "$", Reason: "not pattern matched"}
+		}
+	}
+
+	if pattern.hasMessagePatterns() {
+		if ok, _ := matchMessages(req, pattern); !ok {
+			return describeMessagesMismatch(req, pattern)
+		}
+	}
+
+	if pattern.Deadline != nil && !pattern.Deadline.matches(req.timeLeft, req.hasDeadline) {
+		m := PatternMismatch{Path: "deadline", Expected: pattern.Deadline.describe(), Actual: "not set"}
+		if req.hasDeadline {
+			m.Actual = req.timeLeft.Round(time.Millisecond).String()
+		}
+		return m
+	}
+
+	return PatternMismatch{Path: "$"}
+}
+
+func describeJSONPathMismatch(req *matchRequest, pattern *JSONPathWrapper) PatternMismatch {
+	m := PatternMismatch{
+		Path:     pattern.Expression,
+		Expected: pattern.describe(),
+	}
+
+	prg := pattern.program()
+	if prg.err != nil {
+		m.Reason = prg.err.Error()
+		return m
+	}
+
+	nodes, err := jsonPathNodes(req, prg)
+	if err != nil {
+		m.Reason = err.Error()
+		return m
+	}
+
+	switch len(nodes) {
+	case 0:
+		m.Actual = "nothing"
+	case 1:
+		m.Actual = truncate(nodes[0].String())
+	default:
+		values := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			values = append(values, n.String())
+		}
+		m.Actual = truncate("[" + strings.Join(values, ", ") + "]")
+	}
+
+	return m
+}
+
+func describeFieldMismatch(req *matchRequest, f *FieldPattern, err error) PatternMismatch {
+	m := PatternMismatch{
+		Path:     f.Field,
+		Expected: f.describe(),
+	}
+
+	if err != nil {
+		m.Reason = err.Error()
+		return m
+	}
+
+	values, present, err := f.compiled.values(req)
+	if err != nil {
+		m.Reason = err.Error()
+		return m
+	}
+
+	switch {
+	case f.IsSet != nil && present:
+		m.Actual = "set"
+	case f.IsSet != nil, len(values) == 0:
+		m.Actual = "nothing"
+	case len(values) == 1:
+		m.Actual = truncate(formatValue(f.compiled.leaf, values[0]))
+	default:
+		formatted := make([]string, 0, len(values))
+		for _, v := range values {
+			formatted = append(formatted, formatValue(f.compiled.leaf, v))
+		}
+		m.Actual = truncate("[" + strings.Join(formatted, ", ") + "]")
+	}
+
+	return m
+}
+
+// describeJSONMismatch finds the first value that differs between the request and expected json
+func describeJSONMismatch(req *matchRequest, pattern DittoBodyPattern) PatternMismatch {
+	actual := req.jsonValue()
+	if actual.err != nil {
+		return PatternMismatch{Path: "$", Reason: actual.err.Error()}
+	}
+	expected := pattern.expectedJSON()
+	if expected.err != nil {
+		return PatternMismatch{Path: "$", Reason: expected.err.Error()}
+	}
+
+	path, exp, act := jsonDiff("$", expected.value, actual.value, pattern.jsonOptions())
+	if path == "" {
+		path = "$"
+	}
+
+	return PatternMismatch{
+		Path:     path,
+		Expected: truncate(exp),
+		Actual:   truncate(act),
+	}
+}
+
+// jsonDiff returns the path and the values of the first difference
+func jsonDiff(path string, expected, actual interface{}, opts jsonOptions) (string, string, string) {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(exp)+len(act))
+		for k := range exp {
+			keys = append(keys, k)
+		}
+		for k := range act {
+			if _, ok := exp[k]; !ok && !opts.ignoreExtraFields {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			expVal, expOk := exp[k]
+			actVal, actOk := act[k]
+			switch {
+			case !expOk:
+				return path + "." + k, "nothing", toJSON(actVal)
+			case !actOk:
+				return path + "." + k, toJSON(expVal), "nothing"
+			}
+			if p, e, a := jsonDiff(path+"."+k, expVal, actVal, opts); p != "" {
+				return p, e, a
+			}
+		}
+		return "", "", ""
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(act) != len(exp) {
+			break
+		}
+
+		// elements of unordered arrays can't be paired, so the whole array is reported
+		if opts.ignoreArrayOrder {
+			if jsonIncludes(exp, act, opts) {
+				return "", "", ""
+			}
+			break
+		}
+
+		for i := range exp {
+			if p, e, a := jsonDiff(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i], opts); p != "" {
+				return p, e, a
+			}
+		}
+		return "", "", ""
+	}
+
+	expJS, actJS := toJSON(expected), toJSON(actual)
+	if expJS == actJS {
+		return "", "", ""
+	}
+
+	return path, expJS, actJS
+}
+
+func toJSON(val interface{}) string {
+	js, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprint(val)
+	}
+	return string(js)
+}
+
+// truncate cuts long values at a rune boundary, so the result stays valid utf-8
+func truncate(s string) string {
+	if len(s) <= maxValueLen {
+		return s
+	}
+	n := maxValueLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
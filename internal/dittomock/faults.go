@@ -0,0 +1,236 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vadimi/grpc-ditto/api"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// FaultRule injects failures into the calls of the matching methods
+type FaultRule struct {
+	ID string
+	// Method is a glob of fully qualified grpc method, empty value matches all methods
+	Method string
+	// Percentage of the matching calls the fault is injected into
+	Percentage float64
+	// Metadata values must be present in the request metadata
+	Metadata map[string]string
+	Delay    time.Duration
+	Status   *RpcStatus
+	// ResetConnection closes the client connection
+	ResetConnection bool
+	// ResetStream resets only http/2 stream of the call
+	ResetStream bool
+	// AfterMessages applies the fault after the number of response messages are sent,
+	// the stream is truncated if none of Status, ResetConnection and ResetStream is set
+	AfterMessages *uint32
+}
+
+func (r FaultRule) matches(method string, md metadata.MD) bool {
+	if r.Method != "" {
+		if ok, _ := path.Match(r.Method, method); !ok {
+			return false
+		}
+	}
+	return metadataContains(md, r.Metadata)
+}
+
+// ToProto converts the rule back to api representation
+func (r FaultRule) ToProto() *api.FaultRule {
+	rule := &api.FaultRule{
+		Id:              r.ID,
+		Method:          r.Method,
+		Percentage:      proto.Float64(r.Percentage),
+		Metadata:        r.Metadata,
+		ResetConnection: r.ResetConnection,
+		ResetStream:     r.ResetStream,
+		AfterMessages:   r.AfterMessages,
+	}
+	if r.Delay > 0 {
+		rule.Delay = r.Delay.String()
+	}
+	if r.Status != nil {
+		rule.Status = &api.RpcStatus{Code: code.Code(r.Status.Code), Message: r.Status.Message}
+	}
+	return rule
+}
+
+func FaultRuleFromProto(src *api.FaultRule) (FaultRule, error) {
+	r := FaultRule{
+		ID:              src.GetId(),
+		Method:          src.GetMethod(),
+		Percentage:      100,
+		Metadata:        src.GetMetadata(),
+		ResetConnection: src.GetResetConnection(),
+		ResetStream:     src.GetResetStream(),
+		AfterMessages:   src.AfterMessages,
+	}
+
+	if _, err := path.Match(r.Method, ""); err != nil {
+		return r, fmt.Errorf("invalid method glob %q: %w", r.Method, err)
+	}
+
+	if src.Percentage != nil {
+		r.Percentage = src.GetPercentage()
+		if r.Percentage < 0 || r.Percentage > 100 {
+			return r, fmt.Errorf("percentage %v must be between 0 and 100", r.Percentage)
+		}
+	}
+
+	if src.GetDelay() != "" {
+		delay, err := time.ParseDuration(src.GetDelay())
+		if err != nil {
+			return r, fmt.Errorf("invalid delay: %w", err)
+		}
+		if delay < 0 {
+			return r, errors.New("delay must not be negative")
+		}
+		r.Delay = delay
+	}
+
+	if src.GetStatus() != nil {
+		r.Status = &RpcStatus{
+			Code:    codes.Code(src.GetStatus().GetCode()),
+			Message: src.GetStatus().GetMessage(),
+		}
+		if r.Status.Code == codes.OK {
+			return r, errors.New("status code must not be OK")
+		}
+		if r.Status.Code > codes.Unauthenticated {
+			return r, fmt.Errorf("unknown status code %d", r.Status.Code)
+		}
+	}
+
+	if r.Status != nil && r.ResetConnection {
+		return r, errors.New("status and reset_connection can't be used together")
+	}
+
+	if r.ResetStream && (r.Status != nil || r.ResetConnection) {
+		return r, errors.New("reset_stream can't be used together with status or reset_connection")
+	}
+
+	if r.Delay == 0 && r.Status == nil && !r.ResetConnection && !r.ResetStream && r.AfterMessages == nil {
+		return r, errors.New("delay, status, reset_connection, reset_stream or after_messages is required")
+	}
+
+	return r, nil
+}
+
+// ParseFaultRules loads json or yaml array of fault rules from the file
+func ParseFaultRules(file string) ([]FaultRule, error) {
+	js, err := readMockFile(file, strings.ToLower(filepath.Ext(file)))
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := []json.RawMessage{}
+	if err := json.Unmarshal(js, &msgs); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	rules := make([]FaultRule, 0, len(msgs))
+	for i, msg := range msgs {
+		src := MockSource{File: file, Index: i}
+
+		rule := &api.FaultRule{}
+		if err := protojson.Unmarshal(msg, rule); err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+
+		r, err := FaultRuleFromProto(rule)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// FaultInjector chooses fault rules for the calls, the same seed produces
+// the same sequence of faults for the same sequence of calls
+type FaultInjector struct {
+	mu    sync.Mutex
+	rules []FaultRule
+	rnd   *rand.Rand
+	seed  int64
+}
+
+func NewFaultInjector(seed int64, rules []FaultRule) *FaultInjector {
+	return &FaultInjector{
+		rules: rules,
+		rnd:   rand.New(rand.NewSource(seed)),
+		seed:  seed,
+	}
+}
+
+func (f *FaultInjector) Seed() int64 {
+	return f.seed
+}
+
+func (f *FaultInjector) Add(rule FaultRule) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = append(f.rules, rule)
+}
+
+func (f *FaultInjector) Rules() []FaultRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.rules)
+}
+
+func (f *FaultInjector) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = nil
+}
+
+// Pick returns the first rule that matches the call and wins the percentage roll,
+// nil is returned if no fault has to be injected
+func (f *FaultInjector) Pick(method string, md metadata.MD) *FaultRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i := range f.rules {
+		rule := f.rules[i]
+		if !rule.matches(method, md) {
+			continue
+		}
+
+		// every matching rule rolls, so the sequence doesn't depend on the percentage values
+		if f.rnd.Float64()*100 < rule.Percentage {
+			return &rule
+		}
+	}
+
+	return nil
+}
+
+// metadataContains checks that every value is present in the metadata
+func metadataContains(md metadata.MD, values map[string]string) bool {
+	for k, v := range values {
+		if !slices.Contains(md.Get(k), v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package dittomock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseFaultRules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "faults.yaml")
+	err := os.WriteFile(file, []byte(`
+- id: flaky-users
+  method: /users.UserService/*
+  percentage: 25
+  metadata: { x-env: test }
+  status: { code: UNAVAILABLE, message: try again }
+- method: /events.Events/Watch
+  delay: 100ms
+  after_messages: 3
+`), 0o600)
+	require.NoError(t, err)
+
+	rules, err := ParseFaultRules(file)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, "flaky-users", rules[0].ID)
+	assert.Equal(t, 25.0, rules[0].Percentage)
+	assert.Equal(t, &RpcStatus{Code: codes.Unavailable, Message: "try again"}, rules[0].Status)
+	assert.Equal(t, 100.0, rules[1].Percentage)
+	assert.Equal(t, uint32(3), *rules[1].AfterMessages)
+	assert.Equal(t, "100ms", rules[1].Delay.String())
+}
+
+func TestFaultRuleFromProtoErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *api.FaultRule
+		err  string
+	}{
+		{name: "NoFault", rule: &api.FaultRule{Method: "/svc/*"}, err: "is required"},
+		{name: "Glob", rule: &api.FaultRule{Method: "/svc/[", Delay: "1s"}, err: "invalid method glob"},
+		{name: "Percentage", rule: &api.FaultRule{Percentage: proto.Float64(101), Delay: "1s"}, err: "between 0 and 100"},
+		{name: "Delay", rule: &api.FaultRule{Delay: "soon"}, err: "invalid delay"},
+		{name: "StatusAndReset", rule: &api.FaultRule{Status: &api.RpcStatus{Code: code.Code_UNAVAILABLE}, ResetConnection: true}, err: "can't be used together"},
+		{name: "ResetStreamAndConnection", rule: &api.FaultRule{ResetStream: true, ResetConnection: true}, err: "can't be used together"},
+		{name: "ResetStreamAndStatus", rule: &api.FaultRule{ResetStream: true, Status: &api.RpcStatus{Code: code.Code_UNAVAILABLE}}, err: "can't be used together"},
+		{name: "StatusOK", rule: &api.FaultRule{Status: &api.RpcStatus{Code: code.Code_OK}}, err: "must not be OK"},
+		{name: "StatusOKAfterMessages", rule: &api.FaultRule{Status: &api.RpcStatus{}, AfterMessages: proto.Uint32(2)}, err: "must not be OK"},
+		{name: "UnknownStatus", rule: &api.FaultRule{Status: &api.RpcStatus{Code: 42}}, err: "unknown status code 42"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := FaultRuleFromProto(test.rule)
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestFaultInjectorPick(t *testing.T) {
+	rules := []FaultRule{
+		{ID: "users", Method: "/users.UserService/*", Percentage: 100, Metadata: map[string]string{"x-env": "test"}},
+		{ID: "half", Percentage: 50},
+	}
+
+	md := metadata.Pairs("x-env", "test")
+	rule := NewFaultInjector(1, rules).Pick("/users.UserService/Get", md)
+	require.NotNil(t, rule)
+	assert.Equal(t, "users", rule.ID)
+
+	picks := func(seed int64) []bool {
+		f := NewFaultInjector(seed, rules)
+		var result []bool
+		for i := 0; i < 100; i++ {
+			result = append(result, f.Pick("/users.UserService/Get", nil) != nil)
+		}
+		return result
+	}
+
+	first := picks(42)
+	assert.Equal(t, first, picks(42), "the same seed produces the same faults")
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+
+	f := NewFaultInjector(1, rules)
+	f.Clear()
+	assert.Nil(t, f.Pick("/users.UserService/Get", md))
+}
@@ -0,0 +1,617 @@
+package dittomock
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// fieldSegmentRegexp matches path segments like items or items[0]
+var fieldSegmentRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?:\[([^\]]*)\])?$`)
+
+// compiledField is the field path resolved against the method input
+// and the operator bound to the field type
+type compiledField struct {
+	method *desc.MethodDescriptor
+	// types resolve google.protobuf.Any values of the request
+	types *protoregistry.Types
+	// stream is true if the request is the list of client streaming messages
+	stream bool
+	// index selects the message of client streaming requests, -1 selects all of them
+	index int
+	steps []fieldStep
+	// leaf describes the selected values, it's the map value for maps
+	leaf  protoreflect.FieldDescriptor
+	match func(v protoreflect.Value) (bool, error)
+	err   error
+}
+
+type fieldStep struct {
+	fd protoreflect.FieldDescriptor
+	// index of repeated field element, -1 selects all elements
+	index int
+	// key of map entry, nil selects all values
+	key *protoreflect.MapKey
+}
+
+// CheckFieldPattern resolves the field and its operator values against the method input message,
+// stream is true if the pattern is applied to all messages of client streaming request
+func CheckFieldPattern(method *desc.MethodDescriptor, f *FieldPattern, stream bool) error {
+	_, err := compileField(method, f, stream)
+	return err
+}
+
+func compileField(method *desc.MethodDescriptor, f *FieldPattern, stream bool) (*compiledField, error) {
+	if method == nil {
+		return nil, errors.New("method descriptor is required to resolve field")
+	}
+
+	c := &compiledField{method: method, types: inputTypes(method), stream: stream, index: -1}
+	path := f.Field
+	if strings.HasPrefix(path, "[") {
+		if !stream {
+			return nil, fmt.Errorf("field %q: message index is supported only for client streaming requests", f.Field)
+		}
+		end := strings.Index(path, "]")
+		idx, err := strconv.Atoi(path[1:max(end, 1)])
+		if end < 0 || err != nil || idx < 0 {
+			return nil, fmt.Errorf("field %q: invalid message index", f.Field)
+		}
+		c.index = idx
+		path = strings.TrimPrefix(path[end+1:], ".")
+	}
+
+	if path == "" {
+		return nil, errors.New("field is required")
+	}
+
+	md := method.GetInputType().UnwrapMessage()
+	segments := strings.Split(path, ".")
+	for i, seg := range segments {
+		m := fieldSegmentRegexp.FindStringSubmatch(seg)
+		if m == nil {
+			return nil, fmt.Errorf("field %q: invalid path segment %q", f.Field, seg)
+		}
+		if md == nil {
+			return nil, fmt.Errorf("field %q: scalar value doesn't have %q field", f.Field, m[1])
+		}
+
+		fd := md.Fields().ByName(protoreflect.Name(m[1]))
+		if fd == nil {
+			if jsonFd := md.Fields().ByJSONName(m[1]); jsonFd != nil {
+				return nil, fmt.Errorf("field %q not found in %s, use original field name %q", m[1], md.FullName(), jsonFd.Name())
+			}
+			return nil, fmt.Errorf("field %q not found in %s", m[1], md.FullName())
+		}
+
+		step := fieldStep{fd: fd, index: -1}
+		if strings.Contains(seg, "[") {
+			switch {
+			case fd.IsMap():
+				key, err := parseMapKey(fd.MapKey(), m[2])
+				if err != nil {
+					return nil, fmt.Errorf("field %q: %w", f.Field, err)
+				}
+				step.key = &key
+			case fd.IsList():
+				idx, err := strconv.Atoi(m[2])
+				if err != nil || idx < 0 {
+					return nil, fmt.Errorf("field %q: invalid index %q", f.Field, m[2])
+				}
+				step.index = idx
+			default:
+				return nil, fmt.Errorf("field %q: %s is not repeated or map", f.Field, fd.Name())
+			}
+		}
+		c.steps = append(c.steps, step)
+
+		c.leaf = fd
+		if fd.IsMap() {
+			c.leaf = fd.MapValue()
+		}
+
+		md = nil
+		if i < len(segments)-1 {
+			if c.leaf.Message() == nil || isWellKnownValue(c.leaf.Message()) {
+				return nil, fmt.Errorf("field %q: %s is not a message", f.Field, fd.Name())
+			}
+			md = c.leaf.Message()
+		}
+	}
+
+	if f.IsSet == nil {
+		match, err := fieldOperator(c.leaf, f)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Field, err)
+		}
+		c.match = match
+	}
+
+	return c, nil
+}
+
+// fieldOperator parses operator values according to the field type
+func fieldOperator(fd protoreflect.FieldDescriptor, f *FieldPattern) (func(v protoreflect.Value) (bool, error), error) {
+	switch {
+	case f.Between != nil:
+		if err := checkComparable(fd); err != nil {
+			return nil, err
+		}
+		minVal, err := parseOperand(fd, f.Between.Min)
+		if err != nil {
+			return nil, err
+		}
+		maxVal, err := parseOperand(fd, f.Between.Max)
+		if err != nil {
+			return nil, err
+		}
+		if compareTyped(minVal, maxVal) > 0 {
+			return nil, fmt.Errorf("between min %q is greater than max %q", f.Between.Min, f.Between.Max)
+		}
+		return func(v protoreflect.Value) (bool, error) {
+			val := nativeValue(fd, v)
+			return compareTyped(val, minVal) >= 0 && compareTyped(val, maxVal) <= 0, nil
+		}, nil
+	case f.In != nil:
+		if len(f.In) == 0 {
+			return nil, errors.New("in requires at least one value")
+		}
+		operands := make([]interface{}, 0, len(f.In))
+		for _, s := range f.In {
+			operand, err := parseOperand(fd, s)
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+		}
+		return func(v protoreflect.Value) (bool, error) {
+			val := nativeValue(fd, v)
+			for _, operand := range operands {
+				if equalTyped(val, operand) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}, nil
+	case f.Contains != "":
+		return stringOperator(fd, func(s string) bool { return strings.Contains(s, f.Contains) })
+	case f.StartsWith != "":
+		return stringOperator(fd, func(s string) bool { return strings.HasPrefix(s, f.StartsWith) })
+	case f.EndsWith != "":
+		return stringOperator(fd, func(s string) bool { return strings.HasSuffix(s, f.EndsWith) })
+	case f.Regexp != "":
+		re, err := regexp.Compile(f.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp %q: %w", f.Regexp, err)
+		}
+		return stringOperator(fd, re.MatchString)
+	case f.Gt != "":
+		return compareOperator(fd, f.Gt, func(c int) bool { return c > 0 })
+	case f.Gte != "":
+		return compareOperator(fd, f.Gte, func(c int) bool { return c >= 0 })
+	case f.Lt != "":
+		return compareOperator(fd, f.Lt, func(c int) bool { return c < 0 })
+	case f.Lte != "":
+		return compareOperator(fd, f.Lte, func(c int) bool { return c <= 0 })
+	}
+
+	operand, err := parseOperand(fd, f.Equals)
+	if err != nil {
+		return nil, err
+	}
+	return func(v protoreflect.Value) (bool, error) {
+		return equalTyped(nativeValue(fd, v), operand), nil
+	}, nil
+}
+
+func compareOperator(fd protoreflect.FieldDescriptor, s string, accept func(c int) bool) (func(v protoreflect.Value) (bool, error), error) {
+	if err := checkComparable(fd); err != nil {
+		return nil, err
+	}
+	operand, err := parseOperand(fd, s)
+	if err != nil {
+		return nil, err
+	}
+	return func(v protoreflect.Value) (bool, error) {
+		return accept(compareTyped(nativeValue(fd, v), operand)), nil
+	}, nil
+}
+
+// stringOperator applies to strings and enum names, wrappers are unwrapped the same way as operands
+func stringOperator(fd protoreflect.FieldDescriptor, match func(s string) bool) (func(v protoreflect.Value) (bool, error), error) {
+	valueFd := fd
+	if md := fd.Message(); md != nil && isWrapper(md) {
+		valueFd = md.Fields().ByName("value")
+	}
+
+	if valueFd.Kind() != protoreflect.StringKind && valueFd.Kind() != protoreflect.EnumKind {
+		return nil, fmt.Errorf("string operators are not supported for %s fields", valueFd.Kind())
+	}
+	return func(v protoreflect.Value) (bool, error) {
+		if valueFd != fd {
+			v = v.Message().Get(valueFd)
+		}
+		return match(formatValue(valueFd, v)), nil
+	}, nil
+}
+
+func checkComparable(fd protoreflect.FieldDescriptor) error {
+	if md := fd.Message(); md != nil && !isWellKnownValue(md) {
+		return fmt.Errorf("%s values can't be compared, only eq and in are supported", md.FullName())
+	}
+	return nil
+}
+
+// parseOperand converts the pattern value to the go type of the field value
+func parseOperand(fd protoreflect.FieldDescriptor, s string) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return int64(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unknown %s value %q", fd.Enum().FullName(), s)
+		}
+		return n, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return n, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an unsigned integer", s)
+		}
+		return n, nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return n, nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", s)
+		}
+		return b, nil
+	case protoreflect.StringKind:
+		return s, nil
+	case protoreflect.BytesKind:
+		return decodeBytes(s)
+	}
+
+	md := fd.Message()
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not RFC3339 timestamp", s)
+		}
+		return t.UTC(), nil
+	case "google.protobuf.Duration":
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a duration", s)
+		}
+		return d, nil
+	}
+
+	if isWrapper(md) {
+		return parseOperand(md.Fields().ByName("value"), s)
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal([]byte(s), msg); err != nil {
+		return nil, fmt.Errorf("invalid %s json: %w", md.FullName(), err)
+	}
+	return protoreflect.Message(msg), nil
+}
+
+// nativeValue converts the field value to the go type used for comparison
+func nativeValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		return int64(v.Enum())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	}
+
+	msg := v.Message()
+	md := msg.Descriptor()
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return time.Unix(msg.Get(md.Fields().ByName("seconds")).Int(), msg.Get(md.Fields().ByName("nanos")).Int()).UTC()
+	case "google.protobuf.Duration":
+		return time.Duration(msg.Get(md.Fields().ByName("seconds")).Int())*time.Second +
+			time.Duration(msg.Get(md.Fields().ByName("nanos")).Int())
+	}
+
+	if isWrapper(md) {
+		valueFd := md.Fields().ByName("value")
+		return nativeValue(valueFd, msg.Get(valueFd))
+	}
+
+	return msg
+}
+
+// equalTyped compares values of the same field type
+func equalTyped(a, b interface{}) bool {
+	if am, ok := a.(protoreflect.Message); ok {
+		bm, ok := b.(protoreflect.Message)
+		return ok && proto.Equal(am.Interface(), bm.Interface())
+	}
+	return compareTyped(a, b) == 0
+}
+
+// compareTyped compares values of the same comparable field type
+func compareTyped(a, b interface{}) int {
+	switch x := a.(type) {
+	case int64:
+		return cmp.Compare(x, b.(int64))
+	case uint64:
+		return cmp.Compare(x, b.(uint64))
+	case float64:
+		return cmp.Compare(x, b.(float64))
+	case string:
+		return strings.Compare(x, b.(string))
+	case bool:
+		return compareBool(x, b.(bool))
+	case []byte:
+		return bytes.Compare(x, b.([]byte))
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	case time.Duration:
+		return cmp.Compare(x, b.(time.Duration))
+	}
+	// messages are only compared for equality
+	return 1
+}
+
+// formatValue renders the value for diagnostics and string operators
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch val := nativeValue(fd, v).(type) {
+		case time.Time:
+			return val.Format(time.RFC3339Nano)
+		case protoreflect.Message:
+			js, err := protojson.Marshal(val.Interface())
+			if err != nil {
+				return err.Error()
+			}
+			return string(js)
+		default:
+			return fmt.Sprint(val)
+		}
+	}
+	return fmt.Sprint(nativeValue(fd, v))
+}
+
+// decodeBytes accepts base64 and hex with 0x prefix
+func decodeBytes(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "0x") {
+		b, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("%q is not hex", s)
+		}
+		return b, nil
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is not base64 or hex with 0x prefix", s)
+}
+
+func parseMapKey(fd protoreflect.FieldDescriptor, s string) (protoreflect.MapKey, error) {
+	var v protoreflect.Value
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		v = protoreflect.ValueOfString(s)
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q", s)
+		}
+		v = protoreflect.ValueOfBool(b)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q", s)
+		}
+		v = protoreflect.ValueOfInt32(int32(n))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q", s)
+		}
+		v = protoreflect.ValueOfInt64(n)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q", s)
+		}
+		v = protoreflect.ValueOfUint32(uint32(n))
+	default:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q", s)
+		}
+		v = protoreflect.ValueOfUint64(n)
+	}
+	return v.MapKey(), nil
+}
+
+// isWellKnownValue returns true for messages that are matched as values
+func isWellKnownValue(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp", "google.protobuf.Duration":
+		return true
+	}
+	return isWrapper(md)
+}
+
+func isWrapper(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile().Package() == "google.protobuf" &&
+		strings.HasSuffix(string(md.Name()), "Value") &&
+		md.Fields().Len() == 1 && md.Fields().ByName("value") != nil
+}
+
+// fieldMatcher matches the field pattern against the request messages
+func fieldMatcher(req *matchRequest, f *FieldPattern) (bool, error) {
+	c := f.compiled
+	if c == nil {
+		return false, fmt.Errorf("field pattern %q is not compiled", f.Field)
+	}
+	if c.err != nil {
+		return false, c.err
+	}
+
+	values, present, err := c.values(req)
+	if err != nil {
+		return false, err
+	}
+
+	if f.IsSet != nil {
+		return present == *f.IsSet, nil
+	}
+
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	for _, v := range values {
+		ok, err := c.match(v)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// values selects the field values of the request, present is false if the field is not set
+func (c *compiledField) values(req *matchRequest) ([]protoreflect.Value, bool, error) {
+	msgs, err := req.inputMessages(c.method, c.types, c.stream)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if c.index >= 0 {
+		if c.index >= len(msgs) {
+			return nil, false, nil
+		}
+		msgs = msgs[c.index : c.index+1]
+	}
+
+	present := false
+	var values []protoreflect.Value
+	for i, step := range c.steps {
+		last := i == len(c.steps)-1
+		values = nil
+		for _, m := range msgs {
+			has := m.Has(step.fd)
+			switch {
+			case step.fd.IsList():
+				l := m.Get(step.fd).List()
+				for j := 0; j < l.Len(); j++ {
+					if step.index < 0 || step.index == j {
+						values = append(values, l.Get(j))
+					}
+				}
+			case step.fd.IsMap():
+				mp := m.Get(step.fd).Map()
+				if step.key != nil {
+					if mp.Has(*step.key) {
+						values = append(values, mp.Get(*step.key))
+					}
+				} else {
+					mp.Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+						values = append(values, v)
+						return true
+					})
+				}
+			case has || (last && !step.fd.HasPresence()):
+				// scalars without presence are matched with default values
+				present = present || has
+				values = append(values, m.Get(step.fd))
+			}
+		}
+
+		if last {
+			if step.fd.IsList() || step.fd.IsMap() {
+				present = len(values) > 0
+			}
+			break
+		}
+
+		msgs = make([]protoreflect.Message, 0, len(values))
+		for _, v := range values {
+			msgs = append(msgs, v.Message())
+		}
+	}
+
+	return values, present, nil
+}
+
+// inputMessages returns request messages, client streaming requests have one message per item
+func (r *matchRequest) inputMessages(method *desc.MethodDescriptor, types *protoregistry.Types, stream bool) ([]protoreflect.Message, error) {
+	input, err := r.celInput(method, types, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := input.(type) {
+	case proto.Message:
+		return []protoreflect.Message{v.ProtoReflect()}, nil
+	case []proto.Message:
+		msgs := make([]protoreflect.Message, 0, len(v))
+		for _, m := range v {
+			msgs = append(msgs, m.ProtoReflect())
+		}
+		return msgs, nil
+	}
+
+	return nil, nil
+}
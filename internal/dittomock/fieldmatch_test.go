@@ -0,0 +1,207 @@
+package dittomock
+
+import (
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fieldTestProto = `syntax = "proto3";
+
+package field.test;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/duration.proto";
+import "google/protobuf/wrappers.proto";
+import "google/protobuf/any.proto";
+
+service PaymentService {
+  rpc Pay(Payment) returns (Payment);
+  rpc Upload(stream Payment) returns (Payment);
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  ACTIVE = 1;
+  BLOCKED = 2;
+}
+
+message Payment {
+  Status status = 1;
+  int64 amount = 2;
+  bytes token = 3;
+  google.protobuf.Timestamp created_at = 4;
+  google.protobuf.Duration ttl = 5;
+  google.protobuf.StringValue note = 6;
+  map<string, string> labels = 7;
+  repeated Item items = 8;
+  Item main = 9;
+  google.protobuf.Any details = 10;
+}
+
+message Item {
+  string sku = 1;
+  uint64 price = 2;
+}
+`
+
+func fieldTestResolver(t *testing.T) func(string) *desc.MethodDescriptor {
+	p := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{
+			"field.proto": fieldTestProto,
+		}),
+	}
+	fds, err := p.ParseFiles("field.proto")
+	require.NoError(t, err)
+
+	sd := fds[0].FindService("field.test.PaymentService")
+	return func(method string) *desc.MethodDescriptor {
+		for _, m := range sd.GetMethods() {
+			if "/"+sd.GetFullyQualifiedName()+"/"+m.GetName() == method {
+				return m
+			}
+		}
+		return nil
+	}
+}
+
+func TestFieldMatching(t *testing.T) {
+	resolve := fieldTestResolver(t)
+	set, notSet := true, false
+
+	payment := `{
+		"status": "BLOCKED",
+		"amount": "9007199254740993",
+		"token": "3q2+7w==",
+		"created_at": "2024-01-15T10:00:00Z",
+		"ttl": "90s",
+		"note": "urgent",
+		"labels": {"env": "prod"},
+		"items": [{"sku": "a-1", "price": "100"}, {"sku": "a-2", "price": "250"}],
+		"main": null
+	}`
+
+	tests := []struct {
+		name    string
+		method  string
+		pattern FieldPattern
+		json    string
+		matched bool
+	}{
+		{name: "EnumName", pattern: FieldPattern{Field: "status", Equals: "BLOCKED"}, json: payment, matched: true},
+		{name: "EnumNumber", pattern: FieldPattern{Field: "status", Equals: "2"}, json: payment, matched: true},
+		{name: "EnumIn", pattern: FieldPattern{Field: "status", In: []string{"ACTIVE", "2"}}, json: payment, matched: true},
+		{name: "EnumPrefix", pattern: FieldPattern{Field: "status", StartsWith: "BLOCK"}, json: payment, matched: true},
+		{name: "EnumDefault", pattern: FieldPattern{Field: "status", Equals: "STATUS_UNSPECIFIED"}, json: `{}`, matched: true},
+		{name: "Int64Precision", pattern: FieldPattern{Field: "amount", Gt: "9007199254740992"}, json: payment, matched: true},
+		{name: "Int64PrecisionEq", pattern: FieldPattern{Field: "amount", Equals: "9007199254740992"}, json: payment, matched: false},
+		{name: "BytesBase64", pattern: FieldPattern{Field: "token", Equals: "3q2+7w=="}, json: payment, matched: true},
+		{name: "BytesHex", pattern: FieldPattern{Field: "token", Equals: "0xdeadbeef"}, json: payment, matched: true},
+		{
+			name:    "TimestampBetween",
+			pattern: FieldPattern{Field: "created_at", Between: &Range{Min: "2024-01-01T00:00:00Z", Max: "2024-02-01T00:00:00+01:00"}},
+			json:    payment,
+			matched: true,
+		},
+		{name: "TimestampBefore", pattern: FieldPattern{Field: "created_at", Lt: "2024-01-01T00:00:00Z"}, json: payment, matched: false},
+		{name: "Duration", pattern: FieldPattern{Field: "ttl", Gte: "1m30s"}, json: payment, matched: true},
+		{name: "Wrapper", pattern: FieldPattern{Field: "note", Equals: "urgent"}, json: payment, matched: true},
+		{name: "WrapperNotSet", pattern: FieldPattern{Field: "note", IsSet: &notSet}, json: `{}`, matched: true},
+		{name: "WrapperContains", pattern: FieldPattern{Field: "note", Contains: "gen"}, json: payment, matched: true},
+		{name: "WrapperRegexp", pattern: FieldPattern{Field: "note", Regexp: "^urg"}, json: payment, matched: true},
+		{name: "WrapperPrefixMismatch", pattern: FieldPattern{Field: "note", StartsWith: "low"}, json: payment, matched: false},
+		{
+			name:    "AnyUserType",
+			pattern: FieldPattern{Field: "status", Equals: "ACTIVE"},
+			json:    `{"status": "ACTIVE", "details": {"@type": "type.googleapis.com/field.test.Item", "sku": "a-1", "price": "100"}}`,
+			matched: true,
+		},
+		{name: "MapKey", pattern: FieldPattern{Field: "labels[env]", Equals: "prod"}, json: payment, matched: true},
+		{name: "MapKeyMissing", pattern: FieldPattern{Field: "labels[team]", IsSet: &set}, json: payment, matched: false},
+		{name: "RepeatedIndex", pattern: FieldPattern{Field: "items[1].price", Equals: "250"}, json: payment, matched: true},
+		{name: "RepeatedAll", pattern: FieldPattern{Field: "items.sku", StartsWith: "a-"}, json: payment, matched: true},
+		{name: "RepeatedAllMismatch", pattern: FieldPattern{Field: "items.price", Gt: "100"}, json: payment, matched: false},
+		{name: "Message", pattern: FieldPattern{Field: "items[0]", Equals: `{"sku": "a-1", "price": "100"}`}, json: payment, matched: true},
+		{name: "MessageNotSet", pattern: FieldPattern{Field: "main.sku", Equals: ""}, json: payment, matched: false},
+		{
+			name:    "ClientStreamIndex",
+			method:  "/field.test.PaymentService/Upload",
+			pattern: FieldPattern{Field: "[1].status", Equals: "ACTIVE"},
+			json:    `[{"status": "BLOCKED"}, {"status": "ACTIVE"}]`,
+			matched: true,
+		},
+		{
+			name:    "ClientStreamAll",
+			method:  "/field.test.PaymentService/Upload",
+			pattern: FieldPattern{Field: "status", Equals: "ACTIVE"},
+			json:    `[{"status": "BLOCKED"}, {"status": "ACTIVE"}]`,
+			matched: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method := test.method
+			if method == "" {
+				method = "/field.test.PaymentService/Pay"
+			}
+
+			pattern := test.pattern
+			mock := DittoMock{
+				Request: &DittoRequest{
+					Method:       method,
+					BodyPatterns: []DittoBodyPattern{{MatchesField: &pattern}},
+				},
+			}
+
+			rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+			require.NoError(t, err)
+
+			_, err = rm.Match(method, []byte(test.json))
+			if test.matched {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNotMatched)
+			}
+		})
+	}
+}
+
+func TestFieldMismatchDescription(t *testing.T) {
+	resolve := fieldTestResolver(t)
+
+	mock := DittoMock{
+		Request: &DittoRequest{
+			Method:       "/field.test.PaymentService/Pay",
+			BodyPatterns: []DittoBodyPattern{{MatchesField: &FieldPattern{Field: "status", Equals: "ACTIVE"}}},
+		},
+	}
+	rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+	require.NoError(t, err)
+
+	_, err = rm.Match("/field.test.PaymentService/Pay", []byte(`{"status": "BLOCKED"}`))
+	var notMatched *NotMatchedError
+	require.ErrorAs(t, err, &notMatched)
+	require.Len(t, notMatched.Candidates, 1)
+	assert.Equal(t, `body_patterns[0] status: expected eq "ACTIVE", got BLOCKED`, notMatched.Candidates[0].Mismatches[0].String())
+}
+
+func TestCheckFieldPattern(t *testing.T) {
+	resolve := fieldTestResolver(t)
+	pay := resolve("/field.test.PaymentService/Pay")
+
+	assert.NoError(t, CheckFieldPattern(pay, &FieldPattern{Field: "items[0].sku", Equals: "a"}, false))
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "price", Equals: "1"}, false), `field "price" not found`)
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "createdAt", Equals: "1"}, false), `use original field name "created_at"`)
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "status", Equals: "DELETED"}, false), "unknown field.test.Status value")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "amount", Gt: "a lot"}, false), "is not an integer")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "amount", Contains: "1"}, false), "string operators are not supported")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "main", Gt: "{}"}, false), "can't be compared")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "amount.value", Equals: "1"}, false), "is not a message")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "[0].amount", Equals: "1"}, false), "client streaming")
+	assert.ErrorContains(t, CheckFieldPattern(pay, &FieldPattern{Field: "amount", Between: &Range{Min: "5", Max: "1"}}, false), "greater than max")
+	assert.Error(t, CheckFieldPattern(nil, &FieldPattern{Field: "amount", Equals: "1"}, false))
+}
@@ -0,0 +1,203 @@
+package dittomock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/spyzhov/ajson"
+	"github.com/vadimi/grpc-ditto/api"
+	"google.golang.org/grpc/status"
+)
+
+// ResponseGenerator produces messages of server streaming response from the template,
+// either Repeat times or once per element of the request array selected by Foreach
+type ResponseGenerator struct {
+	Repeat       int
+	Foreach      string
+	BodyTemplate string
+	// Interval is the delay between messages
+	Interval time.Duration
+
+	tmpl    *template.Template
+	foreach []string
+}
+
+// generatorData is available to the generator template
+type generatorData struct {
+	Index   int
+	Item    interface{}
+	Count   int
+	Request interface{}
+}
+
+func responseGenerator(src *api.ResponseGenerator) (*ResponseGenerator, error) {
+	g := &ResponseGenerator{
+		BodyTemplate: src.GetBodyTemplate(),
+	}
+
+	switch src.GetSource().(type) {
+	case *api.ResponseGenerator_Repeat:
+		g.Repeat = int(src.GetRepeat())
+	case *api.ResponseGenerator_Foreach:
+		g.Foreach = src.GetForeach()
+		commands, err := ajson.ParseJSONPath(g.Foreach)
+		if err != nil {
+			return nil, fmt.Errorf("invalid foreach jsonpath %q: %w", g.Foreach, err)
+		}
+		g.foreach = commands
+	default:
+		return nil, errors.New("repeat or foreach is required")
+	}
+
+	if src.GetInterval() != "" {
+		interval, err := time.ParseDuration(src.GetInterval())
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, errors.New("interval must be positive")
+		}
+		g.Interval = interval
+	}
+
+	tmpl, err := template.New("generate").Funcs(funcMap).Parse(g.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse body template: %w", err)
+	}
+	g.tmpl = tmpl
+
+	return g, nil
+}
+
+// Generate renders the messages for the request and passes them to send one by one,
+// it stops if the context is done
+func (g *ResponseGenerator) Generate(ctx context.Context, reqJSON []byte, send func(body []byte) error) error {
+	var request interface{}
+	if err := json.Unmarshal(reqJSON, &request); err != nil {
+		return fmt.Errorf("response generator request: %w", err)
+	}
+
+	count := g.Repeat
+	var items []interface{}
+	if g.foreach != nil {
+		var err error
+		items, err = g.items(reqJSON)
+		if err != nil {
+			return err
+		}
+		count = len(items)
+	}
+
+	for i := 0; i < count; i++ {
+		if i > 0 && g.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-time.After(g.Interval):
+			}
+		}
+
+		data := generatorData{Index: i, Count: count, Request: request}
+		if items != nil {
+			data.Item = items[i]
+		}
+
+		body, err := renderMessage(g.tmpl, data)
+		if err != nil {
+			return fmt.Errorf("response generator message %d: %w", i, err)
+		}
+
+		if err := send(body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// items returns the elements of the array selected by foreach expression,
+// or all selected values if the expression selects multiple values
+func (g *ResponseGenerator) items(reqJSON []byte) ([]interface{}, error) {
+	root, err := ajson.Unmarshal(reqJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := ajson.ApplyJSONPath(root, g.foreach)
+	if err != nil {
+		return nil, fmt.Errorf("foreach %q: %w", g.Foreach, err)
+	}
+
+	if len(nodes) == 1 && nodes[0].IsArray() {
+		nodes = nodes[0].MustArray()
+	}
+
+	items := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		item, err := node.Unpack()
+		if err != nil {
+			return nil, fmt.Errorf("foreach %q: %w", g.Foreach, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// renderMessage executes the template and converts the yaml or json result to json
+func renderMessage(tmpl *template.Template, data generatorData) ([]byte, error) {
+	sb := &bytes.Buffer{}
+	if err := tmpl.Execute(sb, data); err != nil {
+		return nil, err
+	}
+
+	return loadJSON(sb.Bytes())
+}
+
+// Heartbeat is the message sent periodically while server stream is kept open
+type Heartbeat struct {
+	Interval     time.Duration
+	BodyTemplate string
+
+	tmpl *template.Template
+}
+
+func heartbeat(src *api.Heartbeat) (*Heartbeat, error) {
+	interval, err := time.ParseDuration(src.GetInterval())
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	tmpl, err := template.New("heartbeat").Funcs(funcMap).Parse(src.GetBodyTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse body template: %w", err)
+	}
+
+	return &Heartbeat{
+		Interval:     interval,
+		BodyTemplate: src.GetBodyTemplate(),
+		tmpl:         tmpl,
+	}, nil
+}
+
+// Render returns the heartbeat message with the index starting from 0
+func (h *Heartbeat) Render(index int, reqJSON []byte) ([]byte, error) {
+	var request interface{}
+	if err := json.Unmarshal(reqJSON, &request); err != nil {
+		return nil, fmt.Errorf("heartbeat request: %w", err)
+	}
+
+	body, err := renderMessage(h.tmpl, generatorData{Index: index, Request: request})
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat message %d: %w", index, err)
+	}
+	return body, nil
+}
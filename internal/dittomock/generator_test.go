@@ -0,0 +1,143 @@
+package dittomock
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMockLoaderJSON_ResponseGenerator(t *testing.T) {
+	js := `[
+  {
+    "request": { "method": "/ditto.example.HelloService/Hello" },
+    "response": [
+      { "generate": { "repeat": 10000, "body_template": "name: event {{.Index}}", "interval": "10ms" } },
+      { "generate": { "foreach": "$.items", "body_template": "{ \"name\": \"{{.Item.sku}}\" }" } }
+    ]
+  }
+]
+`
+	rm, err := NewRequestMatcher()
+	require.NoError(t, err)
+
+	mocks, err := rm.loadMockJSON(strings.NewReader(js))
+	require.NoError(t, err)
+	require.Len(t, mocks[0].Response, 2)
+
+	repeat := mocks[0].Response[0].Generator
+	require.NotNil(t, repeat)
+	assert.Equal(t, 10000, repeat.Repeat)
+	assert.Equal(t, 10*time.Millisecond, repeat.Interval)
+	assert.Equal(t, "$.items", mocks[0].Response[1].Generator.Foreach)
+
+	for _, invalid := range []string{
+		`{ "body_template": "{}" }`,
+		`{ "repeat": 1, "body_template": "{{.Index" }`,
+		`{ "foreach": "$.[", "body_template": "{}" }`,
+		`{ "repeat": 1, "body_template": "{}", "interval": "soon" }`,
+		`{ "repeat": 1, "body_template": "{}", "interval": "-10ms" }`,
+		`{ "foreach": "$.items", "body_template": "{}", "interval": "0s" }`,
+	} {
+		_, err := rm.loadMockJSON(strings.NewReader(`[{"request": {"method": "/a/b"}, "response": [{"generate": ` + invalid + `}]}]`))
+		assert.Error(t, err, invalid)
+	}
+
+	_, err = rm.loadMockJSON(strings.NewReader(`[{"request": {"method": "/a/b"}, "response": [{"generate": { "repeat": 1, "body_template": "{}", "interval": "-10ms" }}]}]`))
+	assert.ErrorContains(t, err, "interval must be positive")
+}
+
+func TestResponseGenerator(t *testing.T) {
+	tests := []struct {
+		name     string
+		gen      string
+		request  string
+		expected []string
+	}{
+		{
+			name:     "Repeat",
+			gen:      `{ "repeat": 3, "body_template": "{ \"n\": {{add .Index 1}}, \"of\": {{.Count}}, \"user\": \"{{.Request.name}}\" }" }`,
+			request:  `{"name": "Bob"}`,
+			expected: []string{`{"n": 1, "of": 3, "user": "Bob"}`, `{"n": 2, "of": 3, "user": "Bob"}`, `{"n": 3, "of": 3, "user": "Bob"}`},
+		},
+		{
+			name:     "ForeachArray",
+			gen:      `{ "foreach": "$.items", "body_template": "sku: {{.Item.sku}}" }`,
+			request:  `{"items": [{"sku": "a"}, {"sku": "b"}]}`,
+			expected: []string{`{"sku": "a"}`, `{"sku": "b"}`},
+		},
+		{
+			name:     "ForeachValues",
+			gen:      `{ "foreach": "$.items[*].sku", "body_template": "sku: {{.Item}}" }`,
+			request:  `{"items": [{"sku": "a"}, {"sku": "b"}]}`,
+			expected: []string{`{"sku": "a"}`, `{"sku": "b"}`},
+		},
+		{
+			name:    "ForeachNothing",
+			gen:     `{ "foreach": "$.missing", "body_template": "{}" }`,
+			request: `{"items": []}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks, errs := parseMocks([]byte(`[{"request": {"method": "/a/b"}, "response": [{"generate": `+test.gen+`}]}]`), "")
+			require.Empty(t, errs)
+
+			var bodies []string
+			err := mocks[0].Response[0].Generator.Generate(context.Background(), []byte(test.request), func(body []byte) error {
+				bodies = append(bodies, string(body))
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, bodies, len(test.expected))
+			for i := range test.expected {
+				assert.JSONEq(t, test.expected[i], bodies[i])
+			}
+		})
+	}
+}
+
+func TestResponseGeneratorCanceled(t *testing.T) {
+	mocks, errs := parseMocks([]byte(`[{"request": {"method": "/a/b"}, "response": [{"generate": { "repeat": 10000, "body_template": "{}", "interval": "1h" }}]}]`), "")
+	require.Empty(t, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := 0
+	err := mocks[0].Response[0].Generator.Generate(ctx, []byte(`{}`), func(body []byte) error {
+		sent++
+		cancel()
+		return nil
+	})
+
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestMockLoaderJSON_StreamOptions(t *testing.T) {
+	mocks, errs := parseMocks([]byte(`[
+		{"request": {"method": "/a/b"}, "stream": {"heartbeat": {"interval": "30s", "body_template": "seq: {{.Index}}"}}},
+		{"request": {"method": "/a/c"}, "stream": {"keep_open": true}}
+	]`), "")
+	require.Empty(t, errs)
+
+	hb := mocks[0].Stream.Heartbeat
+	require.NotNil(t, hb)
+	assert.True(t, mocks[0].Stream.KeepOpen)
+	assert.Equal(t, 30*time.Second, hb.Interval)
+
+	body, err := hb.Render(7, []byte(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"seq": 7}`, string(body))
+
+	assert.True(t, mocks[1].Stream.KeepOpen)
+	assert.Nil(t, mocks[1].Stream.Heartbeat)
+
+	_, errs = parseMocks([]byte(`[{"request": {"method": "/a/b"}, "stream": {"heartbeat": {"body_template": "{}"}}}]`), "")
+	assert.NotEmpty(t, errs)
+}
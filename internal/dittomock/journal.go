@@ -0,0 +1,84 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// JournalEntry is an unmatched request recorded by the mock server
+type JournalEntry struct {
+	Time   time.Time
+	Method string
+	// Body is request json, client streaming requests are arrays of messages
+	Body json.RawMessage
+	// Candidates are the closest mocks for unmatched requests
+	Candidates []Candidate
+}
+
+// CanceledEntry is a request canceled by the client or finished by the client deadline
+type CanceledEntry struct {
+	Time   time.Time
+	Method string
+	// Body is request json, it's nil if the call was canceled before the request was received
+	Body   json.RawMessage
+	MockID string
+	// Code is Canceled or DeadlineExceeded
+	Code codes.Code
+	// Stage of the call the cancellation was noticed at
+	Stage string
+	// Timeout is the client timeout, it's zero if the deadline is not set
+	Timeout time.Duration
+	// Elapsed is the time from the start of the call to the cancellation
+	Elapsed time.Duration
+}
+
+// Journal keeps a limited number of the most recent entries
+type Journal[E any] struct {
+	mu      sync.Mutex
+	entries []E
+	size    int
+}
+
+func NewJournal[E any](size int) *Journal[E] {
+	return &Journal[E]{
+		entries: make([]E, 0, size),
+		size:    size,
+	}
+}
+
+// Record adds new entry removing the oldest one if the journal is full
+func (j *Journal[E]) Record(e E) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.size <= 0 {
+		return
+	}
+
+	if len(j.entries) == j.size {
+		copy(j.entries, j.entries[1:])
+		j.entries = j.entries[:len(j.entries)-1]
+	}
+
+	j.entries = append(j.entries, e)
+}
+
+// Entries returns recorded entries, the most recent entry goes last
+func (j *Journal[E]) Entries() []E {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	result := make([]E, len(j.entries))
+	copy(result, j.entries)
+	return result
+}
+
+func (j *Journal[E]) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = j.entries[:0]
+}
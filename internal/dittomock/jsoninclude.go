@@ -0,0 +1,114 @@
+package dittomock
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// jsonOptions relax equal_to_json comparison
+type jsonOptions struct {
+	ignoreExtraFields bool
+	ignoreArrayOrder  bool
+}
+
+func (p DittoBodyPattern) jsonOptions() jsonOptions {
+	return jsonOptions{
+		ignoreExtraFields: p.IgnoreExtraFields,
+		ignoreArrayOrder:  p.IgnoreArrayOrder,
+	}
+}
+
+func (o jsonOptions) strict() bool {
+	return !o.ignoreExtraFields && !o.ignoreArrayOrder
+}
+
+// equalToJSONMatcher compares canonical json unless the pattern relaxes the comparison
+func equalToJSONMatcher(req *matchRequest, pattern DittoBodyPattern) (bool, error) {
+	expected := pattern.expectedJSON()
+	if expected.err != nil {
+		return false, expected.err
+	}
+
+	actual := req.jsonValue()
+	if actual.err != nil {
+		return false, actual.err
+	}
+
+	opts := pattern.jsonOptions()
+	if opts.strict() {
+		return bytes.Equal(actual.canonical, expected.canonical), nil
+	}
+
+	return jsonIncludes(expected.value, actual.value, opts), nil
+}
+
+// jsonIncludes compares json values structurally,
+// objects may have extra fields and arrays may be reordered depending on the options
+func jsonIncludes(expected, actual interface{}, opts jsonOptions) bool {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if !opts.ignoreExtraFields && len(act) != len(exp) {
+			return false
+		}
+		for k, expVal := range exp {
+			actVal, ok := act[k]
+			if !ok || !jsonIncludes(expVal, actVal, opts) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(act) != len(exp) {
+			return false
+		}
+		if opts.ignoreArrayOrder {
+			return jsonIncludesUnordered(exp, act, opts)
+		}
+		for i := range exp {
+			if !jsonIncludes(exp[i], act[i], opts) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+// jsonIncludesUnordered finds a distinct actual element for every expected one,
+// partial elements may match several actual elements, so it's a bipartite matching
+func jsonIncludesUnordered(expected, actual []interface{}, opts jsonOptions) bool {
+	// matchedBy[j] is the index of the expected element that took actual element j
+	matchedBy := make([]int, len(actual))
+	for j := range matchedBy {
+		matchedBy[j] = -1
+	}
+
+	var assign func(i int, seen []bool) bool
+	assign = func(i int, seen []bool) bool {
+		for j := range actual {
+			if seen[j] || !jsonIncludes(expected[i], actual[j], opts) {
+				continue
+			}
+			seen[j] = true
+			if matchedBy[j] < 0 || assign(matchedBy[j], seen) {
+				matchedBy[j] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range expected {
+		if !assign(i, make([]bool, len(actual))) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -3,21 +3,227 @@ package dittomock
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	pstruct "github.com/golang/protobuf/ptypes/struct"
 	"github.com/vadimi/grpc-ditto/api"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
 	"sigs.k8s.io/yaml"
 )
 
+// MockSource points to the file and the position of a mock in it
+type MockSource struct {
+	File  string
+	Index int
+}
+
+func (s MockSource) String() string {
+	if s.File == "" {
+		return fmt.Sprintf("[%d]", s.Index)
+	}
+
+	if s.Index < 0 {
+		return s.File
+	}
+
+	return fmt.Sprintf("%s [%d]", s.File, s.Index)
+}
+
+// MockError describes a problem with a particular mock or mock file
+type MockError struct {
+	Source MockSource
+	Err    error
+}
+
+func (e *MockError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Source, e.Err)
+}
+
+func (e *MockError) Unwrap() error {
+	return e.Err
+}
+
+// ParseMocks loads all mock files from mocksPath. Unlike NewRequestMatcher it doesn't stop
+// on the first invalid mock, every problem found is returned as *MockError
+func ParseMocks(mocksPath string) ([]DittoMock, []error) {
+	return ParseMockSources(MockSources{Paths: []string{mocksPath}})
+}
+
+// ParseMockSources loads mocks from all the sources, mocks of the later sources go first
+// as they take precedence, every problem found is returned
+func ParseMockSources(sources MockSources) ([]DittoMock, []error) {
+	bySource := make([][]DittoMock, len(sources.Paths))
+	var errs []error
+
+	err := sources.walk(func(source int, path string, js []byte, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		fileMocks, fileErrs := parseMocks(js, path)
+		bySource[source] = append(bySource[source], fileMocks...)
+		errs = append(errs, fileErrs...)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return flattenSources(bySource), errs
+}
+
+// flattenSources orders mocks by precedence, the mocks of the last source go first,
+// the order of the mocks of every source is preserved
+func flattenSources(bySource [][]DittoMock) []DittoMock {
+	var mocks []DittoMock
+	for i := len(bySource) - 1; i >= 0; i-- {
+		mocks = append(mocks, bySource[i]...)
+	}
+	return mocks
+}
+
+func readMockFile(path, ext string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ext {
+	case ".json":
+		return content, nil
+	case ".ndjson":
+		return ndjsonToJSON(content)
+	}
+
+	return yaml.YAMLToJSON(content)
+}
+
+// parseMocks converts json array of mocks, every mock is parsed independently
+// so that all invalid mocks in the file are reported
+func parseMocks(js []byte, file string) ([]DittoMock, []error) {
+	mocks := []DittoMock{}
+	msgs := []json.RawMessage{}
+	err := json.Unmarshal(js, &msgs)
+	if err != nil {
+		return mocks, []error{&MockError{Source: MockSource{File: file, Index: -1}, Err: err}}
+	}
+
+	var errs []error
+	for i, msg := range msgs {
+		src := MockSource{File: file, Index: i}
+
+		m := &api.DittoMock{}
+		err := protojson.Unmarshal(msg, m)
+		if err != nil {
+			errs = append(errs, &MockError{Source: src, Err: err})
+			continue
+		}
+
+		dm, err := FromProto(m)
+		if err != nil {
+			errs = append(errs, &MockError{Source: src, Err: err})
+			continue
+		}
+
+		dm.Source = src
+		mocks = append(mocks, dm)
+	}
+
+	return mocks, errs
+}
+
 func FromProto(req *api.DittoMock) (DittoMock, error) {
 	m := DittoMock{
-		Response: make([]*DittoResponse, 0, len(req.Response)),
+		ID: req.GetId(),
+	}
+
+	var err error
+	m.Response, err = dittoResponses(req.GetResponse())
+	if err != nil {
+		return m, err
+	}
+
+	if len(req.GetResponses()) > 0 {
+		if len(req.GetResponse()) > 0 {
+			return m, errors.New("response and responses can't be used together")
+		}
+
+		var total uint32
+		for i, src := range req.GetResponses() {
+			resp, err := dittoResponses(src.GetResponse())
+			if err != nil {
+				return m, fmt.Errorf("responses[%d]: %w", i, err)
+			}
+
+			weight := uint32(1)
+			if src.Weight != nil {
+				weight = src.GetWeight()
+			}
+			total += weight
+
+			m.Responses = append(m.Responses, WeightedResponse{Weight: weight, Response: resp})
+		}
+
+		if total == 0 {
+			return m, errors.New("responses must have positive total weight")
+		}
+	}
+
+	if req.GetDelay() != "" {
+		m.Delay, err = time.ParseDuration(req.GetDelay())
+		if err != nil {
+			return m, fmt.Errorf("invalid delay %q: %w", req.GetDelay(), err)
+		}
+		if m.Delay < 0 {
+			return m, fmt.Errorf("invalid delay %q: must not be negative", req.GetDelay())
+		}
+	}
+
+	if opts := req.GetStream(); opts != nil {
+		m.Stream = &StreamOptions{KeepOpen: opts.GetKeepOpen() || opts.GetHeartbeat() != nil}
+		if opts.GetHeartbeat() != nil {
+			hb, err := heartbeat(opts.GetHeartbeat())
+			if err != nil {
+				return m, fmt.Errorf("stream heartbeat: %w", err)
+			}
+			m.Stream.Heartbeat = hb
+		}
+	}
+
+	m.Request = &DittoRequest{
+		Method:       req.Request.GetMethod(),
+		BodyPatterns: make([]DittoBodyPattern, 0, len(req.Request.GetBodyPatterns())),
 	}
 
-	for _, src := range req.Response {
+	for _, reqPattern := range req.Request.GetBodyPatterns() {
+		p, err := bodyPattern(reqPattern)
+		if err != nil {
+			return m, err
+		}
+
+		m.Request.BodyPatterns = append(m.Request.BodyPatterns, p)
+	}
+	return m, nil
+}
+
+// dittoResponses converts the list of response messages
+func dittoResponses(responses []*api.DittoResponse) ([]*DittoResponse, error) {
+	result := make([]*DittoResponse, 0, len(responses))
+	for _, src := range responses {
 		var respBody []byte
 		var err error
 		if src.Response != nil {
@@ -25,19 +231,19 @@ func FromProto(req *api.DittoMock) (DittoMock, error) {
 			case *api.DittoResponse_Body:
 				respBody, err = structToBytes(src.GetBody())
 				if err != nil {
-					return m, fmt.Errorf("structToBytes: %w", err)
+					return nil, fmt.Errorf("structToBytes: %w", err)
 				}
 
 				if len(respBody) == 0 {
 					respBody = []byte("{}")
 				}
 
-				m.Response = append(m.Response, &DittoResponse{
+				result = append(result, &DittoResponse{
 					Body: respBody,
 				})
 			case *api.DittoResponse_Status:
 				status := src.GetStatus()
-				m.Response = append(m.Response, &DittoResponse{
+				result = append(result, &DittoResponse{
 					Body: respBody,
 					Status: &RpcStatus{
 						Code:    codes.Code(status.GetCode()),
@@ -48,42 +254,156 @@ func FromProto(req *api.DittoMock) (DittoMock, error) {
 				respBodyStr := src.GetBodyTemplate()
 				respBody, err := processTemplate([]byte(respBodyStr))
 				if err != nil {
-					return m, fmt.Errorf("cannot parse response body template: %w", err)
+					return nil, fmt.Errorf("cannot parse response body template: %w", err)
 				}
 
 				body, err := loadJSON(respBody)
 				if err != nil {
-					return m, fmt.Errorf("cannot load reponse body: %w", err)
+					return nil, fmt.Errorf("cannot load reponse body: %w", err)
 				}
-				m.Response = append(m.Response, &DittoResponse{
+				result = append(result, &DittoResponse{
 					Body: body,
 				})
+			case *api.DittoResponse_Generate:
+				g, err := responseGenerator(src.GetGenerate())
+				if err != nil {
+					return nil, fmt.Errorf("response generator: %w", err)
+				}
+				result = append(result, &DittoResponse{
+					Generator: g,
+				})
 			}
 		}
 	}
 
-	m.Request = &DittoRequest{
-		Method:       req.Request.GetMethod(),
-		BodyPatterns: make([]DittoBodyPattern, 0, len(req.Request.BodyPatterns)),
+	return result, nil
+}
+
+// bodyPattern converts the pattern together with nested any_of, all_of and not patterns
+func bodyPattern(reqPattern *api.DittoBodyPattern) (DittoBodyPattern, error) {
+	p := DittoBodyPattern{}
+
+	switch reqPattern.GetPattern().(type) {
+	case *api.DittoBodyPattern_EqualToJson:
+		b, err := structToBytes(reqPattern.GetEqualToJson())
+		if err != nil {
+			return p, fmt.Errorf("structToBytes conversion of equal_to_json: %w", err)
+		}
+		p.EqualToJson = b
+	case *api.DittoBodyPattern_IncludesJson:
+		b, err := structToBytes(reqPattern.GetIncludesJson())
+		if err != nil {
+			return p, fmt.Errorf("structToBytes conversion of includes_json: %w", err)
+		}
+		p.EqualToJson = b
+		p.IgnoreExtraFields = true
+	case *api.DittoBodyPattern_MatchesJsonpath:
+		p.MatchesJsonPath = jsonPathWrapper(reqPattern.GetMatchesJsonpath())
+	case *api.DittoBodyPattern_MatchesCel:
+		p.MatchesCEL = reqPattern.GetMatchesCel()
+	case *api.DittoBodyPattern_MatchesField:
+		p.MatchesField = fieldPattern(reqPattern.GetMatchesField())
 	}
 
-	for _, reqPattern := range req.Request.GetBodyPatterns() {
-		p := DittoBodyPattern{}
+	p.IgnoreExtraFields = p.IgnoreExtraFields || reqPattern.GetIgnoreExtraFields()
+	p.IgnoreArrayOrder = reqPattern.GetIgnoreArrayOrder()
+
+	for i, sub := range reqPattern.GetAnyOf() {
+		subPattern, err := bodyPattern(sub)
+		if err != nil {
+			return p, fmt.Errorf("any_of[%d]: %w", i, err)
+		}
+		p.AnyOf = append(p.AnyOf, subPattern)
+	}
+
+	for i, sub := range reqPattern.GetAllOf() {
+		subPattern, err := bodyPattern(sub)
+		if err != nil {
+			return p, fmt.Errorf("all_of[%d]: %w", i, err)
+		}
+		p.AllOf = append(p.AllOf, subPattern)
+	}
+
+	if reqPattern.GetNot() != nil {
+		subPattern, err := bodyPattern(reqPattern.GetNot())
+		if err != nil {
+			return p, fmt.Errorf("not: %w", err)
+		}
+		p.Not = &subPattern
+	}
 
-		switch reqPattern.GetPattern().(type) {
-		case *api.DittoBodyPattern_EqualToJson:
-			b, err := structToBytes(reqPattern.GetEqualToJson())
+	if reqPattern.GetAnyMessage() != nil {
+		subPattern, err := bodyPattern(reqPattern.GetAnyMessage())
+		if err != nil {
+			return p, fmt.Errorf("any_message: %w", err)
+		}
+		p.AnyMessage = &subPattern
+	}
+
+	if reqPattern.GetEveryMessage() != nil {
+		subPattern, err := bodyPattern(reqPattern.GetEveryMessage())
+		if err != nil {
+			return p, fmt.Errorf("every_message: %w", err)
+		}
+		p.EveryMessage = &subPattern
+	}
+
+	if c := reqPattern.GetMessageCount(); c != nil {
+		p.MessageCount = &MessageCount{Min: c.GetMin(), Max: c.Max}
+	}
+
+	for i, step := range reqPattern.GetSequence() {
+		s := SequenceStep{Any: step.GetAny(), Skip: step.GetSkip()}
+		if step.GetMessage() != nil {
+			subPattern, err := bodyPattern(step.GetMessage())
 			if err != nil {
-				return m, fmt.Errorf("structToBytes conversion of equal_to_json: %w", err)
+				return p, fmt.Errorf("sequence[%d]: %w", i, err)
 			}
-			p.EqualToJson = b
-		case *api.DittoBodyPattern_MatchesJsonpath:
-			p.MatchesJsonPath = jsonPathWrapper(reqPattern.GetMatchesJsonpath())
+			s.Message = &subPattern
 		}
+		p.Sequence = append(p.Sequence, s)
+	}
 
-		m.Request.BodyPatterns = append(m.Request.BodyPatterns, p)
+	if d := reqPattern.GetDeadline(); d != nil {
+		deadline, err := deadlinePattern(d)
+		if err != nil {
+			return p, fmt.Errorf("deadline: %w", err)
+		}
+		p.Deadline = deadline
 	}
-	return m, nil
+
+	return p, nil
+}
+
+func deadlinePattern(d *api.DeadlinePattern) (*DeadlinePattern, error) {
+	p := &DeadlinePattern{IsSet: d.IsSet}
+
+	var err error
+	if d.GetLt() != "" {
+		if p.Lt, err = time.ParseDuration(d.GetLt()); err != nil || p.Lt <= 0 {
+			return nil, fmt.Errorf("invalid lt %q", d.GetLt())
+		}
+	}
+
+	if d.GetGt() != "" {
+		if p.Gt, err = time.ParseDuration(d.GetGt()); err != nil || p.Gt <= 0 {
+			return nil, fmt.Errorf("invalid gt %q", d.GetGt())
+		}
+	}
+
+	if p.Lt > 0 && p.Lt <= p.Gt {
+		return nil, fmt.Errorf("lt %s must be greater than gt %s", p.Lt, p.Gt)
+	}
+
+	if p.IsSet != nil && !*p.IsSet && (p.Lt > 0 || p.Gt > 0) {
+		return nil, errors.New("lt and gt require the deadline to be set")
+	}
+
+	if p.IsSet == nil && p.Lt == 0 && p.Gt == 0 {
+		return nil, errors.New("is_set, lt or gt is required")
+	}
+
+	return p, nil
 }
 
 func jsonPathWrapper(p *api.JSONPathPattern) *JSONPathWrapper {
@@ -100,6 +420,31 @@ func jsonPathWrapper(p *api.JSONPathPattern) *JSONPathWrapper {
 		w.Regexp = p.GetRegexp()
 	case *api.JSONPathPattern_Contains:
 		w.Contains = p.GetContains()
+	case *api.JSONPathPattern_Gt:
+		w.Gt = p.GetGt()
+	case *api.JSONPathPattern_Gte:
+		w.Gte = p.GetGte()
+	case *api.JSONPathPattern_Lt:
+		w.Lt = p.GetLt()
+	case *api.JSONPathPattern_Lte:
+		w.Lte = p.GetLte()
+	case *api.JSONPathPattern_Between:
+		w.Between = &Range{Min: p.GetBetween().GetMin(), Max: p.GetBetween().GetMax()}
+	case *api.JSONPathPattern_In:
+		w.In = make([]string, 0, len(p.GetIn().GetValues()))
+		for _, v := range p.GetIn().GetValues() {
+			w.In = append(w.In, listValueString(v))
+		}
+	case *api.JSONPathPattern_StartsWith:
+		w.StartsWith = p.GetStartsWith()
+	case *api.JSONPathPattern_EndsWith:
+		w.EndsWith = p.GetEndsWith()
+	case *api.JSONPathPattern_Absent:
+		absent := p.GetAbsent()
+		w.Absent = &absent
+	case *api.JSONPathPattern_IsNull:
+		isNull := p.GetIsNull()
+		w.IsNull = &isNull
 	default:
 		w.Partial = true
 	}
@@ -107,6 +452,63 @@ func jsonPathWrapper(p *api.JSONPathPattern) *JSONPathWrapper {
 	return w
 }
 
+func fieldPattern(p *api.FieldPattern) *FieldPattern {
+	f := &FieldPattern{Field: p.GetField()}
+
+	switch p.GetOperator().(type) {
+	case *api.FieldPattern_Eq:
+		f.Equals = p.GetEq()
+	case *api.FieldPattern_Gt:
+		f.Gt = p.GetGt()
+	case *api.FieldPattern_Gte:
+		f.Gte = p.GetGte()
+	case *api.FieldPattern_Lt:
+		f.Lt = p.GetLt()
+	case *api.FieldPattern_Lte:
+		f.Lte = p.GetLte()
+	case *api.FieldPattern_Between:
+		f.Between = &Range{Min: p.GetBetween().GetMin(), Max: p.GetBetween().GetMax()}
+	case *api.FieldPattern_In:
+		f.In = make([]string, 0, len(p.GetIn().GetValues()))
+		for _, v := range p.GetIn().GetValues() {
+			f.In = append(f.In, listValueString(v))
+		}
+	case *api.FieldPattern_Contains:
+		f.Contains = p.GetContains()
+	case *api.FieldPattern_Regexp:
+		f.Regexp = p.GetRegexp()
+	case *api.FieldPattern_StartsWith:
+		f.StartsWith = p.GetStartsWith()
+	case *api.FieldPattern_EndsWith:
+		f.EndsWith = p.GetEndsWith()
+	case *api.FieldPattern_IsSet:
+		isSet := p.GetIsSet()
+		f.IsSet = &isSet
+	}
+
+	return f
+}
+
+// listValueString converts values of the in operator to strings, so they are compared as eq values
+func listValueString(v *pstruct.Value) string {
+	switch kind := v.GetKind().(type) {
+	case *pstruct.Value_StringValue:
+		return kind.StringValue
+	case *pstruct.Value_NumberValue:
+		return strconv.FormatFloat(kind.NumberValue, 'f', -1, 64)
+	case *pstruct.Value_BoolValue:
+		return strconv.FormatBool(kind.BoolValue)
+	case *pstruct.Value_NullValue:
+		return "null"
+	}
+
+	js, err := protojson.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(js)
+}
+
 func structToBytes(msg *pstruct.Struct) ([]byte, error) {
 	if msg == nil {
 		return nil, nil
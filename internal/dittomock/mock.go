@@ -2,6 +2,10 @@ package dittomock
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc/codes"
 )
@@ -22,6 +26,8 @@ func (dr *DittoRequest) String() string {
 type DittoResponse struct {
 	Body   json.RawMessage
 	Status *RpcStatus
+	// Generator produces messages of server streaming response for every request
+	Generator *ResponseGenerator
 }
 
 type RpcStatus struct {
@@ -30,25 +36,150 @@ type RpcStatus struct {
 }
 
 type DittoMock struct {
+	// ID identifies the mock in diagnostics, it's generated if not provided
+	ID       string
 	Request  *DittoRequest
 	Response []*DittoResponse
+	// Source is set for mocks loaded from files
+	Source MockSource
+	// Stream controls the lifetime of server streams, it's optional
+	Stream *StreamOptions
+	// Responses are alternatives of Response chosen by weight, Response of the matched mock
+	// is set to the chosen alternative
+	Responses []WeightedResponse
+	// Delay is applied before the responses are sent, it's cut short by the client deadline
+	Delay time.Duration
+}
+
+// WeightedResponse is a response alternative chosen with probability weight/total weight
+type WeightedResponse struct {
+	Weight   uint32
+	Response []*DittoResponse
+}
+
+// StreamOptions keep server streams open after the responses are sent
+type StreamOptions struct {
+	KeepOpen bool
+	// Heartbeat is sent periodically while the stream is open, it's optional
+	Heartbeat *Heartbeat
 }
 
 type DittoBodyPattern struct {
 	EqualToJson     json.RawMessage  `json:"equalToJson,omitempty"`
 	MatchesJsonPath *JSONPathWrapper `json:"matchesJsonPath,omitempty"`
+	// MatchesCEL is CEL expression evaluated against the request message and metadata
+	MatchesCEL string `json:"matchesCel,omitempty"`
+	// MatchesField matches the request message field by its protobuf value
+	MatchesField *FieldPattern `json:"matchesField,omitempty"`
+	// AnyOf requires at least one of the patterns to match
+	AnyOf []DittoBodyPattern `json:"anyOf,omitempty"`
+	// AllOf requires all of the patterns to match
+	AllOf []DittoBodyPattern `json:"allOf,omitempty"`
+	// Not requires the pattern not to match
+	Not *DittoBodyPattern `json:"not,omitempty"`
+	// IgnoreExtraFields makes EqualToJson a subset comparison
+	IgnoreExtraFields bool `json:"ignoreExtraFields,omitempty"`
+	// IgnoreArrayOrder compares arrays in EqualToJson ignoring the order of the elements
+	IgnoreArrayOrder bool `json:"ignoreArrayOrder,omitempty"`
+	// AnyMessage requires at least one message of client streaming request to match the pattern
+	AnyMessage *DittoBodyPattern `json:"anyMessage,omitempty"`
+	// EveryMessage requires all messages of client streaming request to match the pattern
+	EveryMessage *DittoBodyPattern `json:"everyMessage,omitempty"`
+	// MessageCount limits the number of messages of client streaming request
+	MessageCount *MessageCount `json:"messageCount,omitempty"`
+	// Sequence matches messages of client streaming request in order
+	Sequence []SequenceStep `json:"sequence,omitempty"`
+	// Deadline matches the time left until the client deadline
+	Deadline *DeadlinePattern `json:"deadline,omitempty"`
+
+	// compiled parts of the pattern are set when the mock is added to the matcher
+	cel          *celProgram
+	compiledJSON *compiledJSON
+}
+
+// Empty returns true if the pattern doesn't have any conditions
+func (p DittoBodyPattern) Empty() bool {
+	return len(p.EqualToJson) == 0 && p.MatchesJsonPath == nil && p.MatchesCEL == "" && p.MatchesField == nil &&
+		len(p.AnyOf) == 0 && len(p.AllOf) == 0 && p.Not == nil &&
+		p.AnyMessage == nil && p.EveryMessage == nil && p.MessageCount == nil && len(p.Sequence) == 0 &&
+		p.Deadline == nil
 }
 
 type JSONPathMessage struct {
-	Expression string `json:"expression,omitempty"`
-	Contains   string `json:"contains,omitempty"`
-	Equals     string `json:"eq,omitempty"`
-	Regexp     string `json:"regexp,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+	Contains   string   `json:"contains,omitempty"`
+	Equals     string   `json:"eq,omitempty"`
+	Regexp     string   `json:"regexp,omitempty"`
+	Gt         string   `json:"gt,omitempty"`
+	Gte        string   `json:"gte,omitempty"`
+	Lt         string   `json:"lt,omitempty"`
+	Lte        string   `json:"lte,omitempty"`
+	Between    *Range   `json:"between,omitempty"`
+	In         []string `json:"in,omitempty"`
+	StartsWith string   `json:"startsWith,omitempty"`
+	EndsWith   string   `json:"endsWith,omitempty"`
+	// Absent can't match scalar fields of proto3 messages without presence,
+	// requests are rendered with default values so such fields are never absent
+	Absent *bool `json:"absent,omitempty"`
+	IsNull *bool `json:"isNull,omitempty"`
+}
+
+// Range is inclusive
+type Range struct {
+	Min string `json:"min"`
+	Max string `json:"max"`
 }
 
 type JSONPathWrapper struct {
 	JSONPathMessage
 	Partial bool `json:"-"`
+
+	// compiled is set when the mock is added to the matcher
+	compiled *compiledJSONPath
+}
+
+// describe returns human readable form of the operator
+func (w *JSONPathWrapper) describe() string {
+	switch {
+	case w.Partial:
+		return "any value"
+	case w.Contains != "":
+		return fmt.Sprintf("contains %q", w.Contains)
+	case w.Regexp != "":
+		return fmt.Sprintf("regexp %q", w.Regexp)
+	case w.Absent != nil:
+		if *w.Absent {
+			return "absent"
+		}
+		return "present"
+	case w.IsNull != nil:
+		if *w.IsNull {
+			return "null"
+		}
+		return "not null"
+	case w.Between != nil:
+		return fmt.Sprintf("between %q and %q", w.Between.Min, w.Between.Max)
+	case w.In != nil:
+		values := make([]string, 0, len(w.In))
+		for _, v := range w.In {
+			values = append(values, strconv.Quote(v))
+		}
+		return fmt.Sprintf("in [%s]", strings.Join(values, ", "))
+	case w.StartsWith != "":
+		return fmt.Sprintf("starts with %q", w.StartsWith)
+	case w.EndsWith != "":
+		return fmt.Sprintf("ends with %q", w.EndsWith)
+	case w.Gt != "":
+		return fmt.Sprintf("gt %q", w.Gt)
+	case w.Gte != "":
+		return fmt.Sprintf("gte %q", w.Gte)
+	case w.Lt != "":
+		return fmt.Sprintf("lt %q", w.Lt)
+	case w.Lte != "":
+		return fmt.Sprintf("lte %q", w.Lte)
+	default:
+		return fmt.Sprintf("eq %q", w.Equals)
+	}
 }
 
 func (w *JSONPathWrapper) UnmarshalJSON(data []byte) error {
@@ -61,3 +192,143 @@ func (w *JSONPathWrapper) UnmarshalJSON(data []byte) error {
 	}
 	return json.Unmarshal(data, m)
 }
+
+// MessageCount is inclusive range of the number of messages, Max is unlimited if it's nil
+type MessageCount struct {
+	Min uint32  `json:"min,omitempty"`
+	Max *uint32 `json:"max,omitempty"`
+}
+
+func (c *MessageCount) contains(n int) bool {
+	return n >= int(c.Min) && (c.Max == nil || n <= int(*c.Max))
+}
+
+func (c *MessageCount) describe() string {
+	switch {
+	case c.Max == nil:
+		return fmt.Sprintf("at least %d messages", c.Min)
+	case c.Min == *c.Max:
+		return fmt.Sprintf("%d messages", c.Min)
+	default:
+		return fmt.Sprintf("%d to %d messages", c.Min, *c.Max)
+	}
+}
+
+// SequenceStep matches a single message of the stream or skips any number of messages,
+// only one of the fields is expected to be set
+type SequenceStep struct {
+	// Message is the pattern the next message must match
+	Message *DittoBodyPattern `json:"message,omitempty"`
+	// Any matches exactly one message of any content
+	Any bool `json:"any,omitempty"`
+	// Skip matches zero or more messages of any content
+	Skip bool `json:"skip,omitempty"`
+}
+
+func (s SequenceStep) describe() string {
+	switch {
+	case s.Skip:
+		return "skip"
+	case s.Any:
+		return "any"
+	default:
+		return "message"
+	}
+}
+
+// DeadlinePattern matches the time left until the client deadline, zero bounds are not checked
+type DeadlinePattern struct {
+	// IsSet requires the client to set or not to set the deadline, bounds imply it's set
+	IsSet *bool         `json:"isSet,omitempty"`
+	Lt    time.Duration `json:"lt,omitempty"`
+	Gt    time.Duration `json:"gt,omitempty"`
+}
+
+// matches checks the time left, ok is false if the client didn't set the deadline
+func (d *DeadlinePattern) matches(left time.Duration, ok bool) bool {
+	if d.IsSet != nil && *d.IsSet != ok {
+		return false
+	}
+
+	if d.Lt == 0 && d.Gt == 0 {
+		return true
+	}
+
+	return ok && (d.Lt == 0 || left < d.Lt) && (d.Gt == 0 || left > d.Gt)
+}
+
+func (d *DeadlinePattern) describe() string {
+	var conds []string
+	if d.IsSet != nil && d.Lt == 0 && d.Gt == 0 {
+		if *d.IsSet {
+			return "set"
+		}
+		return "not set"
+	}
+	if d.Gt > 0 {
+		conds = append(conds, fmt.Sprintf("gt %s", d.Gt))
+	}
+	if d.Lt > 0 {
+		conds = append(conds, fmt.Sprintf("lt %s", d.Lt))
+	}
+	return strings.Join(conds, " and ")
+}
+
+// FieldPattern matches the field of the request message by its protobuf value,
+// only one of the operators is expected to be set
+type FieldPattern struct {
+	// Field is the path of original field names like items[0].price
+	Field      string   `json:"field"`
+	Equals     string   `json:"eq,omitempty"`
+	Gt         string   `json:"gt,omitempty"`
+	Gte        string   `json:"gte,omitempty"`
+	Lt         string   `json:"lt,omitempty"`
+	Lte        string   `json:"lte,omitempty"`
+	Between    *Range   `json:"between,omitempty"`
+	In         []string `json:"in,omitempty"`
+	Contains   string   `json:"contains,omitempty"`
+	Regexp     string   `json:"regexp,omitempty"`
+	StartsWith string   `json:"startsWith,omitempty"`
+	EndsWith   string   `json:"endsWith,omitempty"`
+	IsSet      *bool    `json:"isSet,omitempty"`
+
+	// compiled is set when the mock is added to the matcher
+	compiled *compiledField
+}
+
+// describe returns human readable form of the operator
+func (f *FieldPattern) describe() string {
+	switch {
+	case f.IsSet != nil:
+		if *f.IsSet {
+			return "set"
+		}
+		return "not set"
+	case f.Between != nil:
+		return fmt.Sprintf("between %q and %q", f.Between.Min, f.Between.Max)
+	case f.In != nil:
+		values := make([]string, 0, len(f.In))
+		for _, v := range f.In {
+			values = append(values, strconv.Quote(v))
+		}
+		return fmt.Sprintf("in [%s]", strings.Join(values, ", "))
+	case f.Contains != "":
+		return fmt.Sprintf("contains %q", f.Contains)
+	case f.Regexp != "":
+		return fmt.Sprintf("regexp %q", f.Regexp)
+	case f.StartsWith != "":
+		return fmt.Sprintf("starts with %q", f.StartsWith)
+	case f.EndsWith != "":
+		return fmt.Sprintf("ends with %q", f.EndsWith)
+	case f.Gt != "":
+		return fmt.Sprintf("gt %q", f.Gt)
+	case f.Gte != "":
+		return fmt.Sprintf("gte %q", f.Gte)
+	case f.Lt != "":
+		return fmt.Sprintf("lt %q", f.Lt)
+	case f.Lte != "":
+		return fmt.Sprintf("lte %q", f.Lte)
+	default:
+		return fmt.Sprintf("eq %q", f.Equals)
+	}
+}
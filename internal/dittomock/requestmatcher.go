@@ -1,23 +1,21 @@
 package dittomock
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"math/rand"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/vadimi/grpc-ditto/api"
 	"github.com/vadimi/grpc-ditto/internal/logger"
-	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/jhump/protoreflect/desc"
 	"github.com/spyzhov/ajson"
+	"google.golang.org/grpc/metadata"
 	"sigs.k8s.io/yaml"
 )
 
@@ -26,8 +24,13 @@ var ErrNotMatched = errors.New("dittomock: request not matched")
 type RequestMatherOption func(*RequestMatcher)
 
 func WithMocksPath(mocksPath string) RequestMatherOption {
+	return WithMockSources(MockSources{Paths: []string{mocksPath}})
+}
+
+// WithMockSources loads mocks from multiple sources, mocks of the later sources take precedence
+func WithMockSources(sources MockSources) RequestMatherOption {
 	return func(rm *RequestMatcher) {
-		rm.mocksPath = mocksPath
+		rm.sources = sources
 	}
 }
 
@@ -37,6 +40,13 @@ func WithLogger(l logger.Logger) RequestMatherOption {
 	}
 }
 
+// WithMethodResolver provides method descriptors used to compile matches_cel and matches_field patterns
+func WithMethodResolver(resolve func(method string) *desc.MethodDescriptor) RequestMatherOption {
+	return func(rm *RequestMatcher) {
+		rm.resolveMethod = resolve
+	}
+}
+
 func WithMocks(mocks []DittoMock) RequestMatherOption {
 	return func(rm *RequestMatcher) {
 		rules := map[string][]DittoMock{}
@@ -45,24 +55,84 @@ func WithMocks(mocks []DittoMock) RequestMatherOption {
 	}
 }
 
+// WithRandSeed makes the choice of weighted responses reproducible
+func WithRandSeed(seed int64) RequestMatherOption {
+	return func(rm *RequestMatcher) {
+		rm.rnd = rand.New(rand.NewSource(seed))
+	}
+}
+
 type RequestMatcher struct {
-	rules     map[string][]DittoMock
-	logger    logger.Logger
-	mocksPath string
-	rw        sync.RWMutex
+	rules         map[string][]DittoMock
+	logger        logger.Logger
+	sources       MockSources
+	resolveMethod func(method string) *desc.MethodDescriptor
+	rw            sync.RWMutex
+	// rnd chooses weighted responses, it's guarded by rndMu
+	rnd   *rand.Rand
+	rndMu sync.Mutex
+}
+
+// MatchOption provides additional request data
+type MatchOption func(*matchRequest)
+
+// WithMetadata makes incoming metadata available to matches_cel expressions
+func WithMetadata(md metadata.MD) MatchOption {
+	return func(r *matchRequest) {
+		r.metadata = md
+	}
+}
+
+// WithDeadline makes the client deadline available to deadline patterns, ok is false if it's not set,
+// the time left is calculated when the request is created
+func WithDeadline(deadline time.Time, ok bool) MatchOption {
+	return func(r *matchRequest) {
+		r.hasDeadline = ok
+		if ok {
+			r.timeLeft = time.Until(deadline)
+		}
+	}
+}
+
+// matchRequest is the request being matched
+type matchRequest struct {
+	json     []byte
+	metadata metadata.MD
+	// timeLeft until the client deadline, it's only valid if hasDeadline is true
+	timeLeft    time.Duration
+	hasDeadline bool
+
+	// parsed forms of the request are created on the first use
+	root     *ajson.Node
+	rootErr  error
+	value    *compiledJSON
+	celValue interface{}
+	celErr   error
+	// messages of client streaming request
+	items    []*matchRequest
+	itemsErr error
 }
 
-func (rm *RequestMatcher) Match(method string, js []byte) (*DittoMock, error) {
+func newMatchRequest(js []byte, opts ...MatchOption) *matchRequest {
+	req := &matchRequest{json: js}
+	for _, opt := range opts {
+		opt(req)
+	}
+	return req
+}
+
+func (rm *RequestMatcher) Match(method string, js []byte, opts ...MatchOption) (*DittoMock, error) {
 	rm.rw.RLock()
 	defer rm.rw.RUnlock()
 
 	mocks, ok := rm.rules[method]
 	if !ok {
-		return nil, ErrNotMatched
+		return nil, &NotMatchedError{Method: method}
 	}
 
+	req := newMatchRequest(js, opts...)
 	for _, mock := range mocks {
-		res, err := rm.matches(js, mock.Request)
+		res, err := rm.matches(req, mock.Request)
 		if err != nil {
 			rm.logger.Warnw("matching error", "err", err)
 			continue
@@ -70,11 +140,38 @@ func (rm *RequestMatcher) Match(method string, js []byte) (*DittoMock, error) {
 
 		if res {
 			rm.logger.Debugw("match found", "expr", mock.Request.String())
+			if len(mock.Responses) > 0 {
+				mock.Response = rm.pickResponse(mock.Responses)
+			}
 			return &mock, nil
 		}
 	}
 
-	return nil, ErrNotMatched
+	return nil, &NotMatchedError{Method: method, Candidates: closestMocks(req, mocks)}
+}
+
+// pickResponse chooses the alternative with probability proportional to its weight
+func (rm *RequestMatcher) pickResponse(responses []WeightedResponse) []*DittoResponse {
+	var total int64
+	for _, r := range responses {
+		total += int64(r.Weight)
+	}
+	if total == 0 {
+		return responses[0].Response
+	}
+
+	rm.rndMu.Lock()
+	n := rm.rnd.Int63n(total)
+	rm.rndMu.Unlock()
+
+	for _, r := range responses {
+		if n < int64(r.Weight) {
+			return r.Response
+		}
+		n -= int64(r.Weight)
+	}
+
+	return responses[len(responses)-1].Response
 }
 
 func NewRequestMatcher(opts ...RequestMatherOption) (*RequestMatcher, error) {
@@ -90,46 +187,69 @@ func NewRequestMatcher(opts ...RequestMatherOption) (*RequestMatcher, error) {
 		matcher.logger = logger.NewLogger()
 	}
 
-	if matcher.mocksPath != "" {
-		err := filepath.Walk(matcher.mocksPath, func(path string, info os.FileInfo, err error) error {
+	if matcher.rnd == nil {
+		matcher.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	if len(matcher.sources.Paths) > 0 {
+		bySource := make([][]DittoMock, len(matcher.sources.Paths))
+		err := matcher.sources.walk(func(source int, path string, js []byte, err error) error {
 			if err != nil {
 				return err
 			}
-			ext := strings.ToLower(filepath.Ext(path))
-			var loadMockFn func(io.Reader) ([]DittoMock, error)
-			switch ext {
-			case ".json":
-				loadMockFn = matcher.loadMockJSON
-			case ".yaml", ".yml":
-				loadMockFn = matcher.loadMockYAML
-			}
 
-			if loadMockFn != nil {
-				matcher.logger.Debugw("load mock file", "file", path)
-				f, err := os.Open(path)
-				defer f.Close()
-				if err != nil {
-					return err
-				}
-
-				mocks, err := loadMockFn(f)
-				if err != nil {
-					return err
-				}
-				matcher.logger.Debugw("merging mocks", "file", path, "count", len(mocks))
-				mergeMocks(mocks, matcher.rules)
+			matcher.logger.Debugw("load mock file", "file", path)
+			mocks, errs := parseMocks(js, path)
+			if len(errs) > 0 {
+				return errs[0]
 			}
+			matcher.logger.Debugw("merging mocks", "file", path, "count", len(mocks))
+			bySource[source] = append(bySource[source], mocks...)
 
 			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
+
+		mergeMocks(flattenSources(bySource), matcher.rules)
+	}
+
+	var errs []error
+	for _, mocks := range matcher.rules {
+		for _, mock := range mocks {
+			if err := matcher.compile(mock); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
 	return matcher, nil
 }
 
+// compile prepares patterns of the mock, it fails if any of the patterns is invalid
+func (rm *RequestMatcher) compile(mock DittoMock) error {
+	if mock.Request == nil {
+		return nil
+	}
+
+	var method *desc.MethodDescriptor
+	if rm.resolveMethod != nil {
+		method = rm.resolveMethod(mock.Request.Method)
+	}
+
+	stream := method != nil && method.IsClientStreaming()
+	var errs []error
+	for _, err := range compilePatterns(mock.Request.BodyPatterns, method, stream) {
+		errs = append(errs, &MockError{Source: mock.Source, Err: err})
+	}
+
+	return errors.Join(errs...)
+}
+
 func (rm *RequestMatcher) Clear() {
 	rm.rw.Lock()
 	defer rm.rw.Unlock()
@@ -137,11 +257,17 @@ func (rm *RequestMatcher) Clear() {
 	rm.rules = map[string][]DittoMock{}
 }
 
-func (rm *RequestMatcher) AddMock(mock DittoMock) {
+// AddMock adds the mock after its patterns are compiled, invalid mocks are not added
+func (rm *RequestMatcher) AddMock(mock DittoMock) error {
 	rm.rw.Lock()
 	defer rm.rw.Unlock()
 
+	if err := rm.compile(mock); err != nil {
+		return err
+	}
+
 	mergeMocks([]DittoMock{mock}, rm.rules)
+	return nil
 }
 
 func (rm *RequestMatcher) Mocks() map[string][]DittoMock {
@@ -173,61 +299,129 @@ func (rm *RequestMatcher) loadMockJSON(mockJson io.Reader) ([]DittoMock, error)
 }
 
 func (rm *RequestMatcher) loadMock(js []byte) ([]DittoMock, error) {
-	mocks := []DittoMock{}
-	msgs := []json.RawMessage{}
-	err := json.Unmarshal(js, &msgs)
-	if err != nil {
-		return mocks, err
+	mocks, errs := parseMocks(js, "")
+	if len(errs) > 0 {
+		return mocks, errs[0]
 	}
 
-	for _, msg := range msgs {
-		m := &api.DittoMock{}
-		err := protojson.Unmarshal(msg, m)
-		if err != nil {
-			return mocks, err
+	return mocks, nil
+}
+
+func (rm *RequestMatcher) matches(r *matchRequest, req *DittoRequest) (bool, error) {
+	result := false
+	for _, pattern := range req.BodyPatterns {
+		if pattern.Empty() {
+			continue
 		}
 
-		dm, err := FromProto(m)
-		if err != nil {
-			return mocks, err
+		val, err := matchPattern(r, pattern)
+		if err != nil || !val {
+			return false, err
 		}
-		mocks = append(mocks, dm)
+
+		result = true
 	}
 
-	return mocks, nil
+	return result, nil
 }
 
-func (rm *RequestMatcher) matches(json []byte, req *DittoRequest) (bool, error) {
-	result := false
-	for _, pattern := range req.BodyPatterns {
-		if len(pattern.EqualToJson) > 0 {
-			val, err := jsonMatcher(json, pattern.EqualToJson)
-			if err != nil || !val {
-				return false, err
-			}
+// matchPattern returns true if all matchers defined in the pattern match the request
+func matchPattern(req *matchRequest, pattern DittoBodyPattern) (bool, error) {
+	if len(pattern.EqualToJson) > 0 {
+		val, err := equalToJSONMatcher(req, pattern)
+		if err != nil || !val {
+			return false, err
+		}
+	}
 
-			result = true
+	if pattern.MatchesJsonPath != nil {
+		val, err := jsonPathMatcher(req, pattern.MatchesJsonPath)
+		if err != nil || !val {
+			return false, err
 		}
-		if pattern.MatchesJsonPath != nil {
-			val, err := jsonPathMatcher(json, pattern.MatchesJsonPath)
-			if err != nil || !val {
-				return false, err
-			}
+	}
 
-			result = true
+	if pattern.MatchesCEL != "" {
+		val, err := celMatcher(req, pattern)
+		if err != nil || !val {
+			return false, err
 		}
+	}
 
+	if pattern.MatchesField != nil {
+		val, err := fieldMatcher(req, pattern.MatchesField)
+		if err != nil || !val {
+			return false, err
+		}
 	}
 
-	return result, nil
+	for _, sub := range pattern.AllOf {
+		val, err := matchPattern(req, sub)
+		if err != nil || !val {
+			return false, err
+		}
+	}
+
+	if len(pattern.AnyOf) > 0 {
+		val, err := matchAny(req, pattern.AnyOf)
+		if err != nil || !val {
+			return false, err
+		}
+	}
+
+	if pattern.Not != nil {
+		val, err := matchPattern(req, *pattern.Not)
+		if err != nil || val {
+			return false, err
+		}
+	}
+
+	if pattern.hasMessagePatterns() {
+		val, err := matchMessages(req, pattern)
+		if err != nil || !val {
+			return false, err
+		}
+	}
+
+	if pattern.Deadline != nil && !pattern.Deadline.matches(req.timeLeft, req.hasDeadline) {
+		return false, nil
+	}
+
+	return true, nil
 }
 
-func jsonPathMatcher(jsonSrc []byte, pattern *JSONPathWrapper) (bool, error) {
-	nodes, err := ajson.JSONPath(jsonSrc, pattern.Expression)
+// matchAny returns true if at least one of the patterns matches,
+// errors are returned only if none of the patterns match
+func matchAny(req *matchRequest, patterns []DittoBodyPattern) (bool, error) {
+	var firstErr error
+	for _, p := range patterns {
+		val, err := matchPattern(req, p)
+		if err == nil && val {
+			return true, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return false, firstErr
+}
+
+func jsonPathMatcher(req *matchRequest, pattern *JSONPathWrapper) (bool, error) {
+	prg := pattern.program()
+	if prg.err != nil {
+		return false, prg.err
+	}
+
+	nodes, err := jsonPathNodes(req, prg)
 	if err != nil {
 		return false, fmt.Errorf("jsonpath matching: %w, expr: %s", err, pattern.Expression)
 	}
 
+	if pattern.Absent != nil {
+		return (len(nodes) == 0) == *pattern.Absent, nil
+	}
+
 	if len(nodes) == 0 {
 		return false, nil
 	}
@@ -240,60 +434,38 @@ func jsonPathMatcher(jsonSrc []byte, pattern *JSONPathWrapper) (bool, error) {
 		return strings.Contains(nodes[0].String(), pattern.Contains), nil
 	}
 
-	patternVal := pattern.Regexp
-	isRegexp := true
-	if patternVal == "" {
-		patternVal = pattern.Equals
-		isRegexp = false
+	if pattern.Regexp != "" {
+		return regexpMatcher(prg.re, nodes), nil
 	}
-	result := false
 
-	if isRegexp {
-		return regexpMatcher(patternVal, nodes)
+	match := pattern.nodeMatcher(prg)
+	if match == nil {
+		match = func(node *ajson.Node) (bool, error) {
+			return equalNode(node, pattern.Equals, prg.equals)
+		}
 	}
 
 	for _, node := range nodes {
-		switch node.Type() {
-		case ajson.String:
-			strVal, _ := node.GetString()
-			result = strings.EqualFold(strVal, patternVal)
-		case ajson.Numeric:
-			n, _ := node.GetNumeric()
-			floatVal, err := strconv.ParseFloat(patternVal, 64)
-			if err != nil {
-				return result, err
-			}
-			result = (n == floatVal)
-		case ajson.Bool:
-			pbVal, err := strconv.ParseBool(patternVal)
-			if err != nil {
-				return result, err
-			}
-			bVal, _ := node.GetBool()
-			result = pbVal == bVal
-		case ajson.Object, ajson.Array:
-			result, err = jsonMatcher([]byte(node.String()), []byte(patternVal))
-			if err != nil {
-				return false, err
-			}
-		default:
-			result = false
-		}
-
-		if !result {
-			break
+		result, err := match(node)
+		if err != nil || !result {
+			return false, err
 		}
 	}
 
-	return result, nil
+	return true, nil
 }
 
-func regexpMatcher(regexpExpr string, nodes []*ajson.Node) (bool, error) {
-	result := false
-	re, err := regexp.Compile(regexpExpr)
+func jsonPathNodes(req *matchRequest, prg *compiledJSONPath) ([]*ajson.Node, error) {
+	root, err := req.jsonRoot()
 	if err != nil {
-		return result, err
+		return nil, err
 	}
+
+	return ajson.ApplyJSONPath(root, prg.commands)
+}
+
+func regexpMatcher(re *regexp.Regexp, nodes []*ajson.Node) bool {
+	result := false
 	for _, node := range nodes {
 		strVal := ""
 		switch node.Type() {
@@ -309,21 +481,7 @@ func regexpMatcher(regexpExpr string, nodes []*ajson.Node) (bool, error) {
 		continue
 	}
 
-	return result, nil
-}
-
-func jsonMatcher(jsonVal []byte, expetedJson json.RawMessage) (bool, error) {
-	src, err := canonicalJSON(jsonVal)
-	if err != nil {
-		return false, err
-	}
-
-	expected, err := canonicalJSON(expetedJson)
-	if err != nil {
-		return false, err
-	}
-
-	return bytes.Equal(src, expected), nil
+	return result
 }
 
 func mergeMocks(mocks []DittoMock, group map[string][]DittoMock) {
@@ -332,11 +490,23 @@ func mergeMocks(mocks []DittoMock, group map[string][]DittoMock) {
 		if !ok {
 			methodMocks = []DittoMock{}
 		}
+		if m.ID == "" {
+			m.ID = generateMockID(m, len(methodMocks))
+		}
 		methodMocks = append(methodMocks, m)
 		group[m.Request.Method] = methodMocks
 	}
 }
 
+// generateMockID uses mock file and position for mocks loaded from files
+// and method with mock index otherwise
+func generateMockID(m DittoMock, index int) string {
+	if m.Source.File != "" {
+		return m.Source.String()
+	}
+	return fmt.Sprintf("%s#%d", m.Request.Method, index)
+}
+
 func canonicalJSON(src []byte) ([]byte, error) {
 	var val interface{}
 	err := json.Unmarshal(src, &val)
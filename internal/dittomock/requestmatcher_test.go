@@ -2,9 +2,14 @@ package dittomock
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -399,3 +404,524 @@ func TestMockLoaderJSON_ResponseBodyTemplate(t *testing.T) {
 	_, err = time.Parse(time.RFC3339, body["message"])
 	require.NoError(t, err)
 }
+
+func TestParseMocksReportsAllErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := `[{"request": {"method": "/greet.Greeter/SayHello"}, "response": [{"body": {"message": "ok"}}]}]`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(valid), 0o644))
+
+	invalid := `---
+- request:
+    method: "/greet.Greeter/SayHello"
+  response:
+    - status:
+        code: NOTFOUND
+- request:
+    method: "/greet.Greeter/SayHello"
+  response:
+    - body:
+        message: ok
+- requets:
+    method: "/greet.Greeter/SayHello"
+  response:
+    - body:
+        message: ok
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(invalid), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.json"), []byte("[{"), 0o644))
+
+	mocks, errs := ParseMocks(dir)
+	require.Len(t, mocks, 2)
+	assert.Equal(t, MockSource{File: filepath.Join(dir, "b.yaml"), Index: 1}, mocks[1].Source)
+
+	require.Len(t, errs, 3)
+	var mockErr *MockError
+	require.ErrorAs(t, errs[0], &mockErr)
+	assert.Equal(t, MockSource{File: filepath.Join(dir, "b.yaml"), Index: 0}, mockErr.Source)
+	require.ErrorAs(t, errs[1], &mockErr)
+	assert.Equal(t, 2, mockErr.Source.Index)
+	require.ErrorAs(t, errs[2], &mockErr)
+	assert.Equal(t, -1, mockErr.Source.Index)
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	s := strings.Repeat("a", maxValueLen-1) + "привет"
+	truncated := truncate(s)
+	assert.True(t, utf8.ValidString(truncated))
+	assert.Equal(t, strings.Repeat("a", maxValueLen-1)+"...", truncated)
+	assert.Equal(t, "short", truncate("short"))
+}
+
+func TestNotMatchedDiagnostics(t *testing.T) {
+	mocks := []DittoMock{
+		{
+			ID: "bob",
+			Request: &DittoRequest{
+				Method: "test",
+				BodyPatterns: []DittoBodyPattern{
+					{
+						MatchesJsonPath: &JSONPathWrapper{
+							JSONPathMessage: JSONPathMessage{Expression: "$.name", Equals: "Bob"},
+						},
+					},
+					{
+						MatchesJsonPath: &JSONPathWrapper{
+							JSONPathMessage: JSONPathMessage{Expression: "$.age", Equals: "30"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Request: &DittoRequest{
+				Method: "test",
+				BodyPatterns: []DittoBodyPattern{
+					{EqualToJson: []byte(`{"name": "Bobby", "age": 31}`)},
+				},
+			},
+		},
+	}
+
+	rm, _ := NewRequestMatcher(WithMocks(mocks))
+	_, err := rm.Match("test", []byte(`{"name": "Bobby", "age": 30}`))
+	require.ErrorIs(t, err, ErrNotMatched)
+
+	var notMatched *NotMatchedError
+	require.ErrorAs(t, err, &notMatched)
+	require.Len(t, notMatched.Candidates, 2)
+
+	best := notMatched.Candidates[0]
+	assert.Equal(t, "bob", best.MockID)
+	assert.Equal(t, 1, best.Matched)
+	assert.Equal(t, 2, best.Total)
+	require.Len(t, best.Mismatches, 1)
+	assert.Equal(t, PatternMismatch{Index: 0, Path: "$.name", Expected: `eq "Bob"`, Actual: `"Bobby"`}, best.Mismatches[0])
+	assert.Contains(t, notMatched.Summary(), "closest mock bob")
+
+	other := notMatched.Candidates[1]
+	assert.Equal(t, "test#1", other.MockID)
+	require.Len(t, other.Mismatches, 1)
+	assert.Equal(t, PatternMismatch{Index: 0, Path: "$.age", Expected: "31", Actual: "30"}, other.Mismatches[0])
+
+	_, err = rm.Match("unknown", []byte(`{}`))
+	require.ErrorAs(t, err, &notMatched)
+	assert.Empty(t, notMatched.Candidates)
+}
+
+func TestJournalKeepsRecentEntries(t *testing.T) {
+	j := NewJournal[JournalEntry](2)
+	j.Record(JournalEntry{Method: "1"})
+	j.Record(JournalEntry{Method: "2"})
+	j.Record(JournalEntry{Method: "3"})
+
+	entries := j.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "2", entries[0].Method)
+	assert.Equal(t, "3", entries[1].Method)
+}
+
+func TestBodyPatternCombinators(t *testing.T) {
+	js := `---
+- request:
+    method: "/greet.Greeter/SayHello"
+    body_patterns:
+    - any_of:
+      - matches_jsonpath: { expression: "$.name", eq: Bob }
+      - matches_jsonpath: { expression: "$.name", eq: Alice }
+      not:
+        all_of:
+        - matches_jsonpath: { expression: "$.premium", eq: "true" }
+        - matches_jsonpath: { expression: "$.age", eq: "30" }
+  response:
+  - body:
+      message: ok
+`
+	rm, err := NewRequestMatcher()
+	require.NoError(t, err)
+
+	mocks, err := rm.loadMockYAML(strings.NewReader(js))
+	require.NoError(t, err)
+	require.Len(t, mocks, 1)
+	pattern := mocks[0].Request.BodyPatterns[0]
+	require.Len(t, pattern.AnyOf, 2)
+	require.NotNil(t, pattern.Not)
+	require.Len(t, pattern.Not.AllOf, 2)
+
+	rm, err = NewRequestMatcher(WithMocks(mocks))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		json    string
+		matched bool
+	}{
+		{"FirstAlternative", `{"name": "Bob", "premium": false, "age": 30}`, true},
+		{"SecondAlternative", `{"name": "Alice", "premium": true, "age": 25}`, true},
+		{"NoAlternative", `{"name": "John", "premium": false, "age": 30}`, false},
+		{"Negated", `{"name": "Bob", "premium": true, "age": 30}`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rm.Match("/greet.Greeter/SayHello", []byte(test.json))
+			if test.matched {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNotMatched)
+			}
+		})
+	}
+}
+
+func TestBodyPatternCombinatorsDiagnostics(t *testing.T) {
+	bob := DittoBodyPattern{
+		MatchesJsonPath: &JSONPathWrapper{JSONPathMessage: JSONPathMessage{Expression: "$.name", Equals: "Bob"}},
+	}
+	alice := DittoBodyPattern{
+		MatchesJsonPath: &JSONPathWrapper{JSONPathMessage: JSONPathMessage{Expression: "$.name", Equals: "Alice"}},
+	}
+
+	js := []byte(`{"name": "John"}`)
+
+	m := describeMismatch(newMatchRequest(js), DittoBodyPattern{AnyOf: []DittoBodyPattern{bob, alice}})
+	assert.Contains(t, m.Reason, `any_of[0] $.name: expected eq "Bob", got "John"`)
+	assert.Contains(t, m.Reason, `any_of[1] $.name: expected eq "Alice", got "John"`)
+
+	m = describeMismatch(newMatchRequest(js), DittoBodyPattern{AllOf: []DittoBodyPattern{{Not: &alice}, bob}})
+	assert.Equal(t, PatternMismatch{Path: `all_of[1] $.name`, Expected: `eq "Bob"`, Actual: `"John"`}, m)
+
+	m = describeMismatch(newMatchRequest([]byte(`{"name": "Bob"}`)), DittoBodyPattern{Not: &bob})
+	assert.Equal(t, "not pattern matched", m.Reason)
+}
+
+func TestJSONPathComparisonOperators(t *testing.T) {
+	js := []byte(`{
+  "name": "Bob",
+  "amount": 1500,
+  "balance": "9007199254740993",
+  "status": "ACTIVE",
+  "active": true,
+  "parent": null,
+  "created_at": "2024-01-15T10:00:00Z",
+  "tags": [5, 10, 15]
+}`)
+
+	tests := []struct {
+		pattern string
+		matched bool
+	}{
+		{`{ "expression": "$.amount", "gt": "1000" }`, true},
+		{`{ "expression": "$.amount", "gt": "1500" }`, false},
+		{`{ "expression": "$.amount", "gte": "1500" }`, true},
+		{`{ "expression": "$.amount", "lt": "1500" }`, false},
+		{`{ "expression": "$.amount", "lte": "1500.5" }`, true},
+		{`{ "expression": "$.balance", "gt": "9007199254740000" }`, true},
+		{`{ "expression": "$.balance", "lt": "10000" }`, false},
+		{`{ "expression": "$.created_at", "gt": "2024-01-15T09:00:00-02:00" }`, false},
+		{`{ "expression": "$.created_at", "between": { "min": "2024-01-01T00:00:00Z", "max": "2024-02-01T00:00:00Z" } }`, true},
+		{`{ "expression": "$.amount", "between": { "min": "1500", "max": "2000" } }`, true},
+		{`{ "expression": "$.amount", "between": { "min": "1501", "max": "2000" } }`, false},
+		{`{ "expression": "$.tags[*]", "gte": "5" }`, true},
+		{`{ "expression": "$.tags[*]", "gt": "5" }`, false},
+		{`{ "expression": "$.name", "gt": "Alice" }`, true},
+		{`{ "expression": "$.status", "in": ["PENDING", "ACTIVE"] }`, true},
+		{`{ "expression": "$.status", "in": ["PENDING", "CLOSED"] }`, false},
+		{`{ "expression": "$.amount", "in": [1000, 1500] }`, true},
+		{`{ "expression": "$.active", "in": [true] }`, true},
+		{`{ "expression": "$.status", "starts_with": "ACT" }`, true},
+		{`{ "expression": "$.status", "ends_with": "ACT" }`, false},
+		{`{ "expression": "$.missing", "absent": true }`, true},
+		{`{ "expression": "$.name", "absent": true }`, false},
+		{`{ "expression": "$.name", "absent": false }`, true},
+		{`{ "expression": "$.parent", "is_null": true }`, true},
+		{`{ "expression": "$.name", "is_null": true }`, false},
+		{`{ "expression": "$.name", "is_null": false }`, true},
+		{`{ "expression": "$.amount", "gt": "abc" }`, false},
+		{`{ "expression": "$.name", "gt": "1000" }`, false},
+		{`{ "expression": "$.balance", "gt": "abc" }`, false},
+		{`{ "expression": "$.created_at", "gt": "1000" }`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			mockJS := `[{ "request": { "method": "test", "body_patterns": [{ "matches_jsonpath": ` + test.pattern + ` }] } }]`
+			mocks, errs := parseMocks([]byte(mockJS), "")
+			require.Empty(t, errs)
+
+			matched, _ := matchPattern(newMatchRequest(js), mocks[0].Request.BodyPatterns[0])
+			assert.Equal(t, test.matched, matched)
+		})
+	}
+}
+
+func TestIncludesJSONMatching(t *testing.T) {
+	js := []byte(`{
+  "name": "Bob",
+  "age": 30,
+  "address": { "city": "Paris", "zip": "75001" },
+  "tags": ["a", "b", "c"],
+  "items": [{ "id": 1, "qty": 2 }, { "id": 2, "qty": 1 }]
+}`)
+
+	tests := []struct {
+		name    string
+		pattern string
+		matched bool
+	}{
+		{"Includes", `{ "includes_json": { "name": "Bob" } }`, true},
+		{"IncludesNested", `{ "includes_json": { "address": { "city": "Paris" } } }`, true},
+		{"IncludesMismatch", `{ "includes_json": { "address": { "city": "London" } } }`, false},
+		{"IncludesMissingField", `{ "includes_json": { "email": "" } }`, false},
+		{"IncludesArrayOrder", `{ "includes_json": { "tags": ["c", "b", "a"] } }`, false},
+		{"IncludesArrayLength", `{ "includes_json": { "tags": ["a", "b"] } }`, false},
+		{"IncludesArrayElements", `{ "includes_json": { "items": [{ "id": 1 }, { "id": 2 }] } }`, true},
+		{"IgnoreArrayOrder", `{ "includes_json": { "tags": ["c", "b", "a"] }, "ignore_array_order": true }`, true},
+		{"IgnoreArrayOrderElements", `{ "includes_json": { "items": [{ "id": 2 }, { "id": 1 }] }, "ignore_array_order": true }`, true},
+		{"IgnoreArrayOrderDuplicates", `{ "includes_json": { "items": [{ "id": 1 }, { "id": 1 }] }, "ignore_array_order": true }`, false},
+		{"EqualIgnoreExtraFields", `{ "equal_to_json": { "name": "Bob", "age": 30 }, "ignore_extra_fields": true }`, true},
+		{"EqualIgnoreArrayOrderOnly", `{ "equal_to_json": { "tags": ["c", "b", "a"] }, "ignore_array_order": true }`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockJS := `[{ "request": { "method": "test", "body_patterns": [` + test.pattern + `] } }]`
+			mocks, errs := parseMocks([]byte(mockJS), "")
+			require.Empty(t, errs)
+
+			matched, err := matchPattern(newMatchRequest(js), mocks[0].Request.BodyPatterns[0])
+			require.NoError(t, err)
+			assert.Equal(t, test.matched, matched)
+		})
+	}
+}
+
+func TestIncludesJSONDiagnostics(t *testing.T) {
+	js := []byte(`{"name": "Bob", "tags": ["a", "b"], "address": {"city": "Paris"}}`)
+
+	m := describeMismatch(newMatchRequest(js), DittoBodyPattern{
+		EqualToJson:       []byte(`{"address": {"city": "London"}}`),
+		IgnoreExtraFields: true,
+	})
+	assert.Equal(t, PatternMismatch{Path: "$.address.city", Expected: `"London"`, Actual: `"Paris"`}, m)
+
+	m = describeMismatch(newMatchRequest(js), DittoBodyPattern{
+		EqualToJson:       []byte(`{"tags": ["b", "c"]}`),
+		IgnoreExtraFields: true,
+		IgnoreArrayOrder:  true,
+	})
+	assert.Equal(t, PatternMismatch{Path: "$.tags", Expected: `["b","c"]`, Actual: `["a","b"]`}, m)
+}
+
+// benchmarkMocks creates mocks where only the last one matches the benchmark request
+func benchmarkMocks(n int) []DittoMock {
+	mocks := make([]DittoMock, 0, n)
+	for i := 0; i < n; i++ {
+		mocks = append(mocks, DittoMock{
+			Request: &DittoRequest{
+				Method: "/greet.Greeter/SayHello",
+				BodyPatterns: []DittoBodyPattern{
+					{
+						MatchesJsonPath: &JSONPathWrapper{
+							JSONPathMessage: JSONPathMessage{Expression: "$.user.name", Regexp: "^user-[0-9]+$"},
+						},
+					},
+					{
+						MatchesJsonPath: &JSONPathWrapper{
+							JSONPathMessage: JSONPathMessage{Expression: "$.user.id", Equals: strconv.Itoa(n - i - 1)},
+						},
+					},
+					{
+						EqualToJson:       []byte(fmt.Sprintf(`{"user": {"id": %d}}`, n-i-1)),
+						IgnoreExtraFields: true,
+					},
+				},
+			},
+		})
+	}
+	return mocks
+}
+
+func BenchmarkMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			rm, err := NewRequestMatcher(WithMocks(benchmarkMocks(n)))
+			require.NoError(b, err)
+
+			js := []byte(`{"user": {"id": 0, "name": "user-1", "email": "user@example.com"}, "tags": ["a", "b"]}`)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := rm.Match("/greet.Greeter/SayHello", js); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMatchEqualToJSON(b *testing.B) {
+	mocks := make([]DittoMock, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		mocks = append(mocks, DittoMock{
+			Request: &DittoRequest{
+				Method: "/greet.Greeter/SayHello",
+				BodyPatterns: []DittoBodyPattern{
+					{EqualToJson: []byte(fmt.Sprintf(`{"name": "user-%d", "age": 30}`, 999-i))},
+				},
+			},
+		})
+	}
+
+	rm, err := NewRequestMatcher(WithMocks(mocks))
+	require.NoError(b, err)
+
+	js := []byte(`{"age": 30, "name": "user-0"}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rm.Match("/greet.Greeter/SayHello", js); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRequestMatcherWeightedResponses(t *testing.T) {
+	mocks, errs := parseMocks([]byte(`[{
+		"request": {"method": "/a/b", "body_patterns": [{"equal_to_json": {}}]},
+		"responses": [
+			{"weight": 3, "response": [{"body": {"name": "ok"}}]},
+			{"response": [{"status": {"code": "UNAVAILABLE"}}]},
+			{"weight": 0, "response": [{"body": {"name": "never"}}]}
+		]
+	}]`), "")
+	require.Empty(t, errs)
+	require.Len(t, mocks[0].Responses, 3)
+	assert.Equal(t, uint32(1), mocks[0].Responses[1].Weight)
+
+	picks := func(seed int64) []string {
+		rm, err := NewRequestMatcher(WithMocks(mocks), WithRandSeed(seed))
+		require.NoError(t, err)
+
+		var result []string
+		for i := 0; i < 400; i++ {
+			mock, err := rm.Match("/a/b", []byte(`{}`))
+			require.NoError(t, err)
+			require.Len(t, mock.Response, 1)
+			if mock.Response[0].Status != nil {
+				result = append(result, mock.Response[0].Status.Code.String())
+			} else {
+				result = append(result, string(mock.Response[0].Body))
+			}
+		}
+		return result
+	}
+
+	first := picks(7)
+	assert.Equal(t, first, picks(7), "the same seed chooses the same responses")
+
+	counts := map[string]int{}
+	for _, r := range first {
+		counts[r]++
+	}
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 300, counts[`{"name":"ok"}`], 40)
+	assert.InDelta(t, 100, counts["Unavailable"], 40)
+
+	_, errs = parseMocks([]byte(`[{"request": {"method": "/a/b"}, "response": [{"body": {}}], "responses": [{"response": []}]}]`), "")
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "can't be used together")
+
+	_, errs = parseMocks([]byte(`[{"request": {"method": "/a/b"}, "responses": [{"weight": 0, "response": []}]}]`), "")
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "positive total weight")
+}
+
+func TestRequestMatcherDeadline(t *testing.T) {
+	mocks, errs := parseMocks([]byte(`[
+		{"id": "short", "request": {"method": "/a/b", "body_patterns": [{"deadline": {"lt": "1s"}}]}, "response": [{"body": {}}]},
+		{"id": "long", "request": {"method": "/a/b", "body_patterns": [{"deadline": {"gt": "10s", "lt": "1m"}}]}, "response": [{"body": {}}]},
+		{"id": "none", "request": {"method": "/a/b", "body_patterns": [{"deadline": {"is_set": false}}]}, "response": [{"body": {}}], "delay": "10ms"}
+	]`), "")
+	require.Empty(t, errs)
+	assert.Equal(t, 10*time.Millisecond, mocks[2].Delay)
+
+	rm, err := NewRequestMatcher(WithMocks(mocks))
+	require.NoError(t, err)
+
+	tests := []struct {
+		deadline time.Time
+		ok       bool
+		mockID   string
+	}{
+		{time.Now().Add(500 * time.Millisecond), true, "short"},
+		{time.Now().Add(30 * time.Second), true, "long"},
+		{time.Time{}, false, "none"},
+	}
+
+	for _, tt := range tests {
+		mock, err := rm.Match("/a/b", []byte(`{}`), WithDeadline(tt.deadline, tt.ok))
+		require.NoError(t, err)
+		assert.Equal(t, tt.mockID, mock.ID)
+	}
+
+	_, err = rm.Match("/a/b", []byte(`{}`), WithDeadline(time.Now().Add(5*time.Second), true))
+	var notMatched *NotMatchedError
+	require.ErrorAs(t, err, &notMatched)
+	assert.Contains(t, notMatched.Summary(), "deadline: expected lt 1s, got 5s")
+
+	for spec, msg := range map[string]string{
+		`{}`:                            "is_set, lt or gt is required",
+		`{"lt": "1s", "gt": "2s"}`:      "must be greater than gt",
+		`{"lt": "soon"}`:                `invalid lt "soon"`,
+		`{"is_set": false, "gt": "1s"}`: "require the deadline to be set",
+	} {
+		_, errs := parseMocks([]byte(`[{"request": {"method": "/a/b", "body_patterns": [{"deadline": `+spec+`}]}}]`), "")
+		require.Len(t, errs, 1, spec)
+		assert.ErrorContains(t, errs[0], msg)
+	}
+
+	_, errs = parseMocks([]byte(`[{"request": {"method": "/a/b"}, "response": [{"body": {}}], "delay": "soon"}]`), "")
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], `invalid delay "soon": time: invalid duration`)
+}
+
+func TestJSONPathObjectOperands(t *testing.T) {
+	mock := DittoMock{
+		Request: &DittoRequest{
+			Method: "test",
+			BodyPatterns: []DittoBodyPattern{
+				{
+					MatchesJsonPath: &JSONPathWrapper{
+						JSONPathMessage: JSONPathMessage{Expression: "$.station", Equals: `{"prop1": "val1", "name": "s1"}`},
+					},
+				},
+				{
+					MatchesJsonPath: &JSONPathWrapper{
+						JSONPathMessage: JSONPathMessage{Expression: "$.ids", In: []string{"[3]", "[1, 2]"}},
+					},
+				},
+			},
+		},
+		Response: []*DittoResponse{{Body: []byte("ok")}},
+	}
+
+	rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}))
+	require.NoError(t, err)
+
+	// operands are decoded once when the mock is added
+	prg := rm.Mocks()["test"][0].Request.BodyPatterns[0].MatchesJsonPath.compiled
+	require.NotNil(t, prg)
+	assert.Equal(t, map[string]interface{}{"prop1": "val1", "name": "s1"}, prg.equals.value)
+
+	_, err = rm.Match("test", []byte(`{"station": {"name": "s1", "prop1": "val1"}, "ids": [1, 2]}`))
+	assert.NoError(t, err)
+
+	_, err = rm.Match("test", []byte(`{"station": {"name": "s2", "prop1": "val1"}, "ids": [1, 2]}`))
+	assert.ErrorIs(t, err, ErrNotMatched)
+
+	_, err = rm.Match("test", []byte(`{"station": {"name": "s1", "prop1": "val1"}, "ids": [2]}`))
+	assert.ErrorIs(t, err, ErrNotMatched)
+}
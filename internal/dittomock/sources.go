@@ -0,0 +1,264 @@
+package dittomock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// StdinPath reads mocks from stdin
+const StdinPath = "-"
+
+// stdinSource is the file name of mocks read from stdin in diagnostics
+const stdinSource = "stdin"
+
+// MockSources lists where mocks are loaded from. Mocks of the later paths take precedence,
+// files found in a directory or by a glob are loaded in the order of their paths
+type MockSources struct {
+	// Paths are directories, files, glob patterns like mocks/*.yaml or - for stdin
+	Paths []string
+	// Include and Exclude are glob patterns matched against the names and the relative paths of the files
+	// found in directories and by globs, explicitly listed files are always loaded. Exclude also skips directories.
+	Include []string
+	Exclude []string
+	// Stdin is read for - path, os.Stdin is used if it's nil
+	Stdin io.Reader
+}
+
+// walk calls fn for every mock file of the sources in order, source is the index of the path the file was found by,
+// every file is loaded once as a file of the last path it was found by, so that a file listed after its directory
+// takes precedence. Read errors are passed to fn as *MockError
+func (s MockSources) walk(fn func(source int, path string, js []byte, err error) error) error {
+	w := &sourceWalker{sources: s, last: map[string]int{}}
+	var walkErr error
+	for i, p := range s.Paths {
+		w.source = i
+		if walkErr = w.walkPath(p); walkErr != nil {
+			break
+		}
+	}
+
+	loaded := map[string]bool{}
+	for _, f := range w.files {
+		if w.last[f.key] != f.source || loaded[f.key] {
+			continue
+		}
+		loaded[f.key] = true
+
+		js, err := f.js, f.err
+		if f.key != StdinPath {
+			js, err = readMockFile(f.path, strings.ToLower(filepath.Ext(f.path)))
+			if err != nil {
+				err = &MockError{Source: MockSource{File: f.path, Index: -1}, Err: err}
+			}
+		}
+
+		if err := fn(f.source, f.path, js, err); err != nil {
+			return err
+		}
+	}
+
+	return walkErr
+}
+
+// sourceFile is a mock file found by the source path, stdin content is read when it's found
+type sourceFile struct {
+	source int
+	key    string
+	path   string
+	js     []byte
+	err    error
+}
+
+type sourceWalker struct {
+	sources MockSources
+	source  int
+	files   []sourceFile
+	// last is the index of the last source that found the file
+	last      map[string]int
+	stdinRead bool
+}
+
+func (w *sourceWalker) add(f sourceFile) {
+	f.source = w.source
+	w.files = append(w.files, f)
+	w.last[f.key] = w.source
+}
+
+func (w *sourceWalker) walkPath(p string) error {
+	if p == StdinPath {
+		return w.readStdin()
+	}
+
+	if !strings.ContainsAny(p, "*?[") {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			if !isMockFile(p) {
+				return fmt.Errorf("%s: unsupported mock file, expected json, yaml or ndjson", p)
+			}
+			return w.load(p)
+		}
+		return w.walkDir(p)
+	}
+
+	matches, err := filepath.Glob(p)
+	if err != nil {
+		return fmt.Errorf("invalid mocks pattern %q: %w", p, err)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no mocks found by %s", p)
+	}
+	sort.Strings(matches)
+
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if err := w.walkDir(m); err != nil {
+				return err
+			}
+			continue
+		}
+		if isMockFile(m) && w.sources.selected(filepath.Base(m)) {
+			if err := w.load(m); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkDir loads mock files of the directory and its subdirectories in lexical order
+func (w *sourceWalker) walkDir(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			if path != root && matchesAny(w.sources.Exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isMockFile(path) || !w.sources.selected(rel) {
+			return nil
+		}
+
+		return w.load(path)
+	})
+}
+
+func (w *sourceWalker) load(path string) error {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+	w.add(sourceFile{key: key, path: path})
+	return nil
+}
+
+func (w *sourceWalker) readStdin() error {
+	if w.stdinRead {
+		return errors.New("mocks can be read from stdin only once")
+	}
+	w.stdinRead = true
+
+	in := w.sources.Stdin
+	if in == nil {
+		in = os.Stdin
+	}
+
+	content, err := io.ReadAll(in)
+	var js []byte
+	if err == nil {
+		js, err = stdinToJSON(content)
+	}
+	if err != nil {
+		err = &MockError{Source: MockSource{File: stdinSource, Index: -1}, Err: err}
+	}
+
+	w.add(sourceFile{key: StdinPath, path: stdinSource, js: js, err: err})
+	return nil
+}
+
+// selected returns true if the file passes include and exclude patterns
+func (s MockSources) selected(rel string) bool {
+	if len(s.Include) > 0 && !matchesAny(s.Include, rel) {
+		return false
+	}
+	return !matchesAny(s.Exclude, rel)
+}
+
+// matchesAny matches the patterns against the relative path and the name of the file
+func matchesAny(patterns []string, rel string) bool {
+	name := filepath.Base(rel)
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isMockFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml", ".ndjson":
+		return true
+	}
+	return false
+}
+
+// stdinToJSON detects the format of mocks read from stdin: json array, json objects one after another or yaml
+func stdinToJSON(content []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case len(trimmed) == 0:
+		return []byte("[]"), nil
+	case trimmed[0] == '[':
+		return trimmed, nil
+	case trimmed[0] == '{':
+		return ndjsonToJSON(trimmed)
+	default:
+		return yaml.YAMLToJSON(content)
+	}
+}
+
+// ndjsonToJSON converts mocks written one per line to json array, objects spanning multiple lines are also accepted
+func ndjsonToJSON(content []byte) ([]byte, error) {
+	mocks := []json.RawMessage{}
+	dec := json.NewDecoder(bytes.NewReader(content))
+	for {
+		var m json.RawMessage
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mock %d: %w", len(mocks), err)
+		}
+		mocks = append(mocks, m)
+	}
+
+	return json.Marshal(mocks)
+}
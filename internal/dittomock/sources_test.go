@@ -0,0 +1,110 @@
+package dittomock
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMockFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func mockIDs(mocks []DittoMock) []string {
+	ids := make([]string, 0, len(mocks))
+	for _, m := range mocks {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestMockSources(t *testing.T) {
+	mock := func(id string) string {
+		return `{"id": "` + id + `", "request": {"method": "/a/b", "body_patterns": [{"equal_to_json": {}}]}, "response": [{"body": {}}]}`
+	}
+
+	dir := writeMockFiles(t, map[string]string{
+		"base/b.json":             "[" + mock("base-b") + "]",
+		"base/a.yaml":             "- " + mock("base-a"),
+		"base/notes.txt":          "not a mock",
+		"base/drafts/c.json":      "[" + mock("draft-c") + "]",
+		"base/d.draft.json":       "[" + mock("draft-d") + "]",
+		"overrides/1.ndjson":      mock("override-1") + "\n\n" + mock("override-2") + "\n",
+		"overrides/2.ndjson":      mock("override-3"),
+		"overrides/skipped.json":  "[" + mock("skipped") + "]",
+		"overrides/nested/x.yaml": "- " + mock("nested"),
+	})
+
+	sources := MockSources{
+		Paths: []string{
+			filepath.Join(dir, "base"),
+			filepath.Join(dir, "overrides", "*.ndjson"),
+			filepath.Join(dir, "base", "a.yaml"),
+			StdinPath,
+		},
+		Exclude: []string{"drafts", "*.draft.json"},
+		Stdin:   strings.NewReader("- " + mock("stdin")),
+	}
+
+	mocks, errs := ParseMockSources(sources)
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"stdin", "base-a", "override-1", "override-2", "override-3", "base-b"}, mockIDs(mocks),
+		"later sources go first, files are loaded once by the last path in the order of their paths")
+	assert.Equal(t, MockSource{File: "stdin", Index: 0}, mocks[0].Source)
+
+	sources.Stdin = strings.NewReader(mock("stdin") + "\n")
+	rm, err := NewRequestMatcher(WithMockSources(sources))
+	require.NoError(t, err)
+	matched, err := rm.Match("/a/b", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, "stdin", matched.ID)
+
+	mocks, errs = ParseMockSources(MockSources{Paths: []string{dir}, Include: []string{"overrides/*"}})
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"override-1", "override-2", "override-3", "skipped"}, mockIDs(mocks))
+
+	for path, msg := range map[string]string{
+		filepath.Join(dir, "base", "notes.txt"): "unsupported mock file",
+		filepath.Join(dir, "*.proto"):           "no mocks found",
+		filepath.Join(dir, "missing"):           "no such file",
+	} {
+		_, errs := ParseMockSources(MockSources{Paths: []string{path}})
+		require.Len(t, errs, 1, path)
+		assert.ErrorContains(t, errs[0], msg)
+	}
+
+	_, errs = ParseMockSources(MockSources{Paths: []string{StdinPath, StdinPath}, Stdin: strings.NewReader("[]")})
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "stdin only once")
+}
+
+func TestMockSourcesFileAfterDirectory(t *testing.T) {
+	mock := func(id string) string {
+		return `[{"id": "` + id + `", "request": {"method": "/a/b", "body_patterns": [{"equal_to_json": {}}]}, "response": [{"body": {}}]}]`
+	}
+
+	dir := writeMockFiles(t, map[string]string{
+		"shared/base.json":     mock("base"),
+		"shared/override.json": mock("override"),
+	})
+
+	sources := MockSources{Paths: []string{filepath.Join(dir, "shared"), filepath.Join(dir, "shared", "override.json")}}
+	mocks, errs := ParseMockSources(sources)
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"override", "base"}, mockIDs(mocks))
+
+	sources.Paths = []string{filepath.Join(dir, "shared", "override.json"), filepath.Join(dir, "shared")}
+	mocks, errs = ParseMockSources(sources)
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"base", "override"}, mockIDs(mocks))
+}
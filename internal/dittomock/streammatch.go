@@ -0,0 +1,195 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// hasMessagePatterns returns true if the pattern matches messages of client streaming request
+func (p DittoBodyPattern) hasMessagePatterns() bool {
+	return p.AnyMessage != nil || p.EveryMessage != nil || p.MessageCount != nil || len(p.Sequence) > 0
+}
+
+// messages splits client streaming request into requests of single messages once per request
+func (r *matchRequest) messages() ([]*matchRequest, error) {
+	if r.items != nil || r.itemsErr != nil {
+		return r.items, r.itemsErr
+	}
+
+	// streams without messages are rendered as null
+	var items []json.RawMessage
+	if err := json.Unmarshal(r.json, &items); err != nil {
+		r.itemsErr = fmt.Errorf("message patterns require client streaming request: %w", err)
+		return nil, r.itemsErr
+	}
+
+	r.items = make([]*matchRequest, 0, len(items))
+	for _, item := range items {
+		r.items = append(r.items, &matchRequest{json: item, metadata: r.metadata, timeLeft: r.timeLeft, hasDeadline: r.hasDeadline})
+	}
+	return r.items, nil
+}
+
+// matchMessages matches message patterns against messages of client streaming request
+func matchMessages(req *matchRequest, pattern DittoBodyPattern) (bool, error) {
+	msgs, err := req.messages()
+	if err != nil {
+		return false, err
+	}
+
+	if pattern.MessageCount != nil && !pattern.MessageCount.contains(len(msgs)) {
+		return false, nil
+	}
+
+	if pattern.EveryMessage != nil {
+		if i, err := firstUnmatched(msgs, *pattern.EveryMessage); i >= 0 {
+			return false, err
+		}
+	}
+
+	if pattern.AnyMessage != nil {
+		val, err := matchAnyMessage(msgs, *pattern.AnyMessage)
+		if err != nil || !val {
+			return false, err
+		}
+	}
+
+	if len(pattern.Sequence) > 0 {
+		val, err := matchSequence(msgs, pattern.Sequence)
+		if err != nil || !val {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// firstUnmatched returns the index of the first message that doesn't match the pattern or -1
+func firstUnmatched(msgs []*matchRequest, pattern DittoBodyPattern) (int, error) {
+	for i, msg := range msgs {
+		val, err := matchPattern(msg, pattern)
+		if err != nil || !val {
+			return i, err
+		}
+	}
+	return -1, nil
+}
+
+// matchAnyMessage returns true if at least one message matches,
+// errors are returned only if none of the messages match
+func matchAnyMessage(msgs []*matchRequest, pattern DittoBodyPattern) (bool, error) {
+	var firstErr error
+	for _, msg := range msgs {
+		val, err := matchPattern(msg, pattern)
+		if err == nil && val {
+			return true, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return false, firstErr
+}
+
+// matchSequence matches the whole stream against the steps like a glob pattern:
+// message and any steps consume exactly one message, skip consumes zero or more.
+// Errors are returned only if the sequence doesn't match.
+func matchSequence(msgs []*matchRequest, steps []SequenceStep) (bool, error) {
+	var firstErr error
+	// results of message steps are cached as the same message can be tried against a step multiple times
+	type key struct{ msg, step int }
+	results := map[key]bool{}
+	stepMatches := func(i, j int) bool {
+		k := key{i, j}
+		if val, ok := results[k]; ok {
+			return val
+		}
+
+		val := true
+		if p := steps[j].Message; p != nil {
+			var err error
+			val, err = matchPattern(msgs[i], *p)
+			if err != nil {
+				val = false
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		results[k] = val
+		return val
+	}
+
+	// matched[i] is true if the first i messages match the steps processed so far
+	matched := make([]bool, len(msgs)+1)
+	matched[0] = true
+	for j, step := range steps {
+		next := make([]bool, len(msgs)+1)
+		for i := 0; i <= len(msgs); i++ {
+			switch {
+			case step.Skip:
+				next[i] = matched[i] || (i > 0 && next[i-1])
+			case i > 0:
+				next[i] = matched[i-1] && stepMatches(i-1, j)
+			}
+		}
+		matched = next
+	}
+
+	if matched[len(msgs)] {
+		return true, nil
+	}
+	return false, firstErr
+}
+
+// describeMessagesMismatch explains the first message pattern that didn't match
+func describeMessagesMismatch(req *matchRequest, pattern DittoBodyPattern) PatternMismatch {
+	msgs, err := req.messages()
+	if err != nil {
+		return PatternMismatch{Path: "$", Reason: err.Error()}
+	}
+
+	if c := pattern.MessageCount; c != nil && !c.contains(len(msgs)) {
+		return PatternMismatch{
+			Path:     "message_count",
+			Expected: c.describe(),
+			Actual:   fmt.Sprintf("%d messages", len(msgs)),
+		}
+	}
+
+	if pattern.EveryMessage != nil {
+		if i, _ := firstUnmatched(msgs, *pattern.EveryMessage); i >= 0 {
+			m := describeMismatch(msgs[i], *pattern.EveryMessage)
+			m.Path = fmt.Sprintf("every_message $[%d] %s", i, m.Path)
+			return m
+		}
+	}
+
+	if pattern.AnyMessage != nil {
+		if ok, err := matchAnyMessage(msgs, *pattern.AnyMessage); !ok {
+			reason := fmt.Sprintf("none of %d messages matched any_message", len(msgs))
+			if err != nil {
+				reason += ": " + err.Error()
+			}
+			return PatternMismatch{Path: "$", Reason: reason}
+		}
+	}
+
+	if len(pattern.Sequence) > 0 {
+		if ok, err := matchSequence(msgs, pattern.Sequence); !ok {
+			steps := make([]string, 0, len(pattern.Sequence))
+			for _, step := range pattern.Sequence {
+				steps = append(steps, step.describe())
+			}
+			reason := fmt.Sprintf("%d messages don't match sequence [%s]", len(msgs), strings.Join(steps, ", "))
+			if err != nil {
+				reason += ": " + err.Error()
+			}
+			return PatternMismatch{Path: "$", Reason: reason}
+		}
+	}
+
+	return PatternMismatch{Path: "$"}
+}
@@ -0,0 +1,157 @@
+package dittomock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessagePatterns(t *testing.T) {
+	const method = "/cel.test.OrderService/Upload"
+	resolve := celTestResolver(t)
+	chunk := func(currency string) DittoBodyPattern {
+		return DittoBodyPattern{MatchesJsonPath: &JSONPathWrapper{
+			JSONPathMessage: JSONPathMessage{Expression: "$.currency", Equals: currency},
+		}}
+	}
+	commit := func() *DittoBodyPattern {
+		return &DittoBodyPattern{MatchesField: &FieldPattern{Field: "amount", Gt: "0"}}
+	}
+	count := func(min uint32, max *uint32) *MessageCount {
+		return &MessageCount{Min: min, Max: max}
+	}
+	two := uint32(2)
+
+	stream := `[
+		{"currency": "EUR", "amount": "0", "items": [], "created_at": null},
+		{"currency": "EUR", "amount": "0", "items": [], "created_at": null},
+		{"currency": "USD", "amount": "10", "items": [], "created_at": null}
+	]`
+
+	tests := []struct {
+		name    string
+		pattern DittoBodyPattern
+		json    string
+		matched bool
+	}{
+		{name: "AnyMessage", pattern: DittoBodyPattern{AnyMessage: commit()}, json: stream, matched: true},
+		{name: "AnyMessageNone", pattern: DittoBodyPattern{AnyMessage: ptr(chunk("GBP"))}, json: stream, matched: false},
+		{name: "EveryMessage", pattern: DittoBodyPattern{EveryMessage: ptr(chunk("EUR"))}, json: stream, matched: false},
+		{name: "EveryMessageEmpty", pattern: DittoBodyPattern{EveryMessage: ptr(chunk("EUR"))}, json: `null`, matched: true},
+		{name: "MessageCount", pattern: DittoBodyPattern{MessageCount: count(2, nil)}, json: stream, matched: true},
+		{name: "MessageCountMax", pattern: DittoBodyPattern{MessageCount: count(1, &two)}, json: stream, matched: false},
+		{
+			name:    "SequenceCommitAtEnd",
+			pattern: DittoBodyPattern{Sequence: []SequenceStep{{Skip: true}, {Message: commit()}}},
+			json:    stream,
+			matched: true,
+		},
+		{
+			name:    "SequenceCommitFirst",
+			pattern: DittoBodyPattern{Sequence: []SequenceStep{{Message: commit()}, {Skip: true}}},
+			json:    stream,
+			matched: false,
+		},
+		{
+			name:    "SequenceAny",
+			pattern: DittoBodyPattern{Sequence: []SequenceStep{{Message: ptr(chunk("EUR"))}, {Any: true}, {Message: ptr(chunk("USD"))}}},
+			json:    stream,
+			matched: true,
+		},
+		{
+			name:    "SequenceTooShort",
+			pattern: DittoBodyPattern{Sequence: []SequenceStep{{Any: true}, {Any: true}}},
+			json:    stream,
+			matched: false,
+		},
+		{
+			name:    "SequenceOnlySkip",
+			pattern: DittoBodyPattern{Sequence: []SequenceStep{{Skip: true}}},
+			json:    `null`,
+			matched: true,
+		},
+		{
+			name: "Combined",
+			pattern: DittoBodyPattern{
+				MessageCount: count(2, nil),
+				EveryMessage: &DittoBodyPattern{MatchesCEL: "request.items.size() == 0"},
+				Sequence:     []SequenceStep{{Skip: true}, {Message: commit()}},
+			},
+			json:    stream,
+			matched: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := DittoMock{
+				Request: &DittoRequest{
+					Method:       method,
+					BodyPatterns: []DittoBodyPattern{test.pattern},
+				},
+			}
+
+			rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+			require.NoError(t, err)
+
+			_, err = rm.Match(method, []byte(test.json))
+			if test.matched {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNotMatched)
+			}
+		})
+	}
+}
+
+func TestMessagePatternsMismatchDescription(t *testing.T) {
+	const method = "/cel.test.OrderService/Upload"
+	resolve := celTestResolver(t)
+	two := uint32(2)
+
+	tests := []struct {
+		name     string
+		pattern  DittoBodyPattern
+		expected string
+	}{
+		{
+			name:     "MessageCount",
+			pattern:  DittoBodyPattern{MessageCount: &MessageCount{Min: 2, Max: &two}},
+			expected: "body_patterns[0] message_count: expected 2 messages, got 3 messages",
+		},
+		{
+			name:     "EveryMessage",
+			pattern:  DittoBodyPattern{EveryMessage: &DittoBodyPattern{MatchesField: &FieldPattern{Field: "currency", Equals: "EUR"}}},
+			expected: `body_patterns[0] every_message $[2] currency: expected eq "EUR", got USD`,
+		},
+		{
+			name:     "Sequence",
+			pattern:  DittoBodyPattern{Sequence: []SequenceStep{{Any: true}, {Skip: true}, {Message: &DittoBodyPattern{MatchesCEL: "request.amount > 100"}}}},
+			expected: "body_patterns[0] $: 3 messages don't match sequence [any, skip, message]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := DittoMock{
+				Request: &DittoRequest{
+					Method:       method,
+					BodyPatterns: []DittoBodyPattern{test.pattern},
+				},
+			}
+			rm, err := NewRequestMatcher(WithMocks([]DittoMock{mock}), WithMethodResolver(resolve))
+			require.NoError(t, err)
+
+			_, err = rm.Match(method, []byte(`[{"currency": "EUR"}, {"currency": "EUR"}, {"currency": "USD", "amount": "10"}]`))
+			var notMatched *NotMatchedError
+			require.ErrorAs(t, err, &notMatched)
+			require.Len(t, notMatched.Candidates, 1)
+			assert.Equal(t, test.expected, notMatched.Candidates[0].Mismatches[0].String())
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
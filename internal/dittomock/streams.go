@@ -0,0 +1,197 @@
+package dittomock
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var ErrStreamNotFound = errors.New("dittomock: stream not found")
+
+// OpenStream is a server stream registered while the mock server handles it
+type OpenStream struct {
+	ID       string
+	Method   string
+	Metadata metadata.MD
+	Started  time.Time
+	// Body is request json, client streaming requests are arrays of messages
+	Body json.RawMessage
+
+	seq    int
+	encode func(body []byte) (interface{}, error)
+	send   func(msg interface{}) error
+	closed chan *status.Status
+	once   sync.Once
+	// kept is set if the mock keeps the stream open after the responses are sent,
+	// only such streams can be closed
+	kept bool
+}
+
+// Closed delivers the status the stream has to be finished with
+func (s *OpenStream) Closed() <-chan *status.Status {
+	return s.closed
+}
+
+func (s *OpenStream) close(st *status.Status) {
+	s.once.Do(func() {
+		s.closed <- st
+	})
+}
+
+// StreamSelector chooses streams by id or by method and metadata, empty fields match any stream
+type StreamSelector struct {
+	ID     string
+	Method string
+	// Metadata values must be present in the request metadata
+	Metadata map[string]string
+}
+
+// Empty returns true if the selector matches all streams
+func (sel StreamSelector) Empty() bool {
+	return sel.ID == "" && sel.Method == "" && len(sel.Metadata) == 0
+}
+
+func (sel StreamSelector) matches(s *OpenStream) bool {
+	if sel.ID != "" && sel.ID != s.ID {
+		return false
+	}
+	if sel.Method != "" && sel.Method != s.Method {
+		return false
+	}
+	return metadataContains(s.Metadata, sel.Metadata)
+}
+
+// StreamRegistry keeps track of open server streams, so messages can be pushed into them
+type StreamRegistry struct {
+	mu      sync.Mutex
+	streams map[string]*OpenStream
+	lastID  int
+}
+
+func NewStreamRegistry() *StreamRegistry {
+	return &StreamRegistry{
+		streams: map[string]*OpenStream{},
+	}
+}
+
+// Open registers the stream, encode converts pushed json to the stream message
+// and send delivers it, send has to be safe to call concurrently with the stream handler
+func (r *StreamRegistry) Open(method string, md metadata.MD, body []byte,
+	encode func(body []byte) (interface{}, error), send func(msg interface{}) error,
+) *OpenStream {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	s := &OpenStream{
+		ID:       strconv.Itoa(r.lastID),
+		seq:      r.lastID,
+		Method:   method,
+		Metadata: md,
+		Started:  time.Now(),
+		Body:     body,
+		encode:   encode,
+		send:     send,
+		closed:   make(chan *status.Status, 1),
+	}
+	r.streams[s.ID] = s
+	return s
+}
+
+// Remove unregisters the stream when the handler returns
+func (r *StreamRegistry) Remove(s *OpenStream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.streams, s.ID)
+}
+
+// KeepOpen marks the stream that is held open after the mock responses are sent
+func (r *StreamRegistry) KeepOpen(s *OpenStream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.kept = true
+}
+
+// Streams returns the streams chosen by the selector ordered by id
+func (r *StreamRegistry) Streams(sel StreamSelector) []*OpenStream {
+	return r.find(sel, false)
+}
+
+func (r *StreamRegistry) find(sel StreamSelector, keptOnly bool) []*OpenStream {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*OpenStream
+	for _, s := range r.streams {
+		if sel.matches(s) && (s.kept || !keptOnly) {
+			result = append(result, s)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].seq < result[j].seq
+	})
+
+	return result
+}
+
+// Push sends the message to the chosen streams and returns the streams that received it.
+// The message is converted for every stream before sending, so an invalid message isn't
+// delivered to any of them. Streams finished after they were chosen are skipped,
+// ErrStreamNotFound is returned if no streams are chosen
+func (r *StreamRegistry) Push(sel StreamSelector, body []byte) ([]*OpenStream, error) {
+	streams := r.Streams(sel)
+	if len(streams) == 0 {
+		return nil, ErrStreamNotFound
+	}
+
+	msgs := make([]interface{}, len(streams))
+	for i, s := range streams {
+		msg, err := s.encode(body)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = msg
+	}
+
+	var sent []*OpenStream
+	var firstErr error
+	for i, s := range streams {
+		if err := s.send(msgs[i]); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		sent = append(sent, s)
+	}
+
+	if len(sent) == 0 {
+		return nil, firstErr
+	}
+
+	return sent, nil
+}
+
+// Close finishes the chosen streams that are held open with the status and returns them,
+// ErrStreamNotFound is returned if no such streams are chosen
+func (r *StreamRegistry) Close(sel StreamSelector, st *status.Status) ([]*OpenStream, error) {
+	streams := r.find(sel, true)
+	if len(streams) == 0 {
+		return nil, ErrStreamNotFound
+	}
+
+	for _, s := range streams {
+		s.close(st)
+	}
+
+	return streams, nil
+}
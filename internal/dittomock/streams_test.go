@@ -0,0 +1,102 @@
+package dittomock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamRegistry(t *testing.T) {
+	r := NewStreamRegistry()
+
+	var pushed []string
+	encode := func(body []byte) (interface{}, error) {
+		return string(body), nil
+	}
+	send := func(id string) func(interface{}) error {
+		return func(msg interface{}) error {
+			pushed = append(pushed, id+":"+msg.(string))
+			return nil
+		}
+	}
+
+	bob := r.Open("/events.Events/Watch", metadata.Pairs("x-user", "bob"), []byte(`{}`), encode, send("bob"))
+	alice := r.Open("/events.Events/Watch", metadata.Pairs("x-user", "alice"), []byte(`{}`), encode, send("alice"))
+	other := r.Open("/events.Events/Tail", nil, []byte(`{}`), encode, send("other"))
+
+	assert.Len(t, r.Streams(StreamSelector{}), 3)
+	assert.Equal(t, []*OpenStream{bob, alice}, r.Streams(StreamSelector{Method: "/events.Events/Watch"}))
+	assert.Equal(t, []*OpenStream{alice}, r.Streams(StreamSelector{Metadata: map[string]string{"x-user": "alice"}}))
+	assert.Equal(t, []*OpenStream{other}, r.Streams(StreamSelector{ID: other.ID}))
+
+	streams, err := r.Push(StreamSelector{Method: "/events.Events/Watch"}, []byte(`{"n":1}`))
+	require.NoError(t, err)
+	assert.Len(t, streams, 2)
+	assert.Equal(t, []string{`bob:{"n":1}`, `alice:{"n":1}`}, pushed)
+
+	_, err = r.Push(StreamSelector{Method: "/events.Events/Unknown"}, []byte(`{}`))
+	assert.ErrorIs(t, err, ErrStreamNotFound)
+
+	// only streams held open can be closed
+	_, err = r.Close(StreamSelector{ID: bob.ID}, status.New(codes.Aborted, "closed"))
+	assert.ErrorIs(t, err, ErrStreamNotFound)
+
+	r.KeepOpen(bob)
+	streams, err = r.Close(StreamSelector{Method: "/events.Events/Watch"}, status.New(codes.Aborted, "closed"))
+	require.NoError(t, err)
+	assert.Equal(t, []*OpenStream{bob}, streams)
+	// the first status wins if the stream is closed twice
+	_, err = r.Close(StreamSelector{ID: bob.ID}, status.New(codes.OK, ""))
+	require.NoError(t, err)
+	assert.Equal(t, codes.Aborted, (<-bob.Closed()).Code())
+
+	r.Remove(bob)
+	_, err = r.Close(StreamSelector{ID: bob.ID}, status.New(codes.OK, ""))
+	assert.ErrorIs(t, err, ErrStreamNotFound)
+}
+
+func TestStreamRegistryPush(t *testing.T) {
+	r := NewStreamRegistry()
+
+	var pushed []string
+	encode := func(method string) func([]byte) (interface{}, error) {
+		return func(body []byte) (interface{}, error) {
+			if method == "/events.Events/Tail" && string(body) != "{}" {
+				return nil, status.Error(codes.InvalidArgument, "invalid message")
+			}
+			return string(body), nil
+		}
+	}
+	send := func(id string, err error) func(interface{}) error {
+		return func(msg interface{}) error {
+			if err != nil {
+				return err
+			}
+			pushed = append(pushed, id+":"+msg.(string))
+			return nil
+		}
+	}
+
+	bob := r.Open("/events.Events/Watch", nil, []byte(`{}`), encode("/events.Events/Watch"), send("bob", nil))
+	r.Open("/events.Events/Watch", nil, []byte(`{}`), encode("/events.Events/Watch"), send("gone", ErrStreamNotFound))
+	r.Open("/events.Events/Tail", nil, []byte(`{}`), encode("/events.Events/Tail"), send("tail", nil))
+
+	// the message that doesn't fit one of the streams isn't sent to any of them
+	_, err := r.Push(StreamSelector{}, []byte(`{"n":1}`))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Empty(t, pushed)
+
+	// finished streams are skipped
+	streams, err := r.Push(StreamSelector{Method: "/events.Events/Watch"}, []byte(`{"n":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, []*OpenStream{bob}, streams)
+	assert.Equal(t, []string{`bob:{"n":1}`}, pushed)
+
+	r.Remove(bob)
+	_, err = r.Push(StreamSelector{Method: "/events.Events/Watch"}, []byte(`{"n":2}`))
+	assert.ErrorIs(t, err, ErrStreamNotFound)
+}
@@ -11,6 +11,11 @@ var funcMap = template.FuncMap{
 	"now_rfc3339":     nowRfc3339,
 	"now_add_rfc3339": nowAddRfc3339,
 	"now_utc_rfc3339": nowUtcRfc3339,
+	"add":             add,
+}
+
+func add(a, b int) int {
+	return a + b
 }
 
 func nowRfc3339() string {
@@ -0,0 +1,159 @@
+package mockgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+
+	// number of example messages generated for server streaming methods
+	streamResponses = 2
+)
+
+// Mock mirrors mock file format, it's used to output mock skeletons
+type Mock struct {
+	Request  Request    `json:"request" yaml:"request"`
+	Response []Response `json:"response" yaml:"response"`
+}
+
+type Request struct {
+	Method       string        `json:"method" yaml:"method"`
+	BodyPatterns []BodyPattern `json:"body_patterns" yaml:"body_patterns"`
+}
+
+type BodyPattern struct {
+	EqualToJSON     *Object          `json:"equal_to_json,omitempty" yaml:"equal_to_json,omitempty"`
+	MatchesJSONPath *JSONPathPattern `json:"matches_jsonpath,omitempty" yaml:"matches_jsonpath,omitempty"`
+}
+
+type JSONPathPattern struct {
+	Expression string `json:"expression" yaml:"expression"`
+	Eq         string `json:"eq,omitempty" yaml:"eq,omitempty"`
+}
+
+type Response struct {
+	Body Object `json:"body" yaml:"body"`
+}
+
+// GenerateService creates one mock per service method
+func GenerateService(sd *desc.ServiceDescriptor) []Mock {
+	mocks := make([]Mock, 0, len(sd.GetMethods()))
+	for _, md := range sd.GetMethods() {
+		mocks = append(mocks, GenerateMethod(md))
+	}
+	return mocks
+}
+
+// GenerateMethod creates a mock with a sample request pattern and an example response body.
+// Server streaming methods get multiple response messages.
+func GenerateMethod(md *desc.MethodDescriptor) Mock {
+	mock := Mock{
+		Request: Request{
+			Method:       MethodName(md),
+			BodyPatterns: []BodyPattern{samplePattern(md)},
+		},
+	}
+
+	count := 1
+	if md.IsServerStreaming() {
+		count = streamResponses
+	}
+
+	for i := 1; i <= count; i++ {
+		mock.Response = append(mock.Response, Response{
+			Body: SampleMessage(md.GetOutputType(), i),
+		})
+	}
+
+	return mock
+}
+
+// MethodName returns fully qualified grpc method name like /greet.Greeter/SayHello
+func MethodName(md *desc.MethodDescriptor) string {
+	return fmt.Sprintf("/%s/%s", md.GetService().GetFullyQualifiedName(), md.GetName())
+}
+
+// samplePattern matches the first scalar field of the input message with its example value,
+// client streams are represented as an array of messages, so the pattern targets the first one
+func samplePattern(md *desc.MethodDescriptor) BodyPattern {
+	prefix := "$"
+	if md.IsClientStreaming() {
+		prefix = "$[0]"
+	}
+
+	input := md.GetInputType()
+	for _, fd := range input.GetFields() {
+		if fd.IsRepeated() || fd.GetMessageType() != nil {
+			continue
+		}
+
+		// bytes are matched as base64 strings which is not very helpful for an example
+		if fd.GetType() == descriptorpb.FieldDescriptorProto_TYPE_BYTES {
+			continue
+		}
+
+		return BodyPattern{
+			MatchesJSONPath: &JSONPathPattern{
+				Expression: prefix + "." + fd.GetName(),
+				Eq:         fmt.Sprint(sampleScalar(fd, fd.GetName(), 1)),
+			},
+		}
+	}
+
+	if len(input.GetFields()) > 0 {
+		// no scalar fields, just check that the first field is present
+		return BodyPattern{
+			MatchesJSONPath: &JSONPathPattern{
+				Expression: prefix + "." + input.GetFields()[0].GetName(),
+			},
+		}
+	}
+
+	if md.IsClientStreaming() {
+		return BodyPattern{
+			MatchesJSONPath: &JSONPathPattern{
+				Expression: prefix,
+			},
+		}
+	}
+
+	return BodyPattern{EqualToJSON: &Object{}}
+}
+
+// Write outputs mocks in json or yaml format
+func Write(w io.Writer, mocks []Mock, format string) error {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		js, err := json.MarshalIndent(mocks, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(js, '\n'))
+		return err
+	case FormatYAML, "yml":
+		buf := &bytes.Buffer{}
+		buf.WriteString("---\n")
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(mocks); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+		_, err := w.Write(buf.Bytes())
+		return err
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
@@ -0,0 +1,151 @@
+package mockgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const testProto = `syntax = "proto3";
+
+package mockgen.test;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+service OrderService {
+  rpc Get(GetRequest) returns (Order);
+  rpc Watch(GetRequest) returns (stream Order);
+  rpc Upload(stream Chunk) returns (Order);
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+  STATUS_ACTIVE = 1;
+}
+
+message GetRequest {
+  Order filter = 1;
+  int64 id = 2;
+}
+
+message Chunk {
+  bytes data = 1;
+}
+
+message Order {
+  string name = 1;
+  int64 amount = 2;
+  Status status = 3;
+  repeated Item items = 4;
+  map<string, int32> counts = 5;
+  google.protobuf.Timestamp created_at = 6;
+  google.protobuf.StringValue note = 7;
+  Order parent = 8;
+  oneof payment {
+    string card = 9;
+    string cash = 10;
+  }
+}
+
+message Item {
+  string sku = 1;
+  double price = 2;
+  bytes data = 3;
+}
+`
+
+func parseTestService(t *testing.T) *desc.ServiceDescriptor {
+	p := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{
+			"test.proto": testProto,
+		}),
+		IncludeSourceCodeInfo: true,
+	}
+	fds, err := p.ParseFiles("test.proto")
+	require.NoError(t, err)
+
+	return fds[0].FindService("mockgen.test.OrderService")
+}
+
+func TestGenerateMethodResponseMatchesOutputType(t *testing.T) {
+	sd := parseTestService(t)
+
+	for _, md := range sd.GetMethods() {
+		mock := GenerateMethod(md)
+		for _, resp := range mock.Response {
+			js, err := json.Marshal(resp.Body)
+			require.NoError(t, err)
+
+			msg := dynamic.NewMessage(md.GetOutputType())
+			require.NoError(t, msg.UnmarshalJSON(js), string(js))
+		}
+	}
+}
+
+func TestGenerateMethodSample(t *testing.T) {
+	sd := parseTestService(t)
+
+	mock := GenerateMethod(sd.FindMethodByName("Get"))
+	assert.Equal(t, "/mockgen.test.OrderService/Get", mock.Request.Method)
+	require.Len(t, mock.Request.BodyPatterns, 1)
+	assert.Equal(t, "$.id", mock.Request.BodyPatterns[0].MatchesJSONPath.Expression)
+	assert.Equal(t, "1", mock.Request.BodyPatterns[0].MatchesJSONPath.Eq)
+	require.Len(t, mock.Response, 1)
+
+	js, err := json.Marshal(mock.Response[0].Body)
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(js, &body))
+	assert.Equal(t, "name", body["name"])
+	assert.Equal(t, "STATUS_ACTIVE", body["status"])
+	assert.Equal(t, "2024-01-01T00:00:00Z", body["created_at"])
+	assert.Equal(t, "note", body["note"])
+	assert.Equal(t, "card", body["card"])
+	assert.NotContains(t, body, "cash")
+	assert.NotContains(t, body, "parent")
+	assert.Len(t, body["items"], 1)
+}
+
+func TestGenerateMethodStreaming(t *testing.T) {
+	sd := parseTestService(t)
+
+	mock := GenerateMethod(sd.FindMethodByName("Watch"))
+	require.Len(t, mock.Response, 2)
+	assert.Equal(t, "name", mock.Response[0].Body[0].Value)
+	assert.Equal(t, "name 2", mock.Response[1].Body[0].Value)
+
+	mock = GenerateMethod(sd.FindMethodByName("Upload"))
+	require.Len(t, mock.Response, 1)
+	assert.Equal(t, "$[0].data", mock.Request.BodyPatterns[0].MatchesJSONPath.Expression)
+	assert.Empty(t, mock.Request.BodyPatterns[0].MatchesJSONPath.Eq)
+}
+
+func TestWriteFormats(t *testing.T) {
+	sd := parseTestService(t)
+	mocks := GenerateService(sd)
+
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, Write(buf, mocks, format))
+
+			js, err := yaml.YAMLToJSON(buf.Bytes())
+			require.NoError(t, err)
+
+			var result []map[string]interface{}
+			require.NoError(t, json.Unmarshal(js, &result))
+			assert.Len(t, result, len(sd.GetMethods()))
+		})
+	}
+
+	assert.Error(t, Write(&bytes.Buffer{}, mocks, "xml"))
+}
@@ -0,0 +1,65 @@
+package mockgen
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field is a single key/value pair of an Object
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Object is a JSON object that keeps its fields in proto declaration order,
+// so generated mocks read the same way as the messages they are built from
+type Object []Field
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		val, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (o Object) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+	}
+
+	for _, f := range o {
+		valNode := &yaml.Node{}
+		if err := valNode.Encode(f.Value); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.Name},
+			valNode,
+		)
+	}
+
+	return node, nil
+}
@@ -0,0 +1,163 @@
+package mockgen
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// nested messages are populated down to this depth,
+	// deeper fields are left out to keep examples readable
+	maxDepth = 5
+
+	sampleTimestamp = "2024-01-01T00:00:00Z"
+)
+
+// SampleMessage builds an example JSON object for the message type,
+// n is used to produce distinct values for multiple examples of the same message
+func SampleMessage(md *desc.MessageDescriptor, n int) Object {
+	obj, _ := sampleMessage(md, md.GetName(), n, map[string]bool{}, 0).(Object)
+	if obj == nil {
+		obj = Object{}
+	}
+	return obj
+}
+
+func sampleMessage(md *desc.MessageDescriptor, name string, n int, visiting map[string]bool, depth int) interface{} {
+	if wkt, ok := sampleWellKnown(md, name, n); ok {
+		return wkt
+	}
+
+	msgName := md.GetFullyQualifiedName()
+	visiting[msgName] = true
+	defer delete(visiting, msgName)
+
+	obj := Object{}
+	oneofs := map[*desc.OneOfDescriptor]bool{}
+	for _, fd := range md.GetFields() {
+		// only one field of a oneof can be set
+		if oo := fd.GetOneOf(); oo != nil && !oo.IsSynthetic() {
+			if oneofs[oo] {
+				continue
+			}
+			oneofs[oo] = true
+		}
+
+		val, ok := sampleField(fd, n, visiting, depth)
+		if !ok {
+			continue
+		}
+
+		obj = append(obj, Field{Name: fd.GetName(), Value: val})
+	}
+
+	return obj
+}
+
+func sampleField(fd *desc.FieldDescriptor, n int, visiting map[string]bool, depth int) (interface{}, bool) {
+	if fd.IsMap() {
+		val, ok := sampleSingular(fd.GetMapValueType(), n, visiting, depth)
+		if !ok {
+			return nil, false
+		}
+		key := fmt.Sprint(sampleScalar(fd.GetMapKeyType(), "key", n))
+		return Object{{Name: key, Value: val}}, true
+	}
+
+	val, ok := sampleSingular(fd, n, visiting, depth)
+	if !ok {
+		return nil, false
+	}
+
+	if fd.IsRepeated() {
+		return []interface{}{val}, true
+	}
+
+	return val, true
+}
+
+func sampleSingular(fd *desc.FieldDescriptor, n int, visiting map[string]bool, depth int) (interface{}, bool) {
+	msg := fd.GetMessageType()
+	if msg == nil {
+		return sampleScalar(fd, fd.GetName(), n), true
+	}
+
+	// recursive types and very deep messages are skipped
+	if visiting[msg.GetFullyQualifiedName()] || depth >= maxDepth {
+		return nil, false
+	}
+
+	val := sampleMessage(msg, fd.GetName(), n, visiting, depth+1)
+	if val == nil {
+		return nil, false
+	}
+
+	return val, true
+}
+
+// sampleScalar returns example value of the field, name is used as a base for strings and bytes
+func sampleScalar(fd *desc.FieldDescriptor, name string, n int) interface{} {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		if n > 1 {
+			return fmt.Sprintf("%s %d", name, n)
+		}
+		return name
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return base64.StdEncoding.EncodeToString([]byte(name))
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return true
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return float64(n) + 0.5
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return sampleEnum(fd.GetEnumType())
+	default:
+		return n
+	}
+}
+
+// sampleEnum returns the first non zero value of the enum,
+// zero values are usually reserved for UNSPECIFIED
+func sampleEnum(ed *desc.EnumDescriptor) string {
+	values := ed.GetValues()
+	for _, v := range values {
+		if v.GetNumber() != 0 {
+			return v.GetName()
+		}
+	}
+
+	return values[0].GetName()
+}
+
+// sampleWellKnown returns values for google.protobuf types
+// that have special json representation
+func sampleWellKnown(md *desc.MessageDescriptor, name string, n int) (interface{}, bool) {
+	switch md.GetFullyQualifiedName() {
+	case "google.protobuf.Timestamp":
+		return sampleTimestamp, true
+	case "google.protobuf.Duration":
+		return fmt.Sprintf("%ds", n), true
+	case "google.protobuf.Struct", "google.protobuf.Empty":
+		return Object{}, true
+	case "google.protobuf.ListValue":
+		return []interface{}{}, true
+	case "google.protobuf.Value":
+		return "value", true
+	case "google.protobuf.FieldMask":
+		return "", true
+	case "google.protobuf.Any":
+		// Any requires a resolvable @type, it's impossible to provide a meaningful example
+		return nil, true
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue",
+		"google.protobuf.BytesValue":
+		return sampleScalar(md.FindFieldByName("value"), name, n), true
+	}
+
+	return nil, false
+}
@@ -2,20 +2,30 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/vadimi/grpc-ditto/api"
 	"github.com/vadimi/grpc-ditto/internal/dittomock"
 	"github.com/vadimi/grpc-ditto/internal/logger"
 
 	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/genproto/googleapis/rpc/code"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type mockingServiceImpl struct {
 	matcher   *dittomock.RequestMatcher
 	log       logger.Logger
 	validator MockValidator
+	journal   *dittomock.Journal[dittomock.JournalEntry]
+	canceled  *dittomock.Journal[dittomock.CanceledEntry]
+	streams   *dittomock.StreamRegistry
+	faults    *dittomock.FaultInjector
 
 	api.UnimplementedMockingServiceServer
 }
@@ -24,16 +34,27 @@ type MockValidator interface {
 	ValidateMock(dittomock.DittoMock) error
 }
 
-func NewMockingService(matcher *dittomock.RequestMatcher, validator MockValidator, log logger.Logger) api.MockingServiceServer {
+func NewMockingService(matcher *dittomock.RequestMatcher, validator MockValidator, journal *dittomock.Journal[dittomock.JournalEntry],
+	canceled *dittomock.Journal[dittomock.CanceledEntry], streams *dittomock.StreamRegistry, faults *dittomock.FaultInjector, log logger.Logger,
+) api.MockingServiceServer {
 	return &mockingServiceImpl{
-		matcher: matcher,
-		log:     log,
+		matcher:   matcher,
+		validator: validator,
+		journal:   journal,
+		canceled:  canceled,
+		streams:   streams,
+		faults:    faults,
+		log:       log,
 	}
 }
 
 func (s *mockingServiceImpl) Clear(ctx context.Context, req *api.ClearRequest) (*api.ClearResponse, error) {
 	s.log.Info("clear all mocks")
 	s.matcher.Clear()
+	// unmatched requests refer to the mocks that are cleared
+	if s.journal != nil {
+		s.journal.Clear()
+	}
 	return &api.ClearResponse{}, nil
 }
 
@@ -67,11 +88,244 @@ func (s *mockingServiceImpl) AddMock(ctx context.Context, req *api.AddMockReques
 		return nil, err
 	}
 
-	s.matcher.AddMock(mock)
+	if err := s.matcher.AddMock(mock); err != nil {
+		s.log.Errorw("mock compilation failed", "err", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	return &api.AddMockResponse{}, nil
 }
 
+func (s *mockingServiceImpl) ListUnmatchedRequests(ctx context.Context, req *api.ListUnmatchedRequestsRequest) (*api.ListUnmatchedRequestsResponse, error) {
+	if s.journal == nil {
+		return nil, status.Error(codes.FailedPrecondition, "unmatched requests are not tracked")
+	}
+
+	resp := &api.ListUnmatchedRequestsResponse{}
+	for _, e := range s.journal.Entries() {
+		body := &structpb.Value{}
+		if err := protojson.Unmarshal(e.Body, body); err != nil {
+			s.log.Errorw("converting request body", "err", err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		resp.Requests = append(resp.Requests, &api.UnmatchedRequest{
+			Time:       timestamppb.New(e.Time),
+			Method:     e.Method,
+			Body:       body,
+			Candidates: matchCandidates(e.Candidates),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *mockingServiceImpl) ListCanceledRequests(ctx context.Context, req *api.ListCanceledRequestsRequest) (*api.ListCanceledRequestsResponse, error) {
+	if s.canceled == nil {
+		return nil, status.Error(codes.FailedPrecondition, "canceled requests are not tracked")
+	}
+
+	resp := &api.ListCanceledRequestsResponse{}
+	for _, e := range s.canceled.Entries() {
+		r := &api.CanceledRequest{
+			Time:    timestamppb.New(e.Time),
+			Method:  e.Method,
+			MockId:  e.MockID,
+			Code:    code.Code(e.Code),
+			Stage:   e.Stage,
+			Elapsed: durationpb.New(e.Elapsed),
+		}
+
+		if e.Body != nil {
+			r.Body = &structpb.Value{}
+			if err := protojson.Unmarshal(e.Body, r.Body); err != nil {
+				s.log.Errorw("converting request body", "err", err)
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+
+		if e.Timeout > 0 {
+			r.Timeout = durationpb.New(e.Timeout)
+		}
+
+		resp.Requests = append(resp.Requests, r)
+	}
+
+	return resp, nil
+}
+
+func (s *mockingServiceImpl) ListStreams(ctx context.Context, req *api.ListStreamsRequest) (*api.ListStreamsResponse, error) {
+	if s.streams == nil {
+		return nil, status.Error(codes.FailedPrecondition, "streams are not tracked")
+	}
+
+	resp := &api.ListStreamsResponse{}
+	for _, st := range s.streams.Streams(streamSelector(req.GetSelector())) {
+		body := &structpb.Value{}
+		if err := protojson.Unmarshal(st.Body, body); err != nil {
+			s.log.Errorw("converting request body", "err", err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		md := make(map[string]*api.MetadataValues, len(st.Metadata))
+		for k, v := range st.Metadata {
+			md[k] = &api.MetadataValues{Values: v}
+		}
+
+		resp.Streams = append(resp.Streams, &api.OpenStream{
+			Id:       st.ID,
+			Method:   st.Method,
+			Started:  timestamppb.New(st.Started),
+			Metadata: md,
+			Body:     body,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *mockingServiceImpl) PushMessage(ctx context.Context, req *api.PushMessageRequest) (*api.PushMessageResponse, error) {
+	sel, err := s.requiredSelector(req.GetSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte("{}")
+	if req.GetBody() != nil {
+		body, err = protojson.Marshal(req.GetBody())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	s.log.Infow("push message", "stream", sel.ID, "method", sel.Method)
+	streams, err := s.streams.Push(sel, body)
+	if err != nil {
+		return nil, streamErr(err)
+	}
+
+	return &api.PushMessageResponse{StreamIds: streamIDs(streams)}, nil
+}
+
+func (s *mockingServiceImpl) CloseStream(ctx context.Context, req *api.CloseStreamRequest) (*api.CloseStreamResponse, error) {
+	sel, err := s.requiredSelector(req.GetSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	st := status.New(codes.Code(req.GetStatus().GetCode()), req.GetStatus().GetMessage())
+	s.log.Infow("close stream", "stream", sel.ID, "method", sel.Method, "code", st.Code())
+	streams, err := s.streams.Close(sel, st)
+	if err != nil {
+		return nil, streamErr(err)
+	}
+
+	return &api.CloseStreamResponse{StreamIds: streamIDs(streams)}, nil
+}
+
+func (s *mockingServiceImpl) AddFaultRule(ctx context.Context, req *api.AddFaultRuleRequest) (*api.AddFaultRuleResponse, error) {
+	if s.faults == nil {
+		return nil, status.Error(codes.FailedPrecondition, "fault injection is disabled")
+	}
+
+	if req.GetRule() == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	rule, err := dittomock.FaultRuleFromProto(req.GetRule())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.log.Infow("add fault rule", "rule", rule.ID, "method", rule.Method)
+	s.faults.Add(rule)
+	return &api.AddFaultRuleResponse{}, nil
+}
+
+func (s *mockingServiceImpl) ListFaultRules(ctx context.Context, req *api.ListFaultRulesRequest) (*api.ListFaultRulesResponse, error) {
+	resp := &api.ListFaultRulesResponse{}
+	if s.faults == nil {
+		return resp, nil
+	}
+
+	for _, rule := range s.faults.Rules() {
+		resp.Rules = append(resp.Rules, rule.ToProto())
+	}
+	return resp, nil
+}
+
+func (s *mockingServiceImpl) ClearFaultRules(ctx context.Context, req *api.ClearFaultRulesRequest) (*api.ClearFaultRulesResponse, error) {
+	if s.faults != nil {
+		s.log.Info("clear all fault rules")
+		s.faults.Clear()
+	}
+	return &api.ClearFaultRulesResponse{}, nil
+}
+
+// requiredSelector doesn't allow choosing all streams at once for push and close
+func (s *mockingServiceImpl) requiredSelector(sel *api.StreamSelector) (dittomock.StreamSelector, error) {
+	if s.streams == nil {
+		return dittomock.StreamSelector{}, status.Error(codes.FailedPrecondition, "streams are not tracked")
+	}
+
+	if sel.GetStreamId() == "" && sel.GetMethod() == "" {
+		return dittomock.StreamSelector{}, status.Error(codes.InvalidArgument, "stream_id or method is required")
+	}
+
+	return streamSelector(sel), nil
+}
+
+func streamSelector(sel *api.StreamSelector) dittomock.StreamSelector {
+	return dittomock.StreamSelector{
+		ID:       sel.GetStreamId(),
+		Method:   sel.GetMethod(),
+		Metadata: sel.GetMetadata(),
+	}
+}
+
+func streamErr(err error) error {
+	if errors.Is(err, dittomock.ErrStreamNotFound) {
+		return status.Error(codes.NotFound, "no open streams found")
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func streamIDs(streams []*dittomock.OpenStream) []string {
+	ids := make([]string, 0, len(streams))
+	for _, st := range streams {
+		ids = append(ids, st.ID)
+	}
+	return ids
+}
+
+func matchCandidates(candidates []dittomock.Candidate) []*api.MatchCandidate {
+	result := make([]*api.MatchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		mc := &api.MatchCandidate{
+			MockId:          c.MockID,
+			MatchedPatterns: int32(c.Matched),
+			TotalPatterns:   int32(c.Total),
+		}
+
+		for _, m := range c.Mismatches {
+			mc.Mismatches = append(mc.Mismatches, &api.PatternMismatch{
+				PatternIndex: int32(m.Index),
+				Path:         m.Path,
+				Expected:     m.Expected,
+				Actual:       m.Actual,
+				Reason:       m.Reason,
+			})
+		}
+
+		result = append(result, mc)
+	}
+
+	return result
+}
+
 func dittoMock(req *api.AddMockRequest) (dittomock.DittoMock, error) {
 	return dittomock.FromProto(req.Mock)
 }
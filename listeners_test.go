@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestListeners(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "ditto.sock")
+
+	// socket file of a server that wasn't stopped gracefully
+	stale, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listeners, err := openListeners([]string{"127.0.0.1:0", "unix://" + sock})
+	require.NoError(t, err)
+
+	portFile := filepath.Join(dir, "ports")
+	require.NoError(t, writePortFile(portFile, []string{listenerAddr(listeners[0]), listenerAddr(listeners[1])}))
+
+	content, err := os.ReadFile(portFile)
+	require.NoError(t, err)
+	addrs := strings.Fields(string(content))
+	require.Len(t, addrs, 2)
+	assert.NotEqual(t, "127.0.0.1:0", addrs[0], "ephemeral port is resolved")
+	assert.Equal(t, "unix://"+sock, addrs[1])
+
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	for _, lis := range listeners {
+		go server.Serve(lis)
+	}
+	defer server.Stop()
+
+	for _, addr := range addrs {
+		cc, err := grpc.Dial(addr, grpc.WithInsecure())
+		require.NoError(t, err)
+		defer cc.Close()
+
+		resp, err := grpc_health_v1.NewHealthClient(cc).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err, addr)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	_, err = openListeners([]string{"127.0.0.1:0", "unix://"})
+	assert.ErrorContains(t, err, "socket path is required")
+}
+
+func TestListenSocketInUse(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "ditto.sock")
+
+	lis, err := listen("unix://" + sock)
+	require.NoError(t, err)
+	defer lis.Close()
+
+	_, err = listen("unix://" + sock)
+	assert.ErrorContains(t, err, "address in use")
+
+	// the socket of the running server is kept
+	conn, err := net.Dial("unix", sock)
+	require.NoError(t, err)
+	conn.Close()
+}
@@ -11,39 +11,210 @@ func main() {
 	app := cli.NewApp()
 	app.Version = "0.8.1"
 	app.Usage = "grpc mocking server"
-	app.Flags = []cli.Flag{
+	app.Flags = serverFlags()
+
+	app.Commands = []cli.Command{
+		{
+			Name:  "generate",
+			Usage: "generate mock skeletons for every service method from proto files",
+			Flags: append(protoFlags(),
+				cli.StringFlag{
+					Name:  "out,o",
+					Usage: "output directory, one file per service is created; mocks are written to stdout if not set",
+				},
+				cli.StringFlag{
+					Name:  "format,f",
+					Value: "yaml",
+					Usage: "output format: yaml or json",
+				},
+			),
+			Action: newGenerateCmd(),
+		},
+		{
+			Name:  "validate",
+			Usage: "validate mocks against proto files and report all problems found",
+			Flags: append(protoFlags(),
+				cli.StringSliceFlag{
+					Name:  "mocks",
+					Usage: "mocks directory, file, glob or - for stdin, repeatable",
+				},
+				cli.StringSliceFlag{
+					Name:  "mocks-include",
+					Usage: "validate only the mock files matching the glob",
+				},
+				cli.StringSliceFlag{
+					Name:  "mocks-exclude",
+					Usage: "skip mock files and directories matching the glob",
+				},
+				cli.BoolFlag{
+					Name:  "strict",
+					Usage: "treat warnings as errors",
+				},
+			),
+			Action: newValidateCmd(),
+		},
+	}
+
+	app.Action = newMockCmd()
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serverFlags are the flags of the mock server, every flag can be set by DITTO_ environment variable
+func serverFlags() []cli.Flag {
+	return append(protoFlags(),
+		cli.StringFlag{
+			Name:   "config,c",
+			EnvVar: "DITTO_CONFIG",
+			Usage:  "yaml or json file with server settings, flags and environment variables take precedence",
+		},
 		cli.StringSliceFlag{
-			Name:     "proto",
-			Required: true,
-			Usage:    "proto files input directory",
+			Name:   "mocks",
+			EnvVar: "DITTO_MOCKS",
+			Usage:  "mocks directory, file, glob or - for stdin, repeatable, mocks of the later sources take precedence",
 		},
 		cli.StringSliceFlag{
-			Name:     "protoimports",
-			Required: false,
-			Usage:    "additional directories to search for dependencies",
+			Name:   "mocks-include",
+			EnvVar: "DITTO_MOCKS_INCLUDE",
+			Usage:  "load only the mock files matching the glob, it applies to files found in directories and by globs",
 		},
-		cli.StringFlag{
-			Name:     "mocks",
-			Required: true,
-			Usage:    "directory containing mocks in json format",
+		cli.StringSliceFlag{
+			Name:   "mocks-exclude",
+			EnvVar: "DITTO_MOCKS_EXCLUDE",
+			Usage:  "skip mock files and directories matching the glob",
 		},
 		cli.StringFlag{
 			Name:     "loglevel,l",
+			EnvVar:   "DITTO_LOGLEVEL",
 			Required: false,
 			Value:    "debug",
 			Usage:    "log level",
 		},
 		cli.IntFlag{
 			Name:     "port,p",
+			EnvVar:   "DITTO_PORT",
 			Required: false,
-			Usage:    "grpc server port",
+			Usage:    "grpc server port, it's not used together with --listen unless it's set explicitly",
 			Value:    51000,
 		},
-	}
+		cli.StringSliceFlag{
+			Name:   "listen",
+			EnvVar: "DITTO_LISTEN",
+			Usage:  "address to serve on: host:port, :port or unix:///path.sock, port 0 picks a free port",
+		},
+		cli.StringSliceFlag{
+			Name:   "service-listen",
+			EnvVar: "DITTO_SERVICE_LISTEN",
+			Usage:  "serve only the matching services on their own address: SERVICE[,SERVICE...]=ADDR, services are globs like users.*",
+		},
+		cli.StringFlag{
+			Name:   "port-file",
+			EnvVar: "DITTO_PORT_FILE",
+			Usage:  "write addresses of the listeners to the file one per line once the server is listening",
+		},
+		cli.StringFlag{
+			Name:   "unmatched",
+			EnvVar: "DITTO_UNMATCHED",
+			Usage:  "response for requests that don't match any mock: status[:CODE[:message]], body[:json], generate or proxy:address",
+			Value:  "status:UNIMPLEMENTED",
+		},
+		cli.StringSliceFlag{
+			Name:   "unmatched-service",
+			EnvVar: "DITTO_UNMATCHED_SERVICE",
+			Usage:  "per service or method unmatched response, e.g. greet.Greeter=status:NOT_FOUND",
+		},
+		cli.IntFlag{
+			Name:   "metrics-port",
+			EnvVar: "DITTO_METRICS_PORT",
+			Usage:  "http port of prometheus /metrics endpoint, metrics are disabled if not set",
+		},
+		cli.StringFlag{
+			Name:   "trace-exporter",
+			EnvVar: "DITTO_TRACE_EXPORTER",
+			Usage:  "export OpenTelemetry traces: otlp, stdout or file:path, tracing is disabled if not set",
+		},
+		cli.StringFlag{
+			Name:   "trace-endpoint",
+			EnvVar: "DITTO_TRACE_ENDPOINT",
+			Usage:  "OTLP collector grpc endpoint",
+			Value:  "localhost:4317",
+		},
+		cli.BoolFlag{
+			Name:   "trace-echo",
+			EnvVar: "DITTO_TRACE_ECHO",
+			Usage:  "send trace context of the call back in traceparent response header",
+		},
+		cli.StringFlag{
+			Name:   "access-log",
+			EnvVar: "DITTO_ACCESS_LOG",
+			Usage:  "write mock calls as json lines to stdout, stderr or a file",
+		},
+		cli.StringSliceFlag{
+			Name:   "access-log-redact",
+			EnvVar: "DITTO_ACCESS_LOG_REDACT",
+			Usage:  "field name or dotted path from the message root and metadata key to redact in access log",
+		},
+		cli.IntFlag{
+			Name:   "access-log-max-body",
+			EnvVar: "DITTO_ACCESS_LOG_MAX_BODY",
+			Usage:  "size limit of request and response bodies in access log, 0 disables the limit",
+			Value:  64 * 1024,
+		},
+		cli.StringFlag{
+			Name:   "faults",
+			EnvVar: "DITTO_FAULTS",
+			Usage:  "json or yaml file with fault rules injecting errors, latency and connection resets",
+		},
+		cli.Int64Flag{
+			Name:   "seed",
+			EnvVar: "DITTO_SEED",
+			Usage:  "random seed of fault injection and weighted responses, random if not set",
+		},
+		cli.StringFlag{
+			Name:   "log-format",
+			EnvVar: "DITTO_LOG_FORMAT",
+			Usage:  "log format: logfmt or json",
+			Value:  "logfmt",
+		},
+		cli.StringFlag{
+			Name:   "tls-cert",
+			EnvVar: "DITTO_TLS_CERT",
+			Usage:  "PEM certificate file, the server uses plaintext if not set",
+		},
+		cli.StringFlag{
+			Name:   "tls-key",
+			EnvVar: "DITTO_TLS_KEY",
+			Usage:  "PEM private key file of the certificate",
+		},
+		cli.StringFlag{
+			Name:   "tls-client-ca",
+			EnvVar: "DITTO_TLS_CLIENT_CA",
+			Usage:  "PEM file with CA certificates to verify client certificates, client certificates are required if set",
+		},
+		cli.StringSliceFlag{
+			Name:   "admin-listen",
+			EnvVar: "DITTO_ADMIN_LISTEN",
+			Usage:  "serve admin api only on these addresses instead of the mock listeners",
+		},
+	)
+}
 
-	app.Action = newMockCmd()
-	err := app.Run(os.Args)
-	if err != nil {
-		log.Fatal(err)
+// protoFlags are the flags required to parse proto files,
+// required flags are checked by commands since root flags are also parsed for subcommands
+func protoFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringSliceFlag{
+			Name:   "proto",
+			EnvVar: "DITTO_PROTO",
+			Usage:  "proto files input directory",
+		},
+		cli.StringSliceFlag{
+			Name:     "protoimports",
+			EnvVar:   "DITTO_PROTOIMPORTS",
+			Required: false,
+			Usage:    "additional directories to search for dependencies",
+		},
 	}
 }
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/vadimi/grpc-ditto/internal/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// outcomes of mock calls reported in metrics
+const (
+	outcomeMatched   = "matched"
+	outcomeUnmatched = "unmatched"
+	outcomeFault     = "fault"
+)
+
+// callInfo is filled by the mock handlers and reported by metrics and access log interceptors
+type callInfo struct {
+	mockID  string
+	outcome string
+	// matchDuration is the time spent matching the request, it's zero if matching didn't happen
+	matchDuration time.Duration
+	// request is json of the request, client streaming requests are arrays of messages
+	request []byte
+	// stage of the mock handler the call is at
+	stage string
+}
+
+type callInfoKey struct{}
+
+// withCallInfo adds call info to the context, interceptors share the info of the call
+func withCallInfo(ctx context.Context) (context.Context, *callInfo) {
+	if info := callInfoFromContext(ctx); info != nil {
+		return ctx, info
+	}
+
+	info := &callInfo{}
+	return context.WithValue(ctx, callInfoKey{}, info), info
+}
+
+// callInfoFromContext returns call info of the current call, nil is returned
+// for servers without the metrics interceptor
+func callInfoFromContext(ctx context.Context) *callInfo {
+	info, _ := ctx.Value(callInfoKey{}).(*callInfo)
+	return info
+}
+
+func (c *callInfo) setOutcome(outcome, mockID string) {
+	if c == nil {
+		return
+	}
+	c.outcome = outcome
+	c.mockID = mockID
+}
+
+func (c *callInfo) setMatchDuration(d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.matchDuration = d
+}
+
+func (c *callInfo) setStage(stage string) {
+	if c == nil {
+		return
+	}
+	c.stage = stage
+}
+
+func (c *callInfo) setRequest(js []byte) {
+	if c == nil {
+		return
+	}
+	c.request = js
+}
+
+// contextStream replaces the stream context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+type serverMetrics struct {
+	registry *prometheus.Registry
+	// isMock tells if the method is served by mocks, admin api and reflection calls are not tracked
+	isMock func(method string) bool
+
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	matchDuration *prometheus.HistogramVec
+	activeStreams *prometheus.GaugeVec
+}
+
+func newServerMetrics(isMock func(method string) bool) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		isMock:   isMock,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_ditto_requests_total",
+			Help: "Number of mock calls by method, matched mock, status code and outcome.",
+		}, []string{"method", "mock_id", "code", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_ditto_request_duration_seconds",
+			Help:    "Duration of mock calls including streaming.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "code", "outcome"}),
+		matchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_ditto_match_duration_seconds",
+			Help:    "Time spent matching requests against mocks.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, []string{"method"}),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_ditto_active_streams",
+			Help: "Number of open streaming mock calls.",
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.matchDuration,
+		m.activeStreams,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// streamInterceptor records metrics of the calls handled by mocks,
+// in grpc-go all mock methods are implemented as streams
+func (m *serverMetrics) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, call := withCallInfo(ss.Context())
+
+	streaming := info.IsClientStream || info.IsServerStream
+	if streaming && m.isMock(info.FullMethod) {
+		m.activeStreams.WithLabelValues(info.FullMethod).Inc()
+		defer m.activeStreams.WithLabelValues(info.FullMethod).Dec()
+	}
+
+	start := time.Now()
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+	// calls that didn't reach mock handlers are not reported
+	if call.outcome == "" {
+		return err
+	}
+
+	code := status.Code(err).String()
+	m.requests.WithLabelValues(info.FullMethod, call.mockID, code, call.outcome).Inc()
+	m.duration.WithLabelValues(info.FullMethod, code, call.outcome).Observe(time.Since(start).Seconds())
+	if call.matchDuration > 0 {
+		m.matchDuration.WithLabelValues(info.FullMethod).Observe(call.matchDuration.Seconds())
+	}
+
+	return err
+}
+
+// serveMetrics exposes /metrics endpoint on the port, the returned server has to be closed on stop
+func serveMetrics(port int, m *serverMetrics, log logger.Logger) (*http.Server, error) {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		log.Infow("start metrics server", "addr", lis.Addr().String())
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorw("metrics server", "err", err)
+		}
+	}()
+
+	return srv, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/testdata/greet"
+	"google.golang.org/grpc"
+)
+
+func TestMockServerMetrics(t *testing.T) {
+	log := logger.NewLogger()
+
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+		logger: log,
+		done:   make(chan struct{}),
+	}
+
+	bob := greetMock()
+	bob.ID = "bob"
+	watch, err := dittomock.FromProto(&api.DittoMock{
+		Id: "watch",
+		Request: &api.DittoRequest{
+			Method: "/ditto.example.HelloService/Hello",
+			BodyPatterns: []*api.DittoBodyPattern{
+				{Pattern: &api.DittoBodyPattern_MatchesJsonpath{MatchesJsonpath: &api.JSONPathPattern{
+					Expression: "$.name",
+					Operator:   &api.JSONPathPattern_Eq{Eq: "watch"},
+				}}},
+			},
+		},
+		Response: []*api.DittoResponse{{Response: &api.DittoResponse_BodyTemplate{BodyTemplate: `name: "hello"`}}},
+		Stream:   &api.StreamOptions{KeepOpen: true},
+	})
+	require.NoError(t, err)
+
+	s.matcher, err = dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{bob, watch}), dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	metrics := newServerMetrics(func(method string) bool { return s.findMethodByName(method) != nil })
+	server := grpc.NewServer(grpc.ChainStreamInterceptor(metrics.streamInterceptor))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+	defer stopTestServer(server)
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	client := greet.NewGreeterClient(cc)
+	_, err = client.SayHello(context.Background(), &greet.HelloRequest{Name: "Bob"})
+	require.NoError(t, err)
+	_, err = client.SayHello(context.Background(), &greet.HelloRequest{Name: "Alice"})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := helloStream(ctx, t, addr, "watch")
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, err := io.ReadAll(rec.Result().Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	out := scrape()
+	assert.Contains(t, out, `grpc_ditto_requests_total{code="OK",method="/greet.Greeter/SayHello",mock_id="bob",outcome="matched"} 1`)
+	assert.Contains(t, out, `grpc_ditto_requests_total{code="Unimplemented",method="/greet.Greeter/SayHello",mock_id="",outcome="unmatched"} 1`)
+	assert.Contains(t, out, `grpc_ditto_match_duration_seconds_count{method="/greet.Greeter/SayHello"} 2`)
+	assert.Contains(t, out, `grpc_ditto_request_duration_seconds_count{code="OK",method="/greet.Greeter/SayHello",outcome="matched"} 1`)
+	assert.Contains(t, out, `grpc_ditto_active_streams{method="/ditto.example.HelloService/Hello"} 1`)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		out := scrape()
+		return strings.Contains(out, `grpc_ditto_active_streams{method="/ditto.example.HelloService/Hello"} 0`) &&
+			strings.Contains(out, `grpc_ditto_requests_total{code="Canceled",method="/ditto.example.HelloService/Hello",mock_id="watch",outcome="matched"} 1`)
+	}, time.Second, 10*time.Millisecond)
+}
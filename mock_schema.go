@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+)
+
+// filterFieldRegexp finds field references like @.name or @.user.name in JSONPath filters
+var filterFieldRegexp = regexp.MustCompile(`@((?:\.[A-Za-z_][A-Za-z0-9_]*)+)`)
+
+// mockWarning is a problem that doesn't make a mock invalid,
+// but most likely prevents it from ever matching a request
+type mockWarning struct {
+	err error
+}
+
+func (w *mockWarning) Error() string {
+	return w.err.Error()
+}
+
+func (w *mockWarning) Unwrap() error {
+	return w.err
+}
+
+func warnf(format string, args ...interface{}) error {
+	return &mockWarning{err: fmt.Errorf(format, args...)}
+}
+
+func isWarning(err error) bool {
+	var w *mockWarning
+	return errors.As(err, &w)
+}
+
+// checkJSONPathFields verifies that fields referenced by the JSONPath exist in the method input message.
+// Parts of the path that can't be resolved statically like recursive descent or wildcards stop the check.
+// Fields used in filter expressions are checked too, but reported as warnings.
+// stream is true if the path is applied to the array of client streaming messages.
+func checkJSONPathFields(method *desc.MethodDescriptor, tokens []string, stream bool) []error {
+	node := pathNode{msg: method.GetInputType(), list: stream}
+	path := "$"
+
+	var errs []error
+	for _, tok := range tokens[1:] {
+		if tok == ".." || strings.HasPrefix(tok, "(") {
+			return errs
+		}
+
+		if strings.HasPrefix(tok, "?(") && node.list && node.msg != nil {
+			errs = append(errs, checkFilterFields(node.msg, tok)...)
+		}
+
+		next, err := node.child(tok)
+		if err != nil {
+			return append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+		if next == nil {
+			return errs
+		}
+
+		node = *next
+		path += "." + strings.Trim(tok, "'")
+	}
+
+	return errs
+}
+
+// jsonPathField returns the field selected by the JSONPath,
+// it's nil if the path can't be resolved statically or selects a list
+func jsonPathField(method *desc.MethodDescriptor, tokens []string, stream bool) *desc.FieldDescriptor {
+	msg := method.GetInputType()
+	list := stream
+
+	var field *desc.FieldDescriptor
+	for _, tok := range tokens[1:] {
+		if list {
+			if _, err := strconv.Atoi(tok); err != nil {
+				return nil
+			}
+			list = false
+			continue
+		}
+		if msg == nil || isWellKnown(msg) {
+			return nil
+		}
+
+		field = msg.FindFieldByName(strings.Trim(tok, "'"))
+		if field == nil || field.IsMap() {
+			return nil
+		}
+		msg = field.GetMessageType()
+		list = field.IsRepeated()
+	}
+
+	if list {
+		return nil
+	}
+	return field
+}
+
+// checkFilterFields checks @.field references of a filter expression applied to a list of messages
+func checkFilterFields(msg *desc.MessageDescriptor, filter string) []error {
+	var errs []error
+	for _, m := range filterFieldRegexp.FindAllStringSubmatch(filter, -1) {
+		node := &pathNode{msg: msg}
+		for _, name := range strings.Split(strings.TrimPrefix(m[1], "."), ".") {
+			next, err := node.child(name)
+			if err != nil {
+				errs = append(errs, warnf("filter %s: %w", filter, err))
+				break
+			}
+			if next == nil {
+				break
+			}
+			node = next
+		}
+	}
+	return errs
+}
+
+// checkJSONFields verifies that equal_to_json value has the shape of the method input,
+// client streaming input is an array of messages if stream is true. Missing fields are fine for partial comparison.
+func checkJSONFields(method *desc.MethodDescriptor, val interface{}, partial, stream bool) []error {
+	if !stream {
+		return checkMessageJSON(method.GetInputType(), val, "$", partial)
+	}
+
+	items, ok := val.([]interface{})
+	if !ok {
+		return []error{errors.New("$: client streaming input is an array of messages")}
+	}
+
+	var errs []error
+	for i, item := range items {
+		errs = append(errs, checkMessageJSON(method.GetInputType(), item, fmt.Sprintf("$[%d]", i), partial)...)
+	}
+	return errs
+}
+
+func checkMessageJSON(msg *desc.MessageDescriptor, val interface{}, path string, partial bool) []error {
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("%s: object expected for %s", path, msg.GetFullyQualifiedName())}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(obj) {
+		fd := msg.FindFieldByName(name)
+		if fd == nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, unknownFieldErr(msg, name)))
+			continue
+		}
+
+		errs = append(errs, checkFieldJSON(fd, obj[name], path+"."+name, partial)...)
+	}
+
+	if partial {
+		return errs
+	}
+
+	// requests are rendered with default values, so a message without all its fields never equals them
+	var missing []string
+	for _, fd := range msg.GetFields() {
+		if fd.GetOneOf() != nil {
+			continue
+		}
+		if _, ok := obj[fd.GetName()]; !ok {
+			missing = append(missing, fd.GetName())
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, warnf("%s: fields %s are missing, requests always contain default values so equal_to_json never matches", path, strings.Join(missing, ", ")))
+	}
+
+	return errs
+}
+
+func checkFieldJSON(fd *desc.FieldDescriptor, val interface{}, path string, partial bool) []error {
+	if val == nil {
+		return nil
+	}
+
+	if fd.IsMap() {
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: object expected for map field", path)}
+		}
+		var errs []error
+		for _, key := range sortedKeys(obj) {
+			errs = append(errs, checkSingularJSON(fd.GetMapValueType(), obj[key], path+"."+key, partial)...)
+		}
+		return errs
+	}
+
+	if fd.IsRepeated() {
+		items, ok := val.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: array expected for repeated field", path)}
+		}
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, checkSingularJSON(fd, item, fmt.Sprintf("%s[%d]", path, i), partial)...)
+		}
+		return errs
+	}
+
+	return checkSingularJSON(fd, val, path, partial)
+}
+
+func checkSingularJSON(fd *desc.FieldDescriptor, val interface{}, path string, partial bool) []error {
+	msg := fd.GetMessageType()
+	if msg == nil || val == nil || isWellKnown(msg) {
+		return nil
+	}
+
+	return checkMessageJSON(msg, val, path, partial)
+}
+
+// pathNode represents the type of the value a JSONPath points to
+type pathNode struct {
+	// msg is nil for scalar values
+	msg *desc.MessageDescriptor
+	// list is true for repeated fields and for client streaming input
+	list bool
+	// mapValue is set if the node is a map
+	mapValue *desc.FieldDescriptor
+}
+
+// child resolves JSONPath token, nil result means the rest of the path can't be checked
+func (n pathNode) child(tok string) (*pathNode, error) {
+	switch {
+	case n.list:
+		if tok == "length" {
+			return nil, nil
+		}
+		if !isIndexToken(tok) {
+			return nil, fmt.Errorf("array index expected, got %q", tok)
+		}
+		return &pathNode{msg: n.msg, mapValue: n.mapValue}, nil
+	case n.mapValue != nil:
+		return fieldNode(n.mapValue), nil
+	case n.msg == nil:
+		if tok == "length" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scalar value doesn't have %q field", tok)
+	case tok == "*":
+		return nil, nil
+	}
+
+	name := strings.Trim(tok, "'")
+	fd := n.msg.FindFieldByName(name)
+	if fd == nil {
+		return nil, unknownFieldErr(n.msg, name)
+	}
+
+	return fieldNode(fd), nil
+}
+
+func fieldNode(fd *desc.FieldDescriptor) *pathNode {
+	if fd.IsMap() {
+		return &pathNode{mapValue: fd.GetMapValueType()}
+	}
+
+	msg := fd.GetMessageType()
+	// well known types have special json representation, e.g. timestamps are strings
+	if msg != nil && isWellKnown(msg) {
+		return nil
+	}
+
+	return &pathNode{msg: msg, list: fd.IsRepeated()}
+}
+
+// unknownFieldErr suggests the original field name if json (camelCase) name is used,
+// requests are matched using original proto field names
+func unknownFieldErr(msg *desc.MessageDescriptor, name string) error {
+	for _, fd := range msg.GetFields() {
+		if fd.GetJSONName() == name {
+			return fmt.Errorf("field %q not found in %s, use original field name %q", name, msg.GetFullyQualifiedName(), fd.GetName())
+		}
+	}
+	return fmt.Errorf("field %q not found in %s", name, msg.GetFullyQualifiedName())
+}
+
+func isWellKnown(msg *desc.MessageDescriptor) bool {
+	return msg.GetFile().GetPackage() == "google.protobuf"
+}
+
+// isIndexToken returns true for array indexes, slices, wildcards and filters
+func isIndexToken(tok string) bool {
+	if tok == "*" || strings.HasPrefix(tok, "?(") || strings.ContainsAny(tok, ":,") {
+		return true
+	}
+	_, err := strconv.Atoi(tok)
+	return err == nil
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
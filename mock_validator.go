@@ -1,54 +1,376 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/dynamic"
+	"github.com/spyzhov/ajson"
 	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 type mockValidator struct {
 	findMethodFunc func(methodName string) *desc.MethodDescriptor
+	// logger is optional, it's used to report warnings
+	logger logger.Logger
 }
 
 // Validate that all methods in mocks have protos loaded in memory.
-// also verifies that mock responses can be successfully marshalled into method response message
+// also verifies that mock responses can be successfully marshalled into method response message.
+// All invalid mocks are reported, not just the first one.
 func (v *mockValidator) Validate(mocks map[string][]dittomock.DittoMock) error {
-	for methodName, mocks := range mocks {
-		method := v.findMethodFunc(methodName)
-		if method == nil {
-			return fmt.Errorf("method %s not found in registered proto files", methodName)
-		}
+	methods := make([]string, 0, len(mocks))
+	for methodName := range mocks {
+		methods = append(methods, methodName)
+	}
+	sort.Strings(methods)
+
+	var errs []error
+	for _, methodName := range methods {
+		for i, m := range mocks[methodName] {
+			if m.Source.File == "" {
+				m.Source.Index = i
+			}
 
-		for i, m := range mocks {
-			err := v.ValidateMock(m)
-			if err != nil {
-				return fmt.Errorf("invalid mock [%d]: %w", i, err)
+			for _, err := range v.ValidateMocks([]dittomock.DittoMock{m}) {
+				if isWarning(err) {
+					v.warn(err)
+					continue
+				}
+				errs = append(errs, fmt.Errorf("invalid mock %w", err))
 			}
 		}
 	}
-	return nil
+
+	return errors.Join(errs...)
+}
+
+// ValidateMocks returns all problems found in mocks including warnings,
+// every problem is reported as *dittomock.MockError pointing to the mock source
+func (v *mockValidator) ValidateMocks(mocks []dittomock.DittoMock) []error {
+	var errs []error
+	for _, m := range mocks {
+		for _, err := range v.mockProblems(m) {
+			errs = append(errs, &dittomock.MockError{Source: m.Source, Err: err})
+		}
+	}
+	return errs
 }
 
+// ValidateMock returns an error if the mock is invalid, warnings are only logged
 func (v *mockValidator) ValidateMock(mock dittomock.DittoMock) error {
+	var errs []error
+	for _, err := range v.mockProblems(mock) {
+		if isWarning(err) {
+			v.warn(err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (v *mockValidator) warn(err error) {
+	if v.logger != nil {
+		v.logger.Warnw("mock validation", "warning", err.Error())
+	}
+}
+
+func (v *mockValidator) mockProblems(mock dittomock.DittoMock) []error {
+	if mock.Request == nil {
+		return []error{errors.New("mock request is required")}
+	}
+
+	// the server refused to start with such mocks before as the method can't be found,
+	// the error just makes the reason clear
+	if mock.Request.Method == "" {
+		return []error{errors.New("request method is required")}
+	}
+
 	methodName := mock.Request.Method
 	method := v.findMethodFunc(methodName)
 	if method == nil {
-		return fmt.Errorf("method %s not found in registered proto files", methodName)
+		return []error{fmt.Errorf("method %s not found in registered proto files", methodName)}
 	}
 
-	for _, resp := range mock.Response {
+	var errs []error
+	for i, pattern := range mock.Request.BodyPatterns {
+		for _, err := range v.patternProblems(method, pattern, method.IsClientStreaming()) {
+			errs = append(errs, fmt.Errorf("body_patterns[%d]: %w", i, err))
+		}
+	}
+
+	if mock.Stream != nil && mock.Stream.KeepOpen && !method.IsServerStreaming() {
+		errs = append(errs, errors.New("stream options require server streaming method"))
+	}
+
+	errs = append(errs, responseProblems(method, methodName, mock.Response)...)
+	for i, alt := range mock.Responses {
+		for _, err := range responseProblems(method, methodName, alt.Response) {
+			errs = append(errs, fmt.Errorf("responses[%d]: %w", i, err))
+		}
+	}
+
+	return errs
+}
+
+func responseProblems(method *desc.MethodDescriptor, methodName string, responses []*dittomock.DittoResponse) []error {
+	var errs []error
+	for i, resp := range responses {
 		if resp.Status != nil {
+			if resp.Status.Code > codes.Unauthenticated {
+				errs = append(errs, fmt.Errorf("response[%d]: unknown status code %d", i, resp.Status.Code))
+			}
+			continue
+		}
+
+		if resp.Generator != nil {
+			errs = append(errs, generatorProblems(method, resp.Generator, i)...)
 			continue
 		}
 
 		output := dynamic.NewMessage(method.GetOutputType())
 		err := output.UnmarshalJSON(resp.Body)
 		if err != nil {
-			return fmt.Errorf("invalid response for method %s: %w", methodName, err)
+			errs = append(errs, fmt.Errorf("response[%d]: invalid response for method %s: %w", i, methodName, err))
+		}
+	}
+
+	return errs
+}
+
+// generatorProblems validates generated response, message bodies depend on the request,
+// so they are checked only when responses are sent
+func generatorProblems(method *desc.MethodDescriptor, g *dittomock.ResponseGenerator, index int) []error {
+	var errs []error
+	if !method.IsServerStreaming() {
+		errs = append(errs, fmt.Errorf("response[%d]: generate requires server streaming method", index))
+	}
+
+	if g.Foreach != "" {
+		tokens, err := ajson.ParseJSONPath(g.Foreach)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("response[%d]: invalid foreach %q: %w", index, g.Foreach, err))
+		} else {
+			for _, err := range checkJSONPathFields(method, tokens, method.IsClientStreaming()) {
+				errs = append(errs, fmt.Errorf("response[%d]: foreach %q: %w", index, g.Foreach, err))
+			}
 		}
 	}
 
+	return errs
+}
+
+// patternProblems validates the pattern, stream is true if the pattern is matched against
+// all messages of client streaming request and false if it's matched against a single message
+func (v *mockValidator) patternProblems(method *desc.MethodDescriptor, pattern dittomock.DittoBodyPattern, stream bool) []error {
+	var errs []error
+
+	if len(pattern.EqualToJson) > 0 {
+		var val interface{}
+		if err := json.Unmarshal(pattern.EqualToJson, &val); err != nil {
+			errs = append(errs, fmt.Errorf("invalid equal_to_json: %w", err))
+		} else {
+			for _, err := range checkJSONFields(method, val, pattern.IgnoreExtraFields, stream) {
+				errs = append(errs, fmt.Errorf("equal_to_json %w", err))
+			}
+		}
+	}
+
+	if pattern.MatchesCEL != "" {
+		if err := dittomock.CheckCEL(method, pattern.MatchesCEL, stream); err != nil {
+			errs = append(errs, fmt.Errorf("invalid matches_cel %q: %w", pattern.MatchesCEL, err))
+		}
+	}
+
+	if pattern.MatchesField != nil {
+		if err := dittomock.CheckFieldPattern(method, pattern.MatchesField, stream); err != nil {
+			errs = append(errs, fmt.Errorf("invalid matches_field: %w", err))
+		}
+	}
+
+	if len(pattern.EqualToJson) == 0 && (pattern.IgnoreExtraFields || pattern.IgnoreArrayOrder) {
+		errs = append(errs, errors.New("ignore_extra_fields and ignore_array_order require equal_to_json"))
+	}
+
+	if jp := pattern.MatchesJsonPath; jp != nil {
+		tokens, err := ajson.ParseJSONPath(jp.Expression)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid jsonpath %q: %w", jp.Expression, err))
+		} else {
+			for _, err := range checkJSONPathFields(method, tokens, stream) {
+				errs = append(errs, fmt.Errorf("jsonpath %q: %w", jp.Expression, err))
+			}
+			errs = append(errs, operandProblems(jsonPathField(method, tokens, stream), jp.JSONPathMessage)...)
+		}
+
+		if jp.Regexp != "" {
+			if _, err := regexp.Compile(jp.Regexp); err != nil {
+				errs = append(errs, fmt.Errorf("invalid regexp %q: %w", jp.Regexp, err))
+			}
+		}
+
+		if jp.Between != nil {
+			if jp.Between.Min == "" || jp.Between.Max == "" {
+				errs = append(errs, errors.New("between requires min and max"))
+			} else if err := rangeProblem(jp.Between); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if jp.In != nil && len(jp.In) == 0 {
+			errs = append(errs, errors.New("in requires at least one value"))
+		}
+	}
+
+	for i, sub := range pattern.AnyOf {
+		errs = append(errs, v.nestedPatternProblems(method, fmt.Sprintf("any_of[%d]", i), sub, stream)...)
+	}
+
+	for i, sub := range pattern.AllOf {
+		errs = append(errs, v.nestedPatternProblems(method, fmt.Sprintf("all_of[%d]", i), sub, stream)...)
+	}
+
+	if pattern.Not != nil {
+		errs = append(errs, v.nestedPatternProblems(method, "not", *pattern.Not, stream)...)
+	}
+
+	errs = append(errs, v.messagePatternProblems(method, pattern, stream)...)
+
+	return errs
+}
+
+// messagePatternProblems validates patterns that match messages of client streaming request
+func (v *mockValidator) messagePatternProblems(method *desc.MethodDescriptor, pattern dittomock.DittoBodyPattern, stream bool) []error {
+	if pattern.AnyMessage == nil && pattern.EveryMessage == nil && pattern.MessageCount == nil && len(pattern.Sequence) == 0 {
+		return nil
+	}
+
+	if !stream {
+		return []error{errors.New("any_message, every_message, message_count and sequence require client streaming request")}
+	}
+
+	var errs []error
+	if pattern.AnyMessage != nil {
+		errs = append(errs, v.nestedPatternProblems(method, "any_message", *pattern.AnyMessage, false)...)
+	}
+
+	if pattern.EveryMessage != nil {
+		errs = append(errs, v.nestedPatternProblems(method, "every_message", *pattern.EveryMessage, false)...)
+	}
+
+	if c := pattern.MessageCount; c != nil && c.Max != nil && c.Min > *c.Max {
+		errs = append(errs, fmt.Errorf("message_count min %d is greater than max %d", c.Min, *c.Max))
+	}
+
+	for i, step := range pattern.Sequence {
+		name := fmt.Sprintf("sequence[%d]", i)
+		if step.Message == nil && !step.Any && !step.Skip {
+			errs = append(errs, fmt.Errorf("%s: step requires message, any or skip", name))
+			continue
+		}
+		if step.Message != nil {
+			errs = append(errs, v.nestedPatternProblems(method, name, *step.Message, false)...)
+		}
+	}
+
+	return errs
+}
+
+// nestedPatternProblems validates a pattern of combinators or message patterns,
+// empty nested pattern matches any request, so it's most likely a mistake
+func (v *mockValidator) nestedPatternProblems(method *desc.MethodDescriptor, name string, pattern dittomock.DittoBodyPattern, stream bool) []error {
+	if pattern.Empty() {
+		return []error{fmt.Errorf("%s: pattern is empty", name)}
+	}
+
+	var errs []error
+	for _, err := range v.patternProblems(method, pattern, stream) {
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	return errs
+}
+
+// rangeProblem reports bounds that can't be compared or min greater than max,
+// bounds are compared the same way as request values
+func rangeProblem(r *dittomock.Range) error {
+	c, err := dittomock.CompareValues(r.Min, r.Max)
+	if err != nil {
+		return fmt.Errorf("between: %w", err)
+	}
+	if c > 0 {
+		return fmt.Errorf("between min %q is greater than max %q", r.Min, r.Max)
+	}
 	return nil
 }
+
+// operandProblems checks that comparison operands can be compared with the values of the field,
+// values of string and enum fields are compared as the operands, so they are not checked
+func operandProblems(fd *desc.FieldDescriptor, jp dittomock.JSONPathMessage) []error {
+	if fd == nil {
+		return nil
+	}
+
+	operands := map[string]string{"gt": jp.Gt, "gte": jp.Gte, "lt": jp.Lt, "lte": jp.Lte}
+	if jp.Between != nil {
+		operands["between min"] = jp.Between.Min
+		operands["between max"] = jp.Between.Max
+	}
+
+	var errs []error
+	for _, name := range []string{"gt", "gte", "lt", "lte", "between min", "between max"} {
+		val := operands[name]
+		if val == "" {
+			continue
+		}
+		if err := compareFieldValue(fd, val); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// compareFieldValue compares the operand with a sample value of the field
+func compareFieldValue(fd *desc.FieldDescriptor, val string) error {
+	var sample string
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return fmt.Errorf("%q is not a boolean", val)
+		}
+		return nil
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		switch fd.GetMessageType().GetFullyQualifiedName() {
+		case "google.protobuf.Timestamp":
+			sample = "1970-01-01T00:00:00Z"
+		case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int32Value",
+			"google.protobuf.UInt32Value", "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+			sample = "0"
+		case "google.protobuf.BoolValue":
+			if _, err := strconv.ParseBool(val); err != nil {
+				return fmt.Errorf("%q is not a boolean", val)
+			}
+			return nil
+		case "google.protobuf.StringValue", "google.protobuf.BytesValue", "google.protobuf.Duration":
+			return nil
+		default:
+			return fmt.Errorf("%s values can't be compared", fd.GetMessageType().GetFullyQualifiedName())
+		}
+	default:
+		sample = "0"
+	}
+
+	_, err := dittomock.CompareValues(sample, val)
+	return err
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/spyzhov/ajson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
 	"github.com/vadimi/grpc-ditto/internal/dittomock"
 	"github.com/vadimi/grpc-ditto/internal/logger"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func TestMockServiceValidateSuccess(t *testing.T) {
@@ -87,6 +92,25 @@ func TestMockServiceValidateFailureInvalidMethod(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMockServiceValidateFailureMissingMethod(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	mock := greetMock()
+	mock.Request.Method = ""
+
+	err = validator.ValidateMock(mock)
+	assert.ErrorContains(t, err, "request method is required")
+}
+
 func TestMockServiceValidateFailureInvalidResponse(t *testing.T) {
 	log := logger.NewLogger()
 	invalidMock := dittomock.DittoMock{
@@ -135,3 +159,556 @@ func TestMockServiceValidateFailureInvalidResponse(t *testing.T) {
 	err = validator.Validate(requestMatcher.Mocks())
 	assert.Error(t, err)
 }
+
+func TestMockServiceValidateMocksReportsAllProblems(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	invalidPatterns := greetMock()
+	invalidPatterns.Source = dittomock.MockSource{File: "mocks.yaml", Index: 0}
+	invalidPatterns.Request.BodyPatterns = []dittomock.DittoBodyPattern{
+		{
+			MatchesJsonPath: &dittomock.JSONPathWrapper{
+				JSONPathMessage: dittomock.JSONPathMessage{
+					Expression: "$.nmae",
+					Regexp:     "((",
+				},
+			},
+		},
+		{
+			MatchesJsonPath: &dittomock.JSONPathWrapper{
+				JSONPathMessage: dittomock.JSONPathMessage{
+					Expression: "$.name.first",
+				},
+			},
+		},
+	}
+
+	unknownMethod := greetMock()
+	unknownMethod.Source = dittomock.MockSource{File: "mocks.yaml", Index: 1}
+	unknownMethod.Request.Method = "/greet.Greeter/SayBye"
+
+	errs := validator.ValidateMocks([]dittomock.DittoMock{greetMock(), invalidPatterns, unknownMethod})
+	require.Len(t, errs, 4)
+
+	var mockErr *dittomock.MockError
+	require.ErrorAs(t, errs[0], &mockErr)
+	assert.Equal(t, "mocks.yaml", mockErr.Source.File)
+	assert.Contains(t, errs[0].Error(), `field "nmae" not found`)
+	assert.Contains(t, errs[1].Error(), "invalid regexp")
+	assert.Contains(t, errs[2].Error(), "body_patterns[1]")
+	assert.Contains(t, errs[3].Error(), "mocks.yaml [1]")
+}
+
+func TestCheckJSONPathFields(t *testing.T) {
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{helloDescr},
+	}
+
+	tests := []struct {
+		method string
+		expr   string
+		valid  bool
+	}{
+		{"/ditto.example.HelloService/Hello", "$.name", true},
+		{"/ditto.example.HelloService/Hello", "$['name']", true},
+		{"/ditto.example.HelloService/Hello", "$..name", true},
+		{"/ditto.example.HelloService/Hello", "$.nme", false},
+		{"/ditto.example.HelloService/Hello", "$[0].name", false},
+		{"/ditto.example.HelloService/HelloMulti", "$[0].name", true},
+		{"/ditto.example.HelloService/HelloMulti", "$[?(@.name == 'Bob')].name", true},
+		{"/ditto.example.HelloService/HelloMulti", "$.length", true},
+		{"/ditto.example.HelloService/HelloMulti", "$.name", false},
+		{"/ditto.example.HelloService/HelloMulti", "$[1].nme", false},
+		{"/ditto.example.HelloService/HelloMulti", "$[?(@.nme == 'Bob')].name", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			method := s.findMethodByName(test.method)
+			require.NotNil(t, method)
+
+			tokens, err := ajson.ParseJSONPath(test.expr)
+			require.NoError(t, err)
+
+			errs := checkJSONPathFields(method, tokens, method.IsClientStreaming())
+			if test.valid {
+				assert.Empty(t, errs)
+			} else {
+				assert.NotEmpty(t, errs)
+			}
+		})
+	}
+}
+
+func TestMockServiceValidateEqualToJSONFields(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		json     string
+		partial  bool
+		errors   int
+		warnings int
+	}{
+		{"Valid", "/greet.Greeter/SayHello", `{"name": "Bob"}`, false, 0, 0},
+		{"UnknownField", "/greet.Greeter/SayHello", `{"name": "Bob", "age": 1}`, false, 1, 0},
+		{"MissingField", "/greet.Greeter/SayHello", `{}`, false, 0, 1},
+		{"MissingFieldIncludes", "/greet.Greeter/SayHello", `{}`, true, 0, 0},
+		{"ClientStream", "/ditto.example.HelloService/HelloMulti", `[{"name": "Bob"}, {"name": "John"}]`, false, 0, 0},
+		{"ClientStreamNotArray", "/ditto.example.HelloService/HelloMulti", `{"name": "Bob"}`, false, 1, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := greetMock()
+			mock.Request.Method = test.method
+			mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{
+				{EqualToJson: []byte(test.json), IgnoreExtraFields: test.partial},
+			}
+			mock.Response = nil
+
+			errs, warnings := 0, 0
+			for _, err := range validator.ValidateMocks([]dittomock.DittoMock{mock}) {
+				if isWarning(err) {
+					warnings++
+				} else {
+					errs++
+				}
+			}
+
+			assert.Equal(t, test.errors, errs)
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestMockServiceValidateNestedPatterns(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	mock := greetMock()
+	mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{
+		{
+			AnyOf: []dittomock.DittoBodyPattern{
+				{MatchesJsonPath: &dittomock.JSONPathWrapper{JSONPathMessage: dittomock.JSONPathMessage{Expression: "$.nam", Equals: "Bob"}}},
+				{},
+			},
+			Not: &dittomock.DittoBodyPattern{
+				AllOf: []dittomock.DittoBodyPattern{
+					{MatchesJsonPath: &dittomock.JSONPathWrapper{JSONPathMessage: dittomock.JSONPathMessage{Expression: "$.name", Regexp: "("}}},
+					{EqualToJson: []byte(`{}`)},
+				},
+			},
+		},
+	}
+
+	errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+	require.Len(t, errs, 4)
+	assert.Contains(t, errs[0].Error(), `body_patterns[0]: any_of[0]: jsonpath "$.nam"`)
+	assert.Contains(t, errs[1].Error(), "body_patterns[0]: any_of[1]: pattern is empty")
+	assert.Contains(t, errs[2].Error(), `body_patterns[0]: not: all_of[0]: invalid regexp "("`)
+	assert.Contains(t, errs[3].Error(), "body_patterns[0]: not: all_of[1]: equal_to_json")
+	assert.True(t, isWarning(errs[3]))
+}
+
+func TestMockServiceValidateComparisonOperators(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name    string
+		message dittomock.JSONPathMessage
+		err     string
+	}{
+		{"Between", dittomock.JSONPathMessage{Between: &dittomock.Range{Min: "1", Max: "10"}}, ""},
+		{"BetweenNoMax", dittomock.JSONPathMessage{Between: &dittomock.Range{Min: "1"}}, "between requires min and max"},
+		{"BetweenEmpty", dittomock.JSONPathMessage{Between: &dittomock.Range{Min: "10", Max: "9"}}, `between min "10" is greater than max "9"`},
+		{"BetweenTime", dittomock.JSONPathMessage{Between: &dittomock.Range{Min: "2024-02-01T00:00:00Z", Max: "2024-01-01T00:00:00Z"}}, "is greater than max"},
+		{"BetweenMixedKinds", dittomock.JSONPathMessage{Between: &dittomock.Range{Min: "1", Max: "abc"}}, `between: can't compare number "1" with string "abc"`},
+		{"In", dittomock.JSONPathMessage{In: []string{"Bob"}}, ""},
+		{"InEmpty", dittomock.JSONPathMessage{In: []string{}}, "in requires at least one value"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.message.Expression = "$.name"
+			mock := greetMock()
+			mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{
+				{MatchesJsonPath: &dittomock.JSONPathWrapper{JSONPathMessage: test.message}},
+			}
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
+
+const comparisonTestProto = `syntax = "proto3";
+
+package compare.test;
+
+import "google/protobuf/timestamp.proto";
+
+service Orders {
+  rpc Create(Order) returns (Order);
+}
+
+message Order {
+  string currency = 1;
+  int32 quantity = 2;
+  int64 amount = 3;
+  bool paid = 4;
+  google.protobuf.Timestamp created_at = 5;
+  Customer customer = 6;
+  repeated int32 counts = 7;
+}
+
+message Customer {
+  string name = 1;
+}
+`
+
+func TestMockServiceValidateComparisonOperands(t *testing.T) {
+	p := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"compare.proto": comparisonTestProto}),
+	}
+	fds, err := p.ParseFiles("compare.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: fds,
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name    string
+		message dittomock.JSONPathMessage
+		err     string
+	}{
+		{"Number", dittomock.JSONPathMessage{Expression: "$.quantity", Gt: "10"}, ""},
+		{"NumberText", dittomock.JSONPathMessage{Expression: "$.quantity", Gt: "abc"}, `gt: can't compare number "0" with string "abc"`},
+		{"Int64Text", dittomock.JSONPathMessage{Expression: "$.amount", Lte: "abc"}, `lte: can't compare number "0" with string "abc"`},
+		{"Bool", dittomock.JSONPathMessage{Expression: "$.paid", Gte: "yes"}, `gte: "yes" is not a boolean`},
+		{"Timestamp", dittomock.JSONPathMessage{Expression: "$.created_at", Lt: "2024-01-01T00:00:00Z"}, ""},
+		{"TimestampNumber", dittomock.JSONPathMessage{Expression: "$.created_at", Lt: "1000"}, `lt: can't compare timestamp`},
+		{"BetweenNumber", dittomock.JSONPathMessage{Expression: "$.quantity", Between: &dittomock.Range{Min: "a", Max: "b"}}, `between min: can't compare number "0" with string "a"`},
+		{"Message", dittomock.JSONPathMessage{Expression: "$.customer", Gt: "1"}, "compare.test.Customer values can't be compared"},
+		{"String", dittomock.JSONPathMessage{Expression: "$.currency", Gt: "1000"}, ""},
+		{"NestedString", dittomock.JSONPathMessage{Expression: "$.customer.name", Gt: "A"}, ""},
+		{"ListElement", dittomock.JSONPathMessage{Expression: "$.counts[0]", Gt: "abc"}, `gt: can't compare number "0" with string "abc"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := dittomock.DittoMock{
+				Request: &dittomock.DittoRequest{
+					Method: "/compare.test.Orders/Create",
+					BodyPatterns: []dittomock.DittoBodyPattern{
+						{MatchesJsonPath: &dittomock.JSONPathWrapper{JSONPathMessage: test.message}},
+					},
+				},
+				Response: []*dittomock.DittoResponse{{Body: []byte(`{}`)}},
+			}
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.NotEmpty(t, errs)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
+
+func TestMockServiceValidateCEL(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		expr   string
+		err    string
+	}{
+		{"Valid", "/greet.Greeter/SayHello", "request.name == 'Bob' && 'beta' in metadata['x-features']", ""},
+		{"ClientStream", "/ditto.example.HelloService/HelloMulti", "request.size() > 1 && request[0].name == 'Bob'", ""},
+		{"UnknownField", "/greet.Greeter/SayHello", "request.age > 1", "invalid matches_cel"},
+		{"NotBool", "/greet.Greeter/SayHello", "request.name", "expression must return bool"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := greetMock()
+			mock.Request.Method = test.method
+			mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{{MatchesCEL: test.expr}}
+			mock.Response = nil
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
+
+func TestMockServiceValidateFieldPattern(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		pattern dittomock.FieldPattern
+		err     string
+	}{
+		{"Valid", "/greet.Greeter/SayHello", dittomock.FieldPattern{Field: "name", StartsWith: "B"}, ""},
+		{"ClientStream", "/ditto.example.HelloService/HelloMulti", dittomock.FieldPattern{Field: "[0].name", Equals: "Bob"}, ""},
+		{"UnknownField", "/greet.Greeter/SayHello", dittomock.FieldPattern{Field: "age", Gt: "1"}, "invalid matches_field"},
+		{"Index", "/greet.Greeter/SayHello", dittomock.FieldPattern{Field: "name[0]", Equals: "B"}, "is not repeated or map"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := greetMock()
+			mock.Request.Method = test.method
+			mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{{MatchesField: &test.pattern}}
+			mock.Response = nil
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
+
+func TestMockServiceValidateMessagePatterns(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	name := func(expr string) *dittomock.DittoBodyPattern {
+		return &dittomock.DittoBodyPattern{MatchesJsonPath: &dittomock.JSONPathWrapper{
+			JSONPathMessage: dittomock.JSONPathMessage{Expression: expr, Equals: "Bob"},
+		}}
+	}
+	one := uint32(1)
+
+	tests := []struct {
+		name    string
+		method  string
+		pattern dittomock.DittoBodyPattern
+		err     string
+	}{
+		{
+			name:   "Valid",
+			method: "/ditto.example.HelloService/HelloMulti",
+			pattern: dittomock.DittoBodyPattern{
+				EveryMessage: &dittomock.DittoBodyPattern{MatchesCEL: "request.name != ''"},
+				Sequence:     []dittomock.SequenceStep{{Skip: true}, {Message: name("$.name")}},
+			},
+		},
+		{
+			name:    "Unary",
+			method:  "/greet.Greeter/SayHello",
+			pattern: dittomock.DittoBodyPattern{AnyMessage: name("$.name")},
+			err:     "require client streaming request",
+		},
+		{
+			name:    "MessageField",
+			method:  "/ditto.example.HelloService/HelloMulti",
+			pattern: dittomock.DittoBodyPattern{AnyMessage: name("$.nam")},
+			err:     `any_message: jsonpath "$.nam"`,
+		},
+		{
+			name:    "MessageCount",
+			method:  "/ditto.example.HelloService/HelloMulti",
+			pattern: dittomock.DittoBodyPattern{MessageCount: &dittomock.MessageCount{Min: 2, Max: &one}},
+			err:     "message_count min 2 is greater than max 1",
+		},
+		{
+			name:    "EmptyStep",
+			method:  "/ditto.example.HelloService/HelloMulti",
+			pattern: dittomock.DittoBodyPattern{Sequence: []dittomock.SequenceStep{{}}},
+			err:     "sequence[0]: step requires message, any or skip",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mock := greetMock()
+			mock.Request.Method = test.method
+			mock.Request.BodyPatterns = []dittomock.DittoBodyPattern{test.pattern}
+			mock.Response = nil
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
+
+func TestMockServiceValidateResponseGenerator(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr},
+	}
+
+	validator := &mockValidator{
+		findMethodFunc: s.findMethodByName,
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		generate string
+		err      string
+	}{
+		{"Valid", "/ditto.example.HelloService/HelloMulti", `{ "foreach": "$[*].name", "body_template": "name: {{.Item}}" }`, ""},
+		{"Unary", "/greet.Greeter/SayHello", `{ "repeat": 2, "body_template": "{}" }`, "generate requires server streaming method"},
+		{"UnknownField", "/ditto.example.HelloService/Hello", `{ "foreach": "$.names", "body_template": "{}" }`, `response[0]: foreach "$.names"`},
+	}
+
+	t.Run("WeightedResponses", func(t *testing.T) {
+		mock := greetMock()
+		mock.Responses = []dittomock.WeightedResponse{
+			{Weight: 1, Response: mock.Response},
+			{Weight: 1, Response: []*dittomock.DittoResponse{{Body: []byte(`{ "unknown": 1 }`)}}},
+		}
+		mock.Response = nil
+
+		errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "responses[1]: response[0]: invalid response")
+	})
+
+	t.Run("StreamOptionsUnary", func(t *testing.T) {
+		mock := greetMock()
+		mock.Stream = &dittomock.StreamOptions{KeepOpen: true}
+
+		errs := validator.ValidateMocks([]dittomock.DittoMock{mock})
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "stream options require server streaming method")
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			js := fmt.Sprintf(`{"request": {"method": %q}, "response": [{"generate": %s}]}`, test.method, test.generate)
+			mock := &api.DittoMock{}
+			require.NoError(t, protojson.Unmarshal([]byte(js), mock))
+			dm, err := dittomock.FromProto(mock)
+			require.NoError(t, err)
+
+			errs := validator.ValidateMocks([]dittomock.DittoMock{dm})
+			if test.err == "" {
+				assert.Empty(t, errs)
+				return
+			}
+
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), test.err)
+		})
+	}
+}
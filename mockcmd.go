@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,6 +10,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -22,34 +24,40 @@ import (
 	"github.com/jhump/protoreflect/desc/protoparse"
 	"github.com/urfave/cli"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/runtime/protoimpl"
 )
 
 const (
 	maxShutdownTime = 30 * time.Second
+	// number of recent unmatched requests available via admin api
+	unmatchedJournalSize = 100
+	// number of recent canceled requests available via admin api
+	canceledJournalSize = 100
 )
 
 func newMockCmd() func(ctx *cli.Context) error {
 	return func(ctx *cli.Context) error {
-		log := logger.NewLogger(logger.WithLevel(ctx.String("loglevel")))
-		grpclog.SetLoggerV2(logger.NewGrpcLogger(log, "error"))
+		if path := ctx.String("config"); path != "" {
+			cfg, err := loadConfig(path)
+			if err != nil {
+				return fmt.Errorf("config %s: %w", path, err)
+			}
+			if err := applyConfig(ctx, cfg); err != nil {
+				return fmt.Errorf("config %s: %w", path, err)
+			}
+		}
 
-		descrs, err := parseProtoFiles(ctx)
-		if err != nil {
+		if err := requireFlags(ctx, "proto", "mocks"); err != nil {
 			return err
 		}
 
-		mocksPath := ctx.String("mocks")
-		log.Infow("loading mocks", "path", mocksPath)
-		requestMatcher, err := dittomock.NewRequestMatcher(
-			dittomock.WithMocksPath(mocksPath),
-			dittomock.WithLogger(log),
-		)
+		log := logger.NewLogger(logger.WithLevel(ctx.String("loglevel")), logger.WithEncoding(ctx.String("log-format")))
+		grpclog.SetLoggerV2(logger.NewGrpcLogger(log, "error"))
+
+		descrs, err := parseProtoFiles(ctx)
 		if err != nil {
 			return err
 		}
@@ -61,13 +69,51 @@ func newMockCmd() func(ctx *cli.Context) error {
 			return err
 		}
 		descrs = append(descrs, healthcheckDescr)
-		requestMatcher.AddMock(healthCheckMocks())
+
+		unmatched, err := parseUnmatchedFlags(ctx, descrs)
+		if err != nil {
+			return err
+		}
+
+		// the seed makes fault injection and weighted responses reproducible, it's logged
+		// so that the run can be repeated
+		seed := ctx.Int64("seed")
+		if !ctx.IsSet("seed") {
+			seed = time.Now().UnixNano()
+		}
+		log.Infow("random seed", "seed", seed)
+
+		faults, err := parseFaultFlags(ctx, seed, log)
+		if err != nil {
+			return err
+		}
 
 		mockServer := &mockServer{
-			descrs:  descrs,
-			logger:  log,
-			matcher: requestMatcher,
+			descrs:        descrs,
+			logger:        log,
+			journal:       dittomock.NewJournal[dittomock.JournalEntry](unmatchedJournalSize),
+			unmatched:     unmatched,
+			done:          make(chan struct{}),
+			streams:       dittomock.NewStreamRegistry(),
+			faults:        faults,
+			cancellations: dittomock.NewJournal[dittomock.CanceledEntry](canceledJournalSize),
+		}
+
+		sources := mockSources(ctx)
+		log.Infow("loading mocks", "paths", sources.Paths)
+		requestMatcher, err := dittomock.NewRequestMatcher(
+			dittomock.WithMockSources(sources),
+			dittomock.WithLogger(log),
+			dittomock.WithMethodResolver(mockServer.findMethodByName),
+			dittomock.WithRandSeed(seed),
+		)
+		if err != nil {
+			return err
+		}
+		if err := requestMatcher.AddMock(healthCheckMocks()); err != nil {
+			return err
 		}
+		mockServer.matcher = requestMatcher
 
 		fileDescrs, err := mockServer.fileDescriptors()
 		if err != nil {
@@ -86,6 +132,7 @@ func newMockCmd() func(ctx *cli.Context) error {
 
 		validator := &mockValidator{
 			findMethodFunc: mockServer.findMethodByName,
+			logger:         log,
 		}
 
 		log.Info("validating mocks")
@@ -93,50 +140,249 @@ func newMockCmd() func(ctx *cli.Context) error {
 			return err
 		}
 
-		server := grpc.NewServer(grpc.UnknownServiceHandler(unknownHandler))
+		// onStop functions are called before the server is stopped, afterStop functions once it's stopped
+		onStop := []func(){func() { close(mockServer.done) }}
+		var afterStop []func()
+		var interceptors []grpc.StreamServerInterceptor
+
+		if spec := ctx.String("trace-exporter"); spec != "" {
+			exporter, err := newTraceExporter(context.Background(), spec, ctx.String("trace-endpoint"))
+			if err != nil {
+				return fmt.Errorf("tracing: %w", err)
+			}
+			provider := newTracerProvider(exporter)
+			log.Infow("tracing enabled", "exporter", spec)
+
+			tracing := newServerTracing(provider, func(method string) bool {
+				return mockServer.findMethodByName(method) != nil
+			}, ctx.Bool("trace-echo"), log)
+			interceptors = append(interceptors, tracing.streamInterceptor)
+			// spans of the calls finished during graceful stop are exported too
+			afterStop = append(afterStop, func() {
+				if err := provider.Shutdown(context.Background()); err != nil {
+					log.Errorw("tracing shutdown", "err", err)
+				}
+			})
+		}
+
+		if port := ctx.Int("metrics-port"); port > 0 {
+			metrics := newServerMetrics(func(method string) bool {
+				return mockServer.findMethodByName(method) != nil
+			})
+			metricsServer, err := serveMetrics(port, metrics, log)
+			if err != nil {
+				return err
+			}
+
+			interceptors = append(interceptors, metrics.streamInterceptor)
+			onStop = append(onStop, func() { metricsServer.Close() })
+		}
+
+		if dest := ctx.String("access-log"); dest != "" {
+			out, closeAccessLog, err := openAccessLog(dest)
+			if err != nil {
+				return fmt.Errorf("access log: %w", err)
+			}
+			afterStop = append(afterStop, func() {
+				if err := closeAccessLog(); err != nil {
+					log.Errorw("access log close", "err", err)
+				}
+			})
+
+			accessLog := newAccessLog(out, ctx.Int("access-log-max-body"), ctx.StringSlice("access-log-redact"))
+			interceptors = append(interceptors, accessLog.streamInterceptor)
+		}
+
+		creds, err := serverCredentials(ctx.String("tls-cert"), ctx.String("tls-key"), ctx.String("tls-client-ca"))
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+
+		if creds != nil {
+			log.Info("tls enabled")
+		}
+
+		// options shared by all the servers, connections are tracked for fault rules
+		mockServer.conns = newConnTracker()
+		serverOpts := []grpc.ServerOption{grpc.Creds(mockServer.conns.wrap(creds))}
+
+		server := grpc.NewServer(append(serverOpts,
+			grpc.UnknownServiceHandler(mockServer.unknownHandler),
+			grpc.ChainStreamInterceptor(interceptors...),
+		)...)
 		for _, mockService := range mockServer.serviceDescriptors() {
 			log.Infow("register mock service", "service", mockService.ServiceName)
 			server.RegisterService(mockService, mockServer)
 		}
 
+		// admin api is served together with the mocks unless it has its own listeners
+		adminServer := server
+		if len(ctx.StringSlice("admin-listen")) > 0 {
+			adminServer = grpc.NewServer(serverOpts...)
+			reflection.Register(adminServer)
+		}
+
 		api.RegisterMockingServiceServer(
-			server,
-			services.NewMockingService(requestMatcher, validator, log),
+			adminServer,
+			services.NewMockingService(requestMatcher, validator, mockServer.journal, mockServer.cancellations,
+				mockServer.streams, mockServer.faults, log),
 		)
 
 		reflection.Register(server)
 
-		return startServer(ctx.Int("port"), server, log)
+		// --port is the only listener by default, it's also used together with --listen if it's set explicitly
+		addrs := ctx.StringSlice("listen")
+		if len(addrs) == 0 || ctx.IsSet("port") {
+			addrs = append(addrs, fmt.Sprintf(":%d", ctx.Int("port")))
+		}
+
+		listeners, err := openListeners(addrs)
+		if err != nil {
+			return err
+		}
+
+		servers := []serving{{server: server, listeners: listeners}}
+		portLines := make([]string, 0, len(listeners))
+		for _, lis := range listeners {
+			portLines = append(portLines, listenerAddr(lis))
+		}
+
+		for _, spec := range ctx.StringSlice("service-listen") {
+			l, err := parseServiceListener(spec)
+			if err != nil {
+				return err
+			}
+
+			serviceServer, healthServer, err := mockServer.newServiceServer(l, append(serverOpts, grpc.ChainStreamInterceptor(interceptors...))...)
+			if err != nil {
+				return fmt.Errorf("service-listen %s: %w", spec, err)
+			}
+			onStop = append(onStop, healthServer.Shutdown)
+
+			lis, err := listen(l.addr)
+			if err != nil {
+				return fmt.Errorf("service-listen %s: %w", spec, err)
+			}
+
+			servers = append(servers, serving{server: serviceServer, listeners: []net.Listener{lis}})
+			portLines = append(portLines, strings.Join(l.patterns, ",")+"="+listenerAddr(lis))
+		}
+
+		if addrs := ctx.StringSlice("admin-listen"); len(addrs) > 0 {
+			adminListeners, err := openListeners(addrs)
+			if err != nil {
+				return fmt.Errorf("admin-listen: %w", err)
+			}
+
+			servers = append(servers, serving{server: adminServer, listeners: adminListeners})
+			for _, lis := range adminListeners {
+				portLines = append(portLines, "admin="+listenerAddr(lis))
+			}
+		}
+
+		if path := ctx.String("port-file"); path != "" {
+			if err := writePortFile(path, portLines); err != nil {
+				return fmt.Errorf("port file: %w", err)
+			}
+			onStop = append(onStop, func() { os.Remove(path) })
+		}
+
+		return startServer(servers, log, onStop, afterStop)
 	}
 }
 
-func startServer(port int, server *grpc.Server, log logger.Logger) error {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		return err
-	}
+// serving is a grpc server together with the listeners it serves on
+type serving struct {
+	server    *grpc.Server
+	listeners []net.Listener
+}
+
+// startServer serves on all listeners until a termination signal is received or any of the listeners fails,
+// onStop functions are called before graceful stop, afterStop functions are called once the servers are stopped
+func startServer(servers []serving, log logger.Logger, onStop, afterStop []func()) error {
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
+
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
 		<-sigs
 		log.Info("stopping service")
+		for _, stop := range onStop {
+			stop()
+		}
 		timer := time.AfterFunc(maxShutdownTime, func() {
 			log.Info("force stop gRPC server")
-			server.Stop()
+			for _, srv := range servers {
+				srv.server.Stop()
+			}
 		})
 		defer timer.Stop()
-		server.GracefulStop()
+
+		var wg sync.WaitGroup
+		for _, srv := range servers {
+			wg.Add(1)
+			go func(server *grpc.Server) {
+				defer wg.Done()
+				server.GracefulStop()
+			}(srv.server)
+		}
+		wg.Wait()
+
+		for _, stop := range afterStop {
+			stop()
+		}
 	}()
 
-	log.Infow("start server", "port", port)
-	if err := server.Serve(lis); err != nil {
-		return err
+	var total int
+	for _, srv := range servers {
+		total += len(srv.listeners)
 	}
 
+	// buffered, so that the servers finishing after the first error don't block
+	errs := make(chan error, total)
+	for _, srv := range servers {
+		for _, lis := range srv.listeners {
+			log.Infow("start server", "addr", listenerAddr(lis))
+			go func(server *grpc.Server, lis net.Listener) {
+				errs <- server.Serve(lis)
+			}(srv.server, lis)
+		}
+	}
+
+	for i := 0; i < total; i++ {
+		if err := <-errs; err != nil {
+			for _, srv := range servers {
+				srv.server.Stop()
+			}
+			for _, stop := range afterStop {
+				stop()
+			}
+			return err
+		}
+	}
+
+	// serve returns once the servers are stopped, afterStop functions may be still running
+	<-stopped
+	return nil
+}
+
+// requireFlags mimics cli required flags check, it's done per command
+// because root flags are validated even if a subcommand is executed
+func requireFlags(ctx *cli.Context, names ...string) error {
+	for _, name := range names {
+		if !ctx.IsSet(name) {
+			return fmt.Errorf("Required flag %q not set", name)
+		}
+	}
 	return nil
 }
 
 func parseProtoFiles(ctx *cli.Context) ([]*desc.FileDescriptor, error) {
+	if err := requireFlags(ctx, "proto"); err != nil {
+		return nil, err
+	}
+
 	protoPaths := ctx.StringSlice("proto")
 	protofiles, err := findProtoFiles(protoPaths)
 	if err != nil {
@@ -214,8 +460,54 @@ func findProtoFiles(paths []string) ([]string, error) {
 	return protofiles, nil
 }
 
-func unknownHandler(srv interface{}, stream grpc.ServerStream) error {
-	return status.Error(codes.Unimplemented, "unimplemented mock")
+// parseUnmatchedFlags parses unmatched policies, body policies are validated against the output
+// messages of the methods they apply to
+func parseUnmatchedFlags(ctx *cli.Context, descrs []*desc.FileDescriptor) (*unmatchedPolicies, error) {
+	policies := newUnmatchedPolicies()
+	if spec := ctx.String("unmatched"); spec != "" {
+		policy, err := parseUnmatchedPolicy(spec)
+		if err != nil {
+			return nil, fmt.Errorf("unmatched: %w", err)
+		}
+		policies.defaults = policy
+	}
+
+	for _, s := range ctx.StringSlice("unmatched-service") {
+		if err := policies.parseServicePolicy(s); err != nil {
+			return nil, fmt.Errorf("unmatched-service: %w", err)
+		}
+	}
+
+	if err := policies.validate(descrs); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// mockSources describes mock sources of --mocks flags
+func mockSources(ctx *cli.Context) dittomock.MockSources {
+	return dittomock.MockSources{
+		Paths:   ctx.StringSlice("mocks"),
+		Include: ctx.StringSlice("mocks-include"),
+		Exclude: ctx.StringSlice("mocks-exclude"),
+	}
+}
+
+// parseFaultFlags loads fault rules
+func parseFaultFlags(ctx *cli.Context, seed int64, log logger.Logger) (*dittomock.FaultInjector, error) {
+	var rules []dittomock.FaultRule
+	if file := ctx.String("faults"); file != "" {
+		var err error
+		rules, err = dittomock.ParseFaultRules(file)
+		if err != nil {
+			return nil, fmt.Errorf("faults: %w", err)
+		}
+	}
+
+	log.Infow("fault injection", "rules", len(rules))
+
+	return dittomock.NewFaultInjector(seed, rules), nil
 }
 
 func healthCheckMocks() dittomock.DittoMock {
@@ -224,7 +516,8 @@ func healthCheckMocks() dittomock.DittoMock {
 			Method: "/grpc.health.v1.Health/Check",
 			BodyPatterns: []dittomock.DittoBodyPattern{
 				{
-					EqualToJson: []byte("{}"),
+					// requests are rendered with default values
+					EqualToJson: []byte(`{ "service": "" }`),
 				},
 			},
 		},
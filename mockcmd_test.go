@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthCheckMocksMatchRequest(t *testing.T) {
+	matcher, err := dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{healthCheckMocks()}))
+	require.NoError(t, err)
+
+	md, err := desc.LoadMessageDescriptorForMessage(&grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	// render the request the same way the server does
+	js, err := dynamic.NewMessage(md).MarshalJSONPB(&jsonpb.Marshaler{OrigName: true, EmitDefaults: true})
+	require.NoError(t, err)
+
+	mock, err := matcher.Match("/grpc.health.v1.Health/Check", js)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{ "status": "SERVING" }`, string(mock.Response[0].Body))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// proxy forwards requests to a real service, connection is established on the first request
+type proxy struct {
+	addr string
+
+	once sync.Once
+	cc   *grpc.ClientConn
+	err  error
+}
+
+func newProxy(addr string) *proxy {
+	return &proxy{addr: addr}
+}
+
+func (p *proxy) conn() (*grpc.ClientConn, error) {
+	p.once.Do(func() {
+		p.cc, p.err = grpc.NewClient(p.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	})
+	return p.cc, p.err
+}
+
+// forward sends already received request messages to the upstream service
+func (p *proxy) forward(stream grpc.ServerStream, fullMethodName string, methodDesc *desc.MethodDescriptor, inputJS []byte) error {
+	inputs, err := inputMessages(methodDesc, inputJS)
+	if err != nil {
+		return err
+	}
+
+	return p.call(stream, fullMethodName, inputs)
+}
+
+// forwardRaw forwards requests of the methods without loaded proto files.
+// Messages are passed as is since unknown fields of an empty message keep the original content.
+func (p *proxy) forwardRaw(stream grpc.ServerStream, fullMethodName string) error {
+	var inputs []interface{}
+	for {
+		in := &emptypb.Empty{}
+		err := stream.RecvMsg(in)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		inputs = append(inputs, in)
+	}
+
+	return p.call(stream, fullMethodName, inputs)
+}
+
+func (p *proxy) call(stream grpc.ServerStream, fullMethodName string, inputs []interface{}) error {
+	cc, err := p.conn()
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "proxy connection: %s", err)
+	}
+
+	ctx := stream.Context()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	// every method is called as bidi stream, it works for all method types
+	streamDesc := &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+	cs, err := cc.NewStream(ctx, streamDesc, fullMethodName)
+	if err != nil {
+		return err
+	}
+
+	for _, in := range inputs {
+		if err := cs.SendMsg(in); err != nil {
+			// io.EOF means the stream was terminated by the service,
+			// the actual status is returned by RecvMsg
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+	}
+	if err := cs.CloseSend(); err != nil {
+		return err
+	}
+
+	headerSent := false
+	for {
+		out := &emptypb.Empty{}
+		err := cs.RecvMsg(out)
+		if !headerSent {
+			if header, headerErr := cs.Header(); headerErr == nil {
+				stream.SetHeader(header)
+			}
+			headerSent = true
+		}
+
+		if err != nil {
+			stream.SetTrailer(cs.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if err := stream.SendMsg(out); err != nil {
+			return err
+		}
+	}
+}
+
+// inputMessages converts request json back to messages, client streaming requests are arrays
+func inputMessages(methodDesc *desc.MethodDescriptor, inputJS []byte) ([]interface{}, error) {
+	rawMessages := []json.RawMessage{inputJS}
+	if methodDesc.IsClientStreaming() {
+		rawMessages = nil
+		if err := json.Unmarshal(inputJS, &rawMessages); err != nil {
+			return nil, err
+		}
+	}
+
+	inputs := make([]interface{}, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		in := dynamic.NewMessage(methodDesc.GetInputType())
+		if err := in.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, in)
+	}
+
+	return inputs, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/vadimi/grpc-ditto/internal/dittomock"
 	"github.com/vadimi/grpc-ditto/internal/logger"
@@ -13,8 +14,11 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/dynamic"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
@@ -31,6 +35,20 @@ type mockServer struct {
 	logger  logger.Logger
 	descrs  []*desc.FileDescriptor
 	matcher *dittomock.RequestMatcher
+	// journal records unmatched requests, it's optional
+	journal *dittomock.Journal[dittomock.JournalEntry]
+	// unmatched defines responses for requests that don't match any mock, it's optional
+	unmatched *unmatchedPolicies
+	// done is closed when the server is stopping to end streams that are kept open, it's optional
+	done chan struct{}
+	// streams tracks open server streams for admin api, it's optional
+	streams *dittomock.StreamRegistry
+	// faults injects failures into the calls, it's optional
+	faults *dittomock.FaultInjector
+	// conns allows fault rules to reset client connections, it's optional
+	conns *connTracker
+	// cancellations records calls canceled by clients or finished by client deadlines, it's optional
+	cancellations *dittomock.Journal[dittomock.CanceledEntry]
 }
 
 func (s *mockServer) findMethodByName(method string) *desc.MethodDescriptor {
@@ -100,6 +118,7 @@ func (s *mockServer) serviceDescriptors() []*grpc.ServiceDesc {
 					StreamName:    m.GetName(),
 					Handler:       mockServerStreamHandler,
 					ServerStreams: m.IsServerStreaming(),
+					ClientStreams: m.IsClientStreaming(),
 				})
 			}
 
@@ -111,49 +130,156 @@ func (s *mockServer) serviceDescriptors() []*grpc.ServiceDesc {
 }
 
 func mockServerStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	mockSrv := srv.(*mockServer)
+	started := time.Now()
+
+	// call info tracks the stage of the call even if interceptors are not used
+	if callInfoFromContext(stream.Context()) == nil {
+		ctx, _ := withCallInfo(stream.Context())
+		stream = &contextStream{ServerStream: stream, ctx: ctx}
+	}
+
+	ctx := stream.Context()
+	faulty, err := mockSrv.injectFault(stream)
+	if err == nil {
+		err = handleMockStream(mockSrv, faulty)
+	} else {
+		callInfoFromContext(ctx).setOutcome(outcomeFault, "")
+	}
+
+	if errors.Is(err, errStreamTruncated) {
+		return nil
+	}
+
+	// whatever the handler was doing, the client gave up on the call
+	if err != nil && ctx.Err() != nil {
+		fullMethodName, _ := grpc.Method(ctx)
+		mockSrv.recordCanceled(ctx, fullMethodName, started, callInfoFromContext(ctx))
+		return contextError(ctx)
+	}
+
+	return err
+}
+
+func handleMockStream(mockSrv *mockServer, stream grpc.ServerStream) error {
 	fullMethodName, ok := grpc.Method(stream.Context())
 	if !ok {
 		return errors.New("something is really wrong, method name not found in the request")
 	}
 
-	mockSrv := srv.(*mockServer)
 	mockSrv.logger.Infow("grpc call", "method", fullMethodName)
+	call := callInfoFromContext(stream.Context())
 
 	methodDesc := mockSrv.findMethodByName(fullMethodName)
 	if methodDesc == nil {
+		call.setOutcome(outcomeUnmatched, "")
 		return status.Errorf(codes.Unimplemented, "unimplemented mock for method: %s", fullMethodName)
 	}
 
+	call.setStage(stageReceive)
+	span := startSpan(stream.Context(), "receive", attribute.Bool("rpc.client_streaming", methodDesc.IsClientStreaming()))
 	inputJS, err := readInput(stream, methodDesc, mockSrv.logger)
+	endSpan(span, err)
 	if err != nil {
 		mockSrv.logger.Error(fmt.Errorf("input message json marshaling: %w", err))
 		return err
 	}
+	call.setRequest(inputJS)
+
+	// the client might have given up while the request was received
+	call.setStage(stageMatch)
+	if err := contextError(stream.Context()); err != nil {
+		return err
+	}
 
 	mockSrv.logger.Debugw("matching request", "req", string(inputJS))
-	mock, err := mockSrv.matcher.Match(fullMethodName, inputJS)
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	span = startSpan(stream.Context(), "match")
+	matchStart := time.Now()
+	mock, err := mockSrv.matcher.Match(fullMethodName, inputJS,
+		dittomock.WithMetadata(md),
+		dittomock.WithDeadline(stream.Context().Deadline()),
+	)
+	call.setMatchDuration(time.Since(matchStart))
+	span.SetAttributes(attribute.Bool("ditto.matched", err == nil))
+	if mock != nil {
+		span.SetAttributes(attribute.String("ditto.mock_id", mock.ID))
+	}
+	span.End()
 	if err != nil {
-		if errors.Is(err, dittomock.ErrNotMatched) {
-			mockSrv.logger.Warn("no match found")
-		} else {
-			mockSrv.logger.Error(err)
+		call.setOutcome(outcomeUnmatched, "")
+		var notMatched *dittomock.NotMatchedError
+		if errors.As(err, &notMatched) {
+			mockSrv.logger.Warnw("no match found", "method", fullMethodName, "reason", notMatched.Summary())
+			mockSrv.recordUnmatched(inputJS, notMatched)
+			return mockSrv.respondUnmatched(stream, fullMethodName, methodDesc, inputJS, notMatched)
 		}
+
+		mockSrv.logger.Error(err)
 		return status.Errorf(codes.Unimplemented, "unimplemented mock for method: %s", fullMethodName)
 	}
 
-	for _, resp := range mock.Response {
-		if resp.Status != nil {
-			return status.Error(resp.Status.Code, resp.Status.Message)
+	call.setOutcome(outcomeMatched, mock.ID)
+
+	keepOpen := mock.Stream != nil && mock.Stream.KeepOpen && methodDesc.IsServerStreaming()
+	var closed <-chan *status.Status
+	if mockSrv.streams != nil && methodDesc.IsServerStreaming() {
+		var open *dittomock.OpenStream
+		var unregister func()
+		stream, open, unregister = mockSrv.registerStream(stream, methodDesc, md, inputJS)
+		defer unregister()
+		// close applies only to streams that are held open, it takes effect after the responses are sent
+		if keepOpen {
+			mockSrv.streams.KeepOpen(open)
+			closed = open.Closed()
 		}
+	}
 
-		output := dynamic.NewMessage(methodDesc.GetOutputType())
-		err = output.UnmarshalJSON(resp.Body)
+	if mock.Delay > 0 {
+		call.setStage(stageDelay)
+		span = startSpan(stream.Context(), "delay", attribute.String("ditto.delay", mock.Delay.String()))
+		err = sleep(stream.Context(), mock.Delay)
+		endSpan(span, err)
 		if err != nil {
-			mockSrv.logger.Error(err)
 			return err
 		}
+	}
+
+	call.setStage(stageSend)
+	span = startSpan(stream.Context(), "send", attribute.Int("ditto.responses", len(mock.Response)))
+	err = mockSrv.sendResponses(stream, methodDesc, mock.Response, inputJS)
+	endSpan(span, err)
+	if err != nil {
+		return err
+	}
+
+	if keepOpen {
+		call.setStage(stageKeepOpen)
+		return mockSrv.keepOpen(stream, methodDesc, mock.Stream, inputJS, closed)
+	}
+
+	return nil
+}
 
-		err = stream.SendMsg(output)
+// sendResponses sends the mock responses, a status response finishes the call
+func (s *mockServer) sendResponses(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, responses []*dittomock.DittoResponse, inputJS []byte) error {
+	for _, resp := range responses {
+		if resp.Status != nil {
+			return status.Error(resp.Status.Code, resp.Status.Message)
+		}
+
+		if resp.Generator != nil {
+			err := resp.Generator.Generate(stream.Context(), inputJS, func(body []byte) error {
+				return s.sendBody(stream, methodDesc, body)
+			})
+			if err != nil {
+				s.logger.Error(err)
+				return err
+			}
+			continue
+		}
+
+		err := s.sendBody(stream, methodDesc, resp.Body)
 		if err != nil {
 			return err
 		}
@@ -162,6 +288,66 @@ func mockServerStreamHandler(srv interface{}, stream grpc.ServerStream) error {
 	return nil
 }
 
+func (s *mockServer) sendBody(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, body []byte) error {
+	if err := contextError(stream.Context()); err != nil {
+		return err
+	}
+
+	output := dynamic.NewMessage(methodDesc.GetOutputType())
+	err := output.UnmarshalJSON(body)
+	if err != nil {
+		s.logger.Error(err)
+		return err
+	}
+
+	return stream.SendMsg(output)
+}
+
+func (s *mockServer) recordUnmatched(inputJS []byte, err *dittomock.NotMatchedError) {
+	if s.journal == nil {
+		return
+	}
+
+	s.journal.Record(dittomock.JournalEntry{
+		Time:       time.Now(),
+		Method:     err.Method,
+		Body:       inputJS,
+		Candidates: err.Candidates,
+	})
+}
+
+// unknownHandler handles methods of services that are not loaded from proto files
+func (s *mockServer) unknownHandler(srv interface{}, stream grpc.ServerStream) error {
+	fullMethodName, _ := grpc.Method(stream.Context())
+	s.logger.Warnw("unknown method", "method", fullMethodName)
+	callInfoFromContext(stream.Context()).setOutcome(outcomeUnmatched, "")
+	return s.respondUnmatched(stream, fullMethodName, nil, nil, nil)
+}
+
+// withMismatchDetails adds mismatches of the closest mocks to the status details
+func withMismatchDetails(st *status.Status, err *dittomock.NotMatchedError) *status.Status {
+	details := &errdetails.BadRequest{}
+	for _, c := range err.Candidates {
+		for _, m := range c.Mismatches {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       m.Path,
+				Description: fmt.Sprintf("mock %s: %s", c.MockID, m),
+			})
+		}
+	}
+
+	if len(details.FieldViolations) == 0 {
+		return st
+	}
+
+	withDetails, detailsErr := st.WithDetails(details)
+	if detailsErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
 func readInput(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, log logger.Logger) ([]byte, error) {
 	inputType := methodDesc.GetInputType()
 	log.Debugw("read input", "type", inputType.GetFullyQualifiedName(), "client_stream", methodDesc.IsClientStreaming())
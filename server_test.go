@@ -12,20 +12,25 @@ import (
 	"github.com/jhump/protoreflect/desc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
 	"github.com/vadimi/grpc-ditto/internal/dittomock"
 	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/internal/services"
 	"github.com/vadimi/grpc-ditto/testdata/greet"
 	_ "github.com/vadimi/grpc-ditto/testdata/greet"
 	"github.com/vadimi/grpc-ditto/testdata/hello"
 	apicode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 var (
-	testServer *grpc.Server
-	testAddr   string
+	testServer  *grpc.Server
+	testAddr    string
+	testJournal = dittomock.NewJournal[dittomock.JournalEntry](10)
 )
 
 func TestMain(m *testing.M) {
@@ -69,6 +74,19 @@ func TestMockServerUnarySuccess(t *testing.T) {
 	assert.Equal(t, "hello Bob", resp.Message)
 }
 
+func TestMockServerStreamDescriptors(t *testing.T) {
+	info, ok := testServer.GetServiceInfo()["ditto.example.HelloService"]
+	require.True(t, ok)
+
+	methods := map[string]grpc.MethodInfo{}
+	for _, m := range info.Methods {
+		methods[m.Name] = m
+	}
+
+	assert.Equal(t, grpc.MethodInfo{Name: "Hello", IsServerStream: true}, methods["Hello"])
+	assert.Equal(t, grpc.MethodInfo{Name: "HelloMulti", IsClientStream: true, IsServerStream: true}, methods["HelloMulti"])
+}
+
 func TestMockServerStreamingSuccess(t *testing.T) {
 	cc, err := grpc.Dial(testAddr, grpc.WithInsecure())
 	if err != nil {
@@ -96,6 +114,47 @@ func TestMockServerStreamingSuccess(t *testing.T) {
 	assert.Equal(t, "hello John", messages[1].GetName())
 }
 
+func TestMockServerStreamingGenerated(t *testing.T) {
+	cc, err := grpc.Dial(testAddr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	client := hello.NewHelloServiceClient(cc)
+	resp, err := client.Hello(context.Background(), &hello.HelloRequest{Name: "generated"})
+	require.NoError(t, err)
+
+	var names []string
+	for {
+		msg, err := resp.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, msg.GetName())
+	}
+
+	assert.Equal(t, []string{"1 of 3 for generated", "2 of 3 for generated", "3 of 3 for generated"}, names)
+
+	stream, err := client.HelloMulti(context.Background())
+	require.NoError(t, err)
+	for _, name := range []string{"Ann", "Ben", "Cid"} {
+		require.NoError(t, stream.Send(&hello.HelloRequest{Name: name}))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	names = nil
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, msg.GetName())
+	}
+
+	assert.Equal(t, []string{"hello Ann", "hello Ben", "hello Cid"}, names)
+}
+
 func TestMockServerBidiStreamingSuccess(t *testing.T) {
 	cc, err := grpc.Dial(testAddr, grpc.WithInsecure())
 	if err != nil {
@@ -160,6 +219,96 @@ func TestMockServerBidiStreamingError(t *testing.T) {
 	}
 }
 
+func TestMockServerUnaryNotMatched(t *testing.T) {
+	cc, err := grpc.Dial(testAddr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := greet.NewGreeterClient(cc)
+	_, err = client.SayHello(context.Background(), &greet.HelloRequest{
+		Name: "Alice",
+	})
+
+	require.Error(t, err)
+	errStatus, _ := status.FromError(err)
+	assert.Equal(t, codes.Unimplemented, errStatus.Code())
+	assert.Contains(t, errStatus.Message(), `expected eq "Bob", got "Alice"`)
+
+	require.Len(t, errStatus.Details(), 1)
+	details, ok := errStatus.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, details.GetFieldViolations(), 2)
+	assert.Equal(t, "$.name", details.GetFieldViolations()[0].GetField())
+
+	var entry *dittomock.JournalEntry
+	for _, e := range testJournal.Entries() {
+		if e.Method == "/greet.Greeter/SayHello" {
+			entry = &e
+		}
+	}
+	require.NotNil(t, entry)
+	assert.JSONEq(t, `{"name": "Alice"}`, string(entry.Body))
+	assert.Len(t, entry.Candidates, 2)
+}
+
+func TestMockingServiceClearJournal(t *testing.T) {
+	matcher, err := dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{greetMock()}))
+	require.NoError(t, err)
+	journal := dittomock.NewJournal[dittomock.JournalEntry](10)
+	journal.Record(dittomock.JournalEntry{Method: "/greet.Greeter/SayHello"})
+
+	svc := services.NewMockingService(matcher, nil, journal, nil, nil, nil, logger.NewLogger())
+	_, err = svc.Clear(context.Background(), &api.ClearRequest{})
+	require.NoError(t, err)
+
+	assert.Empty(t, matcher.Mocks())
+	assert.Empty(t, journal.Entries())
+}
+
+func TestMockServerUnaryCELMetadata(t *testing.T) {
+	log := logger.NewLogger()
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+		logger: log,
+	}
+	s.matcher, err = dittomock.NewRequestMatcher(
+		dittomock.WithMocks([]dittomock.DittoMock{greetCELMock()}),
+		dittomock.WithLogger(log),
+		dittomock.WithMethodResolver(s.findMethodByName),
+	)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+	defer stopTestServer(server)
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := greet.NewGreeterClient(cc)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-features", "beta")
+	reply, err := client.SayHello(ctx, &greet.HelloRequest{
+		Name: "Celine",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello beta tester", reply.GetMessage())
+
+	_, err = client.SayHello(context.Background(), &greet.HelloRequest{
+		Name: "Celine",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
 func createListener(server *grpc.Server) (*grpc.Server, string, error) {
 	port := 0
 	if l, err := net.Listen("tcp", "127.0.0.1:0"); err != nil {
@@ -223,6 +372,24 @@ func greetMock() dittomock.DittoMock {
 	}
 }
 
+func greetCELMock() dittomock.DittoMock {
+	return dittomock.DittoMock{
+		Request: &dittomock.DittoRequest{
+			Method: "/greet.Greeter/SayHello",
+			BodyPatterns: []dittomock.DittoBodyPattern{
+				{
+					MatchesCEL: "request.name.startsWith('Ce') && 'beta' in metadata['x-features']",
+				},
+			},
+		},
+		Response: []*dittomock.DittoResponse{
+			{
+				Body: []byte(`{ "message": "hello beta tester" }`),
+			},
+		},
+	}
+}
+
 func helloStreamMock() dittomock.DittoMock {
 	return dittomock.DittoMock{
 		Request: &dittomock.DittoRequest{
@@ -319,22 +486,59 @@ func helloBidiStreamMockErr() dittomock.DittoMock {
 	}
 }
 
-func startTestServer() (*grpc.Server, string, error) {
-	log := logger.NewLogger()
-	requestMatcher, err := dittomock.NewRequestMatcher(
-		dittomock.WithMocks([]dittomock.DittoMock{
-			greetMock(),
-			greetNotFoundMock(),
-			helloStreamMock(),
-			helloBidiStreamMock(),
-			helloBidiStreamMockErr(),
-		}),
-		dittomock.WithLogger(log),
-	)
+// helloGeneratorMock sends 3 generated messages, generators are parsed by the loader
+func helloGeneratorMock() dittomock.DittoMock {
+	mock, err := dittomock.FromProto(&api.DittoMock{
+		Request: &api.DittoRequest{
+			Method: "/ditto.example.HelloService/Hello",
+			BodyPatterns: []*api.DittoBodyPattern{
+				{Pattern: &api.DittoBodyPattern_MatchesJsonpath{MatchesJsonpath: &api.JSONPathPattern{
+					Expression: "$.name",
+					Operator:   &api.JSONPathPattern_Eq{Eq: "generated"},
+				}}},
+			},
+		},
+		Response: []*api.DittoResponse{
+			{Response: &api.DittoResponse_Generate{Generate: &api.ResponseGenerator{
+				Source:       &api.ResponseGenerator_Repeat{Repeat: 3},
+				BodyTemplate: `name: "{{add .Index 1}} of {{.Count}} for {{.Request.name}}"`,
+				Interval:     "1ms",
+			}}},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return mock
+}
 
+// helloBidiGeneratorMock replies to every message of the client stream
+func helloBidiGeneratorMock() dittomock.DittoMock {
+	mock, err := dittomock.FromProto(&api.DittoMock{
+		Request: &api.DittoRequest{
+			Method: "/ditto.example.HelloService/HelloMulti",
+			BodyPatterns: []*api.DittoBodyPattern{
+				{Pattern: &api.DittoBodyPattern_MatchesJsonpath{MatchesJsonpath: &api.JSONPathPattern{
+					Expression: "$[0].name",
+					Operator:   &api.JSONPathPattern_Eq{Eq: "Ann"},
+				}}},
+			},
+		},
+		Response: []*api.DittoResponse{
+			{Response: &api.DittoResponse_Generate{Generate: &api.ResponseGenerator{
+				Source:       &api.ResponseGenerator_Foreach{Foreach: "$"},
+				BodyTemplate: `{ "name": "hello {{.Item.name}}" }`,
+			}}},
+		},
+	})
 	if err != nil {
-		return nil, "", err
+		panic(err)
 	}
+	return mock
+}
+
+func startTestServer() (*grpc.Server, string, error) {
+	log := logger.NewLogger()
 
 	greetDescr, err := findFileDescriptor("greet.proto")
 	if err != nil {
@@ -349,8 +553,27 @@ func startTestServer() (*grpc.Server, string, error) {
 	s := &mockServer{
 		descrs:  []*desc.FileDescriptor{greetDescr, helloDescr},
 		logger:  log,
-		matcher: requestMatcher,
+		journal: testJournal,
+	}
+
+	requestMatcher, err := dittomock.NewRequestMatcher(
+		dittomock.WithMocks([]dittomock.DittoMock{
+			greetMock(),
+			greetNotFoundMock(),
+			helloStreamMock(),
+			helloBidiStreamMock(),
+			helloBidiStreamMockErr(),
+			helloGeneratorMock(),
+			helloBidiGeneratorMock(),
+		}),
+		dittomock.WithLogger(log),
+		dittomock.WithMethodResolver(s.findMethodByName),
+	)
+
+	if err != nil {
+		return nil, "", err
 	}
+	s.matcher = requestMatcher
 
 	server := grpc.NewServer()
 	for _, mockService := range s.serviceDescriptors() {
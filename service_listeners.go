@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// serviceListener serves a subset of mock services on its own address,
+// so that one server impersonates several backends
+type serviceListener struct {
+	// patterns are globs of fully qualified service names like users.UserService or users.*
+	patterns []string
+	addr     string
+}
+
+// parseServiceListener parses SERVICE[,SERVICE...]=ADDR
+func parseServiceListener(spec string) (serviceListener, error) {
+	services, addr, ok := strings.Cut(spec, "=")
+	if !ok || services == "" || addr == "" {
+		return serviceListener{}, fmt.Errorf("invalid service listener %q, expected SERVICE[,SERVICE...]=ADDR", spec)
+	}
+
+	l := serviceListener{addr: addr}
+	for _, p := range strings.Split(services, ",") {
+		p = strings.TrimSpace(p)
+		if _, err := path.Match(p, ""); err != nil || p == "" {
+			return serviceListener{}, fmt.Errorf("invalid service pattern %q in %q", p, spec)
+		}
+		l.patterns = append(l.patterns, p)
+	}
+
+	return l, nil
+}
+
+func (l serviceListener) matches(service string) bool {
+	for _, p := range l.patterns {
+		if ok, _ := path.Match(p, service); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// newServiceServer creates a server of the mock services matching the listener, the server has its own
+// reflection and health service that reports the services as serving, calls of other services
+// are answered like unmatched requests
+func (s *mockServer) newServiceServer(l serviceListener, opts ...grpc.ServerOption) (*grpc.Server, *health.Server, error) {
+	server := grpc.NewServer(append(opts, grpc.UnknownServiceHandler(s.unknownHandler))...)
+	healthServer := health.NewServer()
+
+	var registered []string
+	for _, mockService := range s.serviceDescriptors() {
+		// health mocks are replaced by the health status of the listener
+		if mockService.ServiceName == grpc_health_v1.Health_ServiceDesc.ServiceName || !l.matches(mockService.ServiceName) {
+			continue
+		}
+
+		s.logger.Infow("register mock service", "service", mockService.ServiceName, "listen", l.addr)
+		server.RegisterService(mockService, s)
+		healthServer.SetServingStatus(mockService.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+		registered = append(registered, mockService.ServiceName)
+	}
+
+	if len(registered) == 0 {
+		return nil, nil, errors.New("no services match " + strings.Join(l.patterns, ","))
+	}
+
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server, healthServer, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/testdata/greet"
+	"github.com/vadimi/grpc-ditto/testdata/hello"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestServiceListener(t *testing.T) {
+	log := logger.NewLogger()
+
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+	healthDescr, err := healthCheckFileDescriptor()
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr, helloDescr, healthDescr},
+		logger: log,
+	}
+	s.matcher, err = dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{greetMock(), healthCheckMocks()}), dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	l, err := parseServiceListener("greet.*, other.Service=127.0.0.1:0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"greet.*", "other.Service"}, l.patterns)
+
+	server, _, err := s.newServiceServer(l)
+	require.NoError(t, err)
+	assert.Contains(t, server.GetServiceInfo(), "greet.Greeter")
+	assert.NotContains(t, server.GetServiceInfo(), "ditto.example.HelloService")
+	assert.Contains(t, server.GetServiceInfo(), "grpc.reflection.v1.ServerReflection")
+
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+	defer stopTestServer(server)
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	resp, err := greet.NewGreeterClient(cc).SayHello(context.Background(), &greet.HelloRequest{Name: "Bob"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetMessage())
+
+	// services that are not mapped to the listener are answered like unmatched requests
+	stream, err := hello.NewHelloServiceClient(cc).Hello(context.Background(), &hello.HelloRequest{Name: "x"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "unimplemented mock for method: /ditto.example.HelloService/Hello")
+
+	healthClient := grpc_health_v1.NewHealthClient(cc)
+	check, err := healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "greet.Greeter"})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check.GetStatus())
+
+	_, err = healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "ditto.example.HelloService"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, _, err = s.newServiceServer(serviceListener{patterns: []string{"users.*"}, addr: ":0"})
+	assert.ErrorContains(t, err, "no services match users.*")
+
+	for _, spec := range []string{"greet.Greeter", "=:50051", "greet.Greeter=", "[=:50051"} {
+		_, err := parseServiceListener(spec)
+		assert.Error(t, err, spec)
+	}
+}
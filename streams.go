@@ -0,0 +1,103 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// lockedStream serializes sends of the stream handler and messages pushed using admin api
+type lockedStream struct {
+	grpc.ServerStream
+	mu sync.Mutex
+	// closed is set when the handler returns, the stream can't be used after that
+	closed bool
+}
+
+func (s *lockedStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ServerStream.SendMsg(m)
+}
+
+// push sends the message pushed using admin api unless the handler has returned
+func (s *lockedStream) push(m interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return dittomock.ErrStreamNotFound
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *lockedStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// registerStream makes the stream available to admin api,
+// the returned stream has to be used for sending messages until unregister is called when the handler returns
+func (s *mockServer) registerStream(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, md metadata.MD, inputJS []byte) (grpc.ServerStream, *dittomock.OpenStream, func()) {
+	locked := &lockedStream{ServerStream: stream}
+	encode := func(body []byte) (interface{}, error) {
+		output := dynamic.NewMessage(methodDesc.GetOutputType())
+		if err := output.UnmarshalJSON(body); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid message for %s: %s", methodDesc.GetOutputType().GetFullyQualifiedName(), err)
+		}
+		return output, nil
+	}
+
+	fullMethodName, _ := grpc.Method(stream.Context())
+	open := s.streams.Open(fullMethodName, md, inputJS, encode, locked.push)
+	unregister := func() {
+		locked.close()
+		s.streams.Remove(open)
+	}
+
+	return locked, open, unregister
+}
+
+// keepOpen holds server stream open after the mock responses are sent and sends heartbeat messages
+// until the client cancels the stream, admin api closes it or the server stops
+func (s *mockServer) keepOpen(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, opts *dittomock.StreamOptions, inputJS []byte, closed <-chan *status.Status) error {
+	ctx := stream.Context()
+
+	var ticks <-chan time.Time
+	if opts.Heartbeat != nil {
+		ticker := time.NewTicker(opts.Heartbeat.Interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for i := 0; ; {
+		select {
+		case <-s.done:
+			return nil
+		case st := <-closed:
+			s.logger.Debugw("stream closed by admin api", "code", st.Code())
+			return st.Err()
+		case <-ctx.Done():
+			s.logger.Debugw("stream closed by client", "err", ctx.Err())
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticks:
+			body, err := opts.Heartbeat.Render(i, inputJS)
+			if err != nil {
+				s.logger.Error(err)
+				return err
+			}
+			i++
+
+			if err := s.sendBody(stream, methodDesc, body); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/api"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/internal/services"
+	"github.com/vadimi/grpc-ditto/testdata/hello"
+	apicode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMockServerStreamHeartbeat(t *testing.T) {
+	addr, _, stop := startStreamTestServer(t)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := helloStream(ctx, t, addr, "watch")
+
+	var names []string
+	for len(names) < 4 {
+		msg, err := stream.Recv()
+		require.NoError(t, err)
+		names = append(names, msg.GetName())
+	}
+	assert.Equal(t, []string{"hello watch", "tick 0", "tick 1", "tick 2"}, names)
+
+	cancel()
+	_, err := stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestMockServerStreamKeepOpenUntilStop(t *testing.T) {
+	addr, s, stop := startStreamTestServer(t)
+	defer stop()
+
+	stream := helloStream(context.Background(), t, addr, "open")
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "hello open", msg.GetName())
+
+	received := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		received <- err
+	}()
+
+	select {
+	case err := <-received:
+		t.Fatalf("stream is expected to stay open, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(s.done)
+	assert.Equal(t, io.EOF, <-received)
+}
+
+func TestMockingServicePushMessage(t *testing.T) {
+	addr, _, stop := startStreamTestServer(t)
+	defer stop()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user", "bob")
+	bob := helloStream(ctx, t, addr, "open")
+	alice := helloStream(metadata.AppendToOutgoingContext(context.Background(), "x-user", "alice"), t, addr, "open")
+	for _, stream := range []hello.HelloService_HelloClient{bob, alice} {
+		_, err := stream.Recv()
+		require.NoError(t, err)
+	}
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+	admin := api.NewMockingServiceClient(cc)
+
+	streams, err := admin.ListStreams(context.Background(), &api.ListStreamsRequest{})
+	require.NoError(t, err)
+	require.Len(t, streams.GetStreams(), 2)
+	assert.Equal(t, "/ditto.example.HelloService/Hello", streams.GetStreams()[0].GetMethod())
+	assert.Equal(t, []string{"bob"}, streams.GetStreams()[0].GetMetadata()["x-user"].GetValues())
+	assert.Equal(t, "open", streams.GetStreams()[0].GetBody().GetStructValue().GetFields()["name"].GetStringValue())
+
+	bobSelector := &api.StreamSelector{
+		Method:   "/ditto.example.HelloService/Hello",
+		Metadata: map[string]string{"x-user": "bob"},
+	}
+
+	body, err := structpb.NewStruct(map[string]interface{}{"name": "pushed"})
+	require.NoError(t, err)
+	pushed, err := admin.PushMessage(context.Background(), &api.PushMessageRequest{Selector: bobSelector, Body: body})
+	require.NoError(t, err)
+	assert.Equal(t, []string{streams.GetStreams()[0].GetId()}, pushed.GetStreamIds())
+
+	msg, err := bob.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "pushed", msg.GetName())
+
+	invalid, err := structpb.NewStruct(map[string]interface{}{"unknown": 1})
+	require.NoError(t, err)
+	_, err = admin.PushMessage(context.Background(), &api.PushMessageRequest{Selector: bobSelector, Body: invalid})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = admin.PushMessage(context.Background(), &api.PushMessageRequest{Body: body})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = admin.PushMessage(context.Background(), &api.PushMessageRequest{Selector: &api.StreamSelector{StreamId: "404"}, Body: body})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = admin.CloseStream(context.Background(), &api.CloseStreamRequest{
+		Selector: bobSelector,
+		Status:   &api.RpcStatus{Code: apicode.Code_NOT_FOUND, Message: "gone"},
+	})
+	require.NoError(t, err)
+
+	_, err = bob.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = admin.CloseStream(context.Background(), &api.CloseStreamRequest{Selector: &api.StreamSelector{StreamId: streams.GetStreams()[1].GetId()}})
+	require.NoError(t, err)
+
+	_, err = alice.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	require.Eventually(t, func() bool {
+		streams, err := admin.ListStreams(context.Background(), &api.ListStreamsRequest{})
+		return err == nil && len(streams.GetStreams()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLockedStreamPushAfterHandlerReturns(t *testing.T) {
+	locked := &lockedStream{}
+	locked.close()
+
+	err := locked.push(&hello.HelloResponse{Name: "late"})
+	assert.ErrorIs(t, err, dittomock.ErrStreamNotFound)
+}
+
+func helloStream(ctx context.Context, t *testing.T, addr, name string) hello.HelloService_HelloClient {
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { cc.Close() })
+
+	stream, err := hello.NewHelloServiceClient(cc).Hello(ctx, &hello.HelloRequest{Name: name})
+	require.NoError(t, err)
+	return stream
+}
+
+// startStreamTestServer starts a server with mocks that keep hello streams open
+func startStreamTestServer(t *testing.T) (string, *mockServer, func()) {
+	log := logger.NewLogger()
+
+	helloDescr, err := findFileDescriptor("hello.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs:  []*desc.FileDescriptor{helloDescr},
+		logger:  log,
+		done:    make(chan struct{}),
+		streams: dittomock.NewStreamRegistry(),
+		faults:  dittomock.NewFaultInjector(1, nil),
+
+		cancellations: dittomock.NewJournal[dittomock.CanceledEntry](10),
+	}
+
+	var mocks []dittomock.DittoMock
+	for name, opts := range map[string]*api.StreamOptions{
+		"watch": {Heartbeat: &api.Heartbeat{Interval: "10ms", BodyTemplate: `name: "tick {{.Index}}"`}},
+		"open":  {KeepOpen: true},
+	} {
+		mock, err := dittomock.FromProto(&api.DittoMock{
+			Request: &api.DittoRequest{
+				Method: "/ditto.example.HelloService/Hello",
+				BodyPatterns: []*api.DittoBodyPattern{
+					{Pattern: &api.DittoBodyPattern_MatchesJsonpath{MatchesJsonpath: &api.JSONPathPattern{
+						Expression: "$.name",
+						Operator:   &api.JSONPathPattern_Eq{Eq: name},
+					}}},
+				},
+			},
+			Response: []*api.DittoResponse{
+				{Response: &api.DittoResponse_BodyTemplate{BodyTemplate: `name: "hello ` + name + `"`}},
+			},
+			Stream: opts,
+		})
+		require.NoError(t, err)
+		mocks = append(mocks, mock)
+	}
+
+	s.matcher, err = dittomock.NewRequestMatcher(dittomock.WithMocks(mocks), dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	s.conns = newConnTracker()
+	server := grpc.NewServer(grpc.Creds(s.conns.wrap(nil)))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	api.RegisterMockingServiceServer(server, services.NewMockingService(s.matcher, nil, nil, s.cancellations, s.streams, s.faults, log))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+
+	return lis.Addr().String(), s, func() { stopTestServer(server) }
+}
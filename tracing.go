@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vadimi/grpc-ditto/internal/logger"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "github.com/vadimi/grpc-ditto"
+
+// newTraceExporter creates span exporter from the spec: otlp, stdout or file:path,
+// otlp exporter sends spans to the collector endpoint using grpc without tls
+func newTraceExporter(ctx context.Context, spec, endpoint string) (sdktrace.SpanExporter, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "otlp":
+		return otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		if arg == "" {
+			return nil, fmt.Errorf("file path is required: %s", spec)
+		}
+		f, err := os.OpenFile(arg, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &fileExporter{SpanExporter: exporter, file: f}, nil
+	}
+
+	return nil, fmt.Errorf("unknown trace exporter %q, otlp, stdout or file:path are supported", spec)
+}
+
+// fileExporter closes the file when the exporter is shut down
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
+
+func newTracerProvider(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("grpc-ditto"))),
+	)
+}
+
+type serverTracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	// isMock tells if the method is served by mocks, admin api and reflection calls are not traced
+	isMock func(method string) bool
+	// echo sends trace context of the call back in response headers
+	echo bool
+	log  logger.Logger
+}
+
+func newServerTracing(provider trace.TracerProvider, isMock func(method string) bool, echo bool, log logger.Logger) *serverTracing {
+	return &serverTracing{
+		tracer:     provider.Tracer(tracerName),
+		propagator: propagation.TraceContext{},
+		isMock:     isMock,
+		echo:       echo,
+		log:        log,
+	}
+}
+
+// streamInterceptor starts server span of the mock call continuing W3C trace context from the request metadata
+func (t *serverTracing) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !t.isMock(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	ctx := t.propagator.Extract(ss.Context(), metadataCarrier(md))
+
+	service, method := splitMethodName(info.FullMethod)
+	ctx, span := t.tracer.Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(service),
+			semconv.RPCMethod(method),
+		),
+	)
+	defer span.End()
+
+	if t.echo {
+		header := metadata.MD{}
+		t.propagator.Inject(ctx, metadataCarrier(header))
+		if err := ss.SetHeader(header); err != nil {
+			t.log.Warnw("trace context header", "err", err)
+		}
+	}
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+
+	return err
+}
+
+// startSpan starts a child span of the call, spans are not recorded if tracing is disabled
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan records the error if it's set and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", "unknown"
+}
+
+// metadataCarrier adapts grpc metadata to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
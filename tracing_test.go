@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/testdata/greet"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+func TestMockServerTracing(t *testing.T) {
+	log := logger.NewLogger()
+
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+
+	s := &mockServer{
+		descrs: []*desc.FileDescriptor{greetDescr},
+		logger: log,
+	}
+
+	bob := greetMock()
+	bob.ID = "bob"
+	s.matcher, err = dittomock.NewRequestMatcher(dittomock.WithMocks([]dittomock.DittoMock{bob}), dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracing := newServerTracing(provider, func(method string) bool { return s.findMethodByName(method) != nil }, true, log)
+
+	server := grpc.NewServer(grpc.ChainStreamInterceptor(tracing.streamInterceptor))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+	reflection.Register(server)
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+	defer stopTestServer(server)
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-"+traceID+"-"+spanID+"-01")
+
+	var header metadata.MD
+	_, err = greet.NewGreeterClient(cc).SayHello(ctx, &greet.HelloRequest{Name: "Bob"}, grpc.Header(&header))
+	require.NoError(t, err)
+
+	require.Len(t, header.Get("traceparent"), 1)
+	assert.Contains(t, header.Get("traceparent")[0], traceID)
+
+	info, err := grpc_reflection_v1.NewServerReflectionClient(cc).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, info.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	}))
+	_, err = info.Recv()
+	require.NoError(t, err)
+	require.NoError(t, info.CloseSend())
+	_, err = info.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		assert.Equal(t, traceID, span.SpanContext.TraceID().String())
+		spans[span.Name] = span
+	}
+	require.Contains(t, spans, "greet.Greeter/SayHello")
+	require.Contains(t, spans, "receive")
+	require.Contains(t, spans, "match")
+	require.Contains(t, spans, "send")
+	assert.NotContains(t, spans, "grpc.reflection.v1.ServerReflection/ServerReflectionInfo")
+
+	call := spans["greet.Greeter/SayHello"]
+	assert.Equal(t, trace.SpanKindServer, call.SpanKind)
+	assert.Equal(t, spanID, call.Parent.SpanID().String())
+	assert.True(t, call.Parent.IsRemote())
+
+	for _, name := range []string{"receive", "match", "send"} {
+		assert.Equal(t, call.SpanContext.SpanID(), spans[name].Parent.SpanID(), name)
+	}
+	assert.Contains(t, spans["match"].Attributes, attribute.String("ditto.mock_id", "bob"))
+	assert.Contains(t, spans["match"].Attributes, attribute.Bool("ditto.matched", true))
+}
+
+func TestNewTraceExporter(t *testing.T) {
+	_, err := newTraceExporter(context.Background(), "jaeger", "")
+	assert.ErrorContains(t, err, "unknown trace exporter")
+
+	_, err = newTraceExporter(context.Background(), "file:", "")
+	assert.ErrorContains(t, err, "file path is required")
+
+	exporter, err := newTraceExporter(context.Background(), "file:"+filepath.Join(t.TempDir(), "traces.json"), "")
+	require.NoError(t, err)
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+
+	_, err = exporter.(*fileExporter).file.WriteString("{}")
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
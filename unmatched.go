@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/mockgen"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	apicode "google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// unmatchedStatus returns an error status, it's the default behavior
+	unmatchedStatus = "status"
+	// unmatchedBody returns the provided response body
+	unmatchedBody = "body"
+	// unmatchedGenerate returns an example response generated from the output message
+	unmatchedGenerate = "generate"
+	// unmatchedProxy forwards the request to a real service
+	unmatchedProxy = "proxy"
+)
+
+// unmatchedPolicy defines the response for requests that don't match any mock
+type unmatchedPolicy struct {
+	Mode    string
+	Code    codes.Code
+	Message string
+	Body    json.RawMessage
+	Proxy   *proxy
+}
+
+func defaultUnmatchedPolicy() unmatchedPolicy {
+	return unmatchedPolicy{
+		Mode: unmatchedStatus,
+		Code: codes.Unimplemented,
+	}
+}
+
+// parseUnmatchedPolicy parses policy in the form of mode[:argument]:
+//
+//	status[:CODE[:message]]  e.g. status:NOT_FOUND:mock not found
+//	body[:json]              e.g. body:{} for an empty successful response
+//	generate                 respond with an example generated from the output message
+//	proxy:address            forward the request to a real service
+func parseUnmatchedPolicy(spec string) (unmatchedPolicy, error) {
+	policy := defaultUnmatchedPolicy()
+	mode, arg, _ := strings.Cut(spec, ":")
+	policy.Mode = strings.ToLower(strings.TrimSpace(mode))
+
+	switch policy.Mode {
+	case unmatchedStatus:
+		if arg == "" {
+			return policy, nil
+		}
+		codeName, msg, _ := strings.Cut(arg, ":")
+		code, err := parseStatusCode(codeName)
+		if err != nil {
+			return policy, err
+		}
+		policy.Code = code
+		policy.Message = msg
+	case unmatchedBody:
+		if strings.TrimSpace(arg) == "" {
+			arg = "{}"
+		}
+		body, err := yaml.YAMLToJSON([]byte(arg))
+		if err != nil {
+			return policy, fmt.Errorf("invalid unmatched response body: %w", err)
+		}
+		policy.Body = body
+	case unmatchedGenerate:
+	case unmatchedProxy:
+		if arg == "" {
+			return policy, errors.New("proxy address is required")
+		}
+		policy.Proxy = newProxy(arg)
+	default:
+		return policy, fmt.Errorf("unknown unmatched mode %q, supported modes: status, body, generate, proxy", mode)
+	}
+
+	return policy, nil
+}
+
+// parseStatusCode accepts grpc code names like NOT_FOUND and numbers, the code must be an error code
+func parseStatusCode(s string) (codes.Code, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	code, ok := apicode.Code_value[s]
+	if !ok {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || codes.Code(n) > codes.Unauthenticated {
+			return codes.Unknown, fmt.Errorf("unknown status code %q", s)
+		}
+		code = int32(n)
+	}
+
+	if codes.Code(code) == codes.OK {
+		return codes.Unknown, errors.New("status code must not be OK")
+	}
+
+	return codes.Code(code), nil
+}
+
+// unmatchedPolicies keeps server wide and per service (or method) unmatched policies
+type unmatchedPolicies struct {
+	defaults unmatchedPolicy
+	// services are keyed by fully qualified service name or by method name like /greet.Greeter/SayHello
+	services map[string]unmatchedPolicy
+}
+
+func newUnmatchedPolicies() *unmatchedPolicies {
+	return &unmatchedPolicies{
+		defaults: defaultUnmatchedPolicy(),
+		services: map[string]unmatchedPolicy{},
+	}
+}
+
+// parseServicePolicy parses service=policy pair
+func (p *unmatchedPolicies) parseServicePolicy(s string) error {
+	name, spec, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid unmatched service policy %q, expected service=policy", s)
+	}
+
+	policy, err := parseUnmatchedPolicy(spec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	p.services[strings.TrimSpace(name)] = policy
+	return nil
+}
+
+// validate checks that body policies can be converted to the output message of every method they apply to
+func (p *unmatchedPolicies) validate(descrs []*desc.FileDescriptor) error {
+	var errs []error
+	for _, d := range descrs {
+		for _, service := range d.GetServices() {
+			for _, m := range service.GetMethods() {
+				fullMethodName := "/" + service.GetFullyQualifiedName() + "/" + m.GetName()
+				policy := p.policy(fullMethodName)
+				if policy.Mode != unmatchedBody {
+					continue
+				}
+
+				output := dynamic.NewMessage(m.GetOutputType())
+				if err := output.UnmarshalJSON(policy.Body); err != nil {
+					errs = append(errs, fmt.Errorf("invalid unmatched response body for method %s: %w", fullMethodName, err))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *unmatchedPolicies) policy(fullMethodName string) unmatchedPolicy {
+	if p == nil {
+		return defaultUnmatchedPolicy()
+	}
+
+	if policy, ok := p.services[fullMethodName]; ok {
+		return policy
+	}
+
+	if policy, ok := p.services[serviceName(fullMethodName)]; ok {
+		return policy
+	}
+
+	return p.defaults
+}
+
+// respondUnmatched applies the policy to the request that didn't match any mock,
+// notMatched is nil if the method is not known
+func (s *mockServer) respondUnmatched(stream grpc.ServerStream, fullMethodName string, methodDesc *desc.MethodDescriptor, inputJS []byte, notMatched *dittomock.NotMatchedError) error {
+	policy := s.unmatched.policy(fullMethodName)
+
+	switch policy.Mode {
+	case unmatchedBody:
+		if methodDesc == nil {
+			return sendEmpty(stream, fullMethodName, policy)
+		}
+		return sendJSON(stream, methodDesc, policy.Body)
+	case unmatchedGenerate:
+		if methodDesc == nil {
+			break
+		}
+		body, err := json.Marshal(mockgen.SampleMessage(methodDesc.GetOutputType(), 1))
+		if err != nil {
+			return err
+		}
+		return sendJSON(stream, methodDesc, body)
+	case unmatchedProxy:
+		s.logger.Infow("proxy unmatched request", "method", fullMethodName, "address", policy.Proxy.addr)
+		if methodDesc == nil {
+			return policy.Proxy.forwardRaw(stream, fullMethodName)
+		}
+		return policy.Proxy.forward(stream, fullMethodName, methodDesc, inputJS)
+	}
+
+	return unmatchedErr(fullMethodName, policy, notMatched)
+}
+
+// unmatchedErr explains why the closest mocks didn't match in the status message and details
+func unmatchedErr(fullMethodName string, policy unmatchedPolicy, notMatched *dittomock.NotMatchedError) error {
+	code := policy.Code
+	if policy.Mode != unmatchedStatus {
+		code = codes.Unimplemented
+	}
+
+	msg := policy.Message
+	if msg == "" {
+		msg = fmt.Sprintf("unimplemented mock for method: %s", fullMethodName)
+		if notMatched != nil {
+			msg += ", " + notMatched.Summary()
+		}
+	}
+
+	st := status.New(code, msg)
+	if notMatched == nil {
+		return st.Err()
+	}
+
+	return withMismatchDetails(st, notMatched).Err()
+}
+
+// sendJSON sends the json body as the method response message
+func sendJSON(stream grpc.ServerStream, methodDesc *desc.MethodDescriptor, body []byte) error {
+	output := dynamic.NewMessage(methodDesc.GetOutputType())
+	if err := output.UnmarshalJSON(body); err != nil {
+		return status.Errorf(codes.Internal, "invalid unmatched response body: %s", err)
+	}
+
+	return stream.SendMsg(output)
+}
+
+// sendEmpty sends an empty message for methods without loaded proto files,
+// empty message has the same binary representation for any message type
+func sendEmpty(stream grpc.ServerStream, fullMethodName string, policy unmatchedPolicy) error {
+	var body map[string]interface{}
+	if err := json.Unmarshal(policy.Body, &body); err != nil || len(body) > 0 {
+		return status.Errorf(codes.Unimplemented, "unimplemented mock for method: %s", fullMethodName)
+	}
+
+	// the request has to be read before responding
+	for {
+		if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+			break
+		}
+	}
+
+	return stream.SendMsg(&emptypb.Empty{})
+}
+
+// serviceName extracts service name from fully qualified method name
+func serviceName(fullMethodName string) string {
+	idx := strings.LastIndex(fullMethodName, "/")
+	if idx < 0 {
+		return fullMethodName
+	}
+	return strings.Trim(fullMethodName[0:idx], "/")
+}
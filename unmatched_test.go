@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+	"github.com/vadimi/grpc-ditto/internal/logger"
+	"github.com/vadimi/grpc-ditto/testdata/greet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseUnmatchedPolicy(t *testing.T) {
+	tests := []struct {
+		spec    string
+		mode    string
+		code    codes.Code
+		message string
+		body    string
+		err     bool
+	}{
+		{spec: "status", mode: unmatchedStatus, code: codes.Unimplemented},
+		{spec: "status:NOT_FOUND", mode: unmatchedStatus, code: codes.NotFound},
+		{spec: "status:not_found:no mock: sorry", mode: unmatchedStatus, code: codes.NotFound, message: "no mock: sorry"},
+		{spec: "status:14", mode: unmatchedStatus, code: codes.Unavailable},
+		{spec: "status:BAD", err: true},
+		{spec: "status:42", err: true},
+		{spec: "status:OK", err: true},
+		{spec: "status:0", err: true},
+		{spec: "body", mode: unmatchedBody, code: codes.Unimplemented, body: `{}`},
+		{spec: `body:{"message": "hi"}`, mode: unmatchedBody, code: codes.Unimplemented, body: `{"message": "hi"}`},
+		{spec: "generate", mode: unmatchedGenerate, code: codes.Unimplemented},
+		{spec: "proxy", err: true},
+		{spec: "fail", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			policy, err := parseUnmatchedPolicy(tt.spec)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, policy.Mode)
+			assert.Equal(t, tt.code, policy.Code)
+			assert.Equal(t, tt.message, policy.Message)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, string(policy.Body))
+			}
+		})
+	}
+}
+
+func TestUnmatchedPoliciesLookup(t *testing.T) {
+	policies := newUnmatchedPolicies()
+	require.NoError(t, policies.parseServicePolicy("greet.Greeter=generate"))
+	require.NoError(t, policies.parseServicePolicy("/greet.Greeter/SayHello=status:NOT_FOUND"))
+	assert.Error(t, policies.parseServicePolicy("greet.Greeter"))
+	assert.ErrorContains(t, policies.parseServicePolicy("greet.Greeter=status:OK"), "must not be OK")
+
+	assert.Equal(t, codes.NotFound, policies.policy("/greet.Greeter/SayHello").Code)
+	assert.Equal(t, unmatchedGenerate, policies.policy("/greet.Greeter/SayBye").Mode)
+	assert.Equal(t, unmatchedStatus, policies.policy("/hello.Hello/Say").Mode)
+
+	var empty *unmatchedPolicies
+	assert.Equal(t, codes.Unimplemented, empty.policy("/greet.Greeter/SayHello").Code)
+}
+
+func TestUnmatchedPoliciesValidate(t *testing.T) {
+	greetDescr, err := findFileDescriptor("greet.proto")
+	require.NoError(t, err)
+	descrs := []*desc.FileDescriptor{greetDescr}
+
+	policies := newUnmatchedPolicies()
+	require.NoError(t, policies.parseServicePolicy(`greet.Greeter=body:{"message": "hi"}`))
+	assert.NoError(t, policies.validate(descrs))
+
+	require.NoError(t, policies.parseServicePolicy(`/greet.Greeter/SayHello=body:{"msg": "hi"}`))
+	assert.ErrorContains(t, policies.validate(descrs), "/greet.Greeter/SayHello")
+}
+
+func TestMockServerUnmatchedPolicies(t *testing.T) {
+	tests := []struct {
+		spec    string
+		code    codes.Code
+		message string
+	}{
+		{spec: "status:NOT_FOUND:no mock", code: codes.NotFound},
+		{spec: `body:{"message": "fallback"}`, message: "fallback"},
+		{spec: "generate", message: "message"},
+		{spec: "proxy:" + testAddr, message: "hello Bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			policy, err := parseUnmatchedPolicy(tt.spec)
+			require.NoError(t, err)
+
+			addr, stop := startUnmatchedTestServer(t, policy, true)
+			defer stop()
+
+			reply, err := sayHello(t, addr, "Bob")
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.message, reply.GetMessage())
+		})
+	}
+}
+
+func TestMockServerUnknownServiceProxy(t *testing.T) {
+	policy, err := parseUnmatchedPolicy("proxy:" + testAddr)
+	require.NoError(t, err)
+
+	addr, stop := startUnmatchedTestServer(t, policy, false)
+	defer stop()
+
+	reply, err := sayHello(t, addr, "Bob")
+	require.NoError(t, err)
+	assert.Equal(t, "hello Bob", reply.GetMessage())
+
+	_, err = sayHello(t, addr, "John")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMockServerUnknownServiceEmptyBody(t *testing.T) {
+	policy, err := parseUnmatchedPolicy("body")
+	require.NoError(t, err)
+
+	addr, stop := startUnmatchedTestServer(t, policy, false)
+	defer stop()
+
+	reply, err := sayHello(t, addr, "Bob")
+	require.NoError(t, err)
+	assert.Empty(t, reply.GetMessage())
+}
+
+func sayHello(t *testing.T, addr, name string) (*greet.HelloReply, error) {
+	cc, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { cc.Close() })
+
+	return greet.NewGreeterClient(cc).SayHello(context.Background(), &greet.HelloRequest{Name: name})
+}
+
+// startUnmatchedTestServer starts a server without mocks, greet service is unknown to the server if withProto is false
+func startUnmatchedTestServer(t *testing.T, policy unmatchedPolicy, withProto bool) (string, func()) {
+	log := logger.NewLogger()
+	requestMatcher, err := dittomock.NewRequestMatcher(dittomock.WithLogger(log))
+	require.NoError(t, err)
+
+	var descrs []*desc.FileDescriptor
+	if withProto {
+		greetDescr, err := findFileDescriptor("greet.proto")
+		require.NoError(t, err)
+		descrs = append(descrs, greetDescr)
+	}
+
+	policies := newUnmatchedPolicies()
+	policies.defaults = policy
+
+	s := &mockServer{
+		descrs:    descrs,
+		logger:    log,
+		matcher:   requestMatcher,
+		unmatched: policies,
+	}
+
+	server := grpc.NewServer(grpc.UnknownServiceHandler(s.unknownHandler))
+	for _, mockService := range s.serviceDescriptors() {
+		server.RegisterService(mockService, s)
+	}
+
+	_, addr, err := createListener(server)
+	require.NoError(t, err)
+
+	return addr, func() { stopTestServer(server) }
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/vadimi/grpc-ditto/internal/dittomock"
+
+	"github.com/urfave/cli"
+)
+
+func newValidateCmd() func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		if err := requireFlags(ctx, "mocks"); err != nil {
+			return err
+		}
+
+		descrs, err := parseProtoFiles(ctx)
+		if err != nil {
+			return err
+		}
+
+		healthcheckDescr, err := healthCheckFileDescriptor()
+		if err != nil {
+			return err
+		}
+		descrs = append(descrs, healthcheckDescr)
+
+		mockServer := &mockServer{
+			descrs: descrs,
+		}
+
+		validator := &mockValidator{
+			findMethodFunc: mockServer.findMethodByName,
+		}
+
+		mocks, errs := dittomock.ParseMockSources(mockSources(ctx))
+		errs = append(errs, validator.ValidateMocks(mocks)...)
+		sortMockErrors(errs)
+
+		problems := 0
+		for _, err := range errs {
+			if isWarning(err) {
+				fmt.Fprintf(ctx.App.Writer, "warning: %s\n", err)
+				if !ctx.Bool("strict") {
+					continue
+				}
+			} else {
+				fmt.Fprintln(ctx.App.Writer, err)
+			}
+			problems++
+		}
+
+		if problems > 0 {
+			return cli.NewExitError(fmt.Sprintf("%d problem(s) found", problems), 1)
+		}
+
+		fmt.Fprintf(ctx.App.Writer, "%d mock(s) are valid\n", len(mocks))
+		return nil
+	}
+}
+
+// sortMockErrors orders problems by file and mock index
+func sortMockErrors(errs []error) {
+	source := func(err error) dittomock.MockSource {
+		var mockErr *dittomock.MockError
+		if errors.As(err, &mockErr) {
+			return mockErr.Source
+		}
+		return dittomock.MockSource{Index: -1}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		si, sj := source(errs[i]), source(errs[j])
+		if si.File != sj.File {
+			return si.File < sj.File
+		}
+		return si.Index < sj.Index
+	})
+}